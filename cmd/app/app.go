@@ -219,6 +219,9 @@ func addAlphaFormulator(sp *vault.Vault, ctw *types.ContextWrapper, alphaPolicy
 	if err := ctw.CreateAccount(acc); err != nil {
 		panic(err)
 	}
+	if err := sp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+		panic(err)
+	}
 }
 
 func addSigmaFormulator(sp *vault.Vault, ctw *types.ContextWrapper, sigmaPolicy *formulator.SigmaPolicy, alphaPolicy *formulator.AlphaPolicy, KeyHash common.PublicHash, GenHash common.PublicHash, addr common.Address, name string) {
@@ -235,6 +238,9 @@ func addSigmaFormulator(sp *vault.Vault, ctw *types.ContextWrapper, sigmaPolicy
 	if err := ctw.CreateAccount(acc); err != nil {
 		panic(err)
 	}
+	if err := sp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+		panic(err)
+	}
 }
 
 func addHyperFormulator(sp *vault.Vault, ctw *types.ContextWrapper, hyperPolicy *formulator.HyperPolicy, Commission1000 uint32, KeyHash common.PublicHash, GenHash common.PublicHash, addr common.Address, name string) {
@@ -257,6 +263,9 @@ func addHyperFormulator(sp *vault.Vault, ctw *types.ContextWrapper, hyperPolicy
 	if err := ctw.CreateAccount(acc); err != nil {
 		panic(err)
 	}
+	if err := sp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+		panic(err)
+	}
 }
 
 func addStaking(fp *formulator.Formulator, ctw *types.ContextWrapper, HyperAddress common.Address, StakingAddress common.Address, am *amount.Amount) {
@@ -274,5 +283,7 @@ func addStaking(fp *formulator.Formulator, ctw *types.ContextWrapper, HyperAddre
 	} else {
 		frAcc.StakingAmount = frAcc.StakingAmount.Add(am)
 	}
-	fp.AddStakingAmount(ctw, HyperAddress, StakingAddress, am)
+	if err := fp.AddStakingAmount(ctw, HyperAddress, StakingAddress, am); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/formulator"
+	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+// DevAccount is a pre-funded account of the dev chain
+type DevAccount struct {
+	Address common.Address
+	KeyHash common.PublicHash
+	Amount  *amount.Amount
+}
+
+// DevApp is the application of the dev chain, the generator is an admin of all processes
+type DevApp struct {
+	*types.ApplicationBase
+	pm        types.ProcessManager
+	cn        types.Provider
+	generator common.Address
+	genHash   common.PublicHash
+	accounts  []*DevAccount
+}
+
+// NewDevApp returns a DevApp
+func NewDevApp(Generator common.Address, GenHash common.PublicHash, Accounts []*DevAccount) *DevApp {
+	return &DevApp{
+		generator: Generator,
+		genHash:   GenHash,
+		accounts:  Accounts,
+	}
+}
+
+// Name returns the name of the application
+func (app *DevApp) Name() string {
+	return "DevApp"
+}
+
+// Version returns the version of the application
+func (app *DevApp) Version() string {
+	return "v1.0.0"
+}
+
+// Init initializes the consensus
+func (app *DevApp) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
+	app.pm = pm
+	app.cn = cn
+	return nil
+}
+
+// InitGenesis initializes genesis data
+func (app *DevApp) InitGenesis(ctw *types.ContextWrapper) error {
+	alphaPolicy := &formulator.AlphaPolicy{
+		AlphaCreationLimitHeight:  5184000,
+		AlphaCreationAmount:       amount.NewCoinAmount(200000, 0),
+		AlphaUnlockRequiredBlocks: 2592000,
+	}
+
+	if p, err := app.pm.ProcessByName("fleta.admin"); err != nil {
+		return err
+	} else if ap, is := p.(*admin.Admin); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := ap.InitAdmin(ctw, map[string]common.Address{
+			"fleta.gateway":    app.generator,
+			"fleta.formulator": app.generator,
+			"fleta.payment":    app.generator,
+			"fleta.vault":      app.generator,
+		}); err != nil {
+			return err
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.formulator"); err != nil {
+		return err
+	} else if fp, is := p.(*formulator.Formulator); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := fp.InitPolicy(ctw,
+			&formulator.RewardPolicy{
+				RewardPerBlock:        amount.NewCoinAmount(0, 0),
+				PayRewardEveryBlocks:  172800,
+				AlphaEfficiency1000:   1000,
+				SigmaEfficiency1000:   1150,
+				OmegaEfficiency1000:   1300,
+				HyperEfficiency1000:   1300,
+				StakingEfficiency1000: 700,
+			},
+			alphaPolicy,
+			&formulator.SigmaPolicy{
+				SigmaRequiredAlphaBlocks:  5184000,
+				SigmaRequiredAlphaCount:   4,
+				SigmaUnlockRequiredBlocks: 2592000,
+			},
+			&formulator.OmegaPolicy{
+				OmegaRequiredSigmaBlocks:  5184000,
+				OmegaRequiredSigmaCount:   2,
+				OmegaUnlockRequiredBlocks: 2592000,
+			},
+			&formulator.HyperPolicy{
+				HyperCreationAmount:         amount.NewCoinAmount(5000000, 0),
+				HyperUnlockRequiredBlocks:   2592000,
+				StakingUnlockRequiredBlocks: 2592000,
+			},
+		); err != nil {
+			return err
+		}
+		if err := fp.InitStakingMap(ctw, []common.Address{}); err != nil {
+			return err
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.payment"); err != nil {
+		return err
+	} else if pp, is := p.(*payment.Payment); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := pp.InitTopics(ctw, []string{
+			"fleta.formulator.server.cost",
+		}); err != nil {
+			return err
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.gateway"); err != nil {
+		return err
+	} else if fp, is := p.(*gateway.Gateway); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := fp.InitPolicy(ctw,
+			&gateway.Policy{
+				WithdrawFee: amount.NewCoinAmount(30, 0),
+			},
+		); err != nil {
+			return err
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.vault"); err != nil {
+		return err
+	} else if sp, is := p.(*vault.Vault); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := sp.InitPolicy(ctw,
+			&vault.Policy{
+				AccountCreationAmount: amount.NewCoinAmount(10, 0),
+			},
+		); err != nil {
+			return err
+		}
+		if err := sp.InitUpgrades(ctw); err != nil {
+			return err
+		}
+
+		acc := &formulator.FormulatorAccount{
+			Address_:       app.generator,
+			Name_:          "dev.generator",
+			FormulatorType: formulator.AlphaFormulatorType,
+			KeyHash:        app.genHash,
+			GenHash:        app.genHash,
+			Amount:         alphaPolicy.AlphaCreationAmount,
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			return err
+		}
+		if err := sp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+			return err
+		}
+		for _, v := range app.accounts {
+			acc := &vault.SingleAccount{
+				Address_: v.Address,
+				Name_:    "dev." + v.Address.String(),
+				KeyHash:  v.KeyHash,
+			}
+			if err := ctw.CreateAccount(acc); err != nil {
+				return err
+			}
+			if !v.Amount.IsZero() {
+				if err := sp.AddBalance(ctw, acc.Address(), v.Amount); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// OnLoadChain called when the chain loaded
+func (app *DevApp) OnLoadChain(loader types.LoaderWrapper) error {
+	return nil
+}
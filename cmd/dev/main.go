@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/hex"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/cmd/closer"
+	"github.com/fletaio/fleta_testnet/cmd/config"
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	_ "github.com/fletaio/fleta_testnet/core/backend/buntdb_driver"
+	"github.com/fletaio/fleta_testnet/core/chain"
+	"github.com/fletaio/fleta_testnet/core/pile"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/pof/dev"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/escrow"
+	"github.com/fletaio/fleta_testnet/process/formulator"
+	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/htlc"
+	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/token"
+	"github.com/fletaio/fleta_testnet/process/vault"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// Config is a configuration for the cmd
+type Config struct {
+	KeyHex        string
+	Generator     string
+	BlockInterval int // milliseconds, zero means that blocks are generated only by dev.mine
+	APIPort       int
+	StoreRoot     string
+	Accounts      []AccountConfig
+}
+
+// AccountConfig is a configuration of the pre-funded account
+type AccountConfig struct {
+	Address string
+	KeyHash string
+	Amount  string
+}
+
+func main() {
+	var cfg Config
+	if err := config.LoadFile("./config.toml", &cfg); err != nil {
+		panic(err)
+	}
+	if len(cfg.StoreRoot) == 0 {
+		cfg.StoreRoot = "./ddata"
+	}
+
+	var genkey key.Key
+	if bs, err := hex.DecodeString(cfg.KeyHex); err != nil {
+		panic(err)
+	} else if Key, err := key.NewMemoryKeyFromBytes(bs); err != nil {
+		panic(err)
+	} else {
+		genkey = Key
+	}
+	Generator, err := common.ParseAddress(cfg.Generator)
+	if err != nil {
+		panic(err)
+	}
+	Accounts := []*DevAccount{}
+	for _, v := range cfg.Accounts {
+		addr, err := common.ParseAddress(v.Address)
+		if err != nil {
+			panic(err)
+		}
+		pubhash, err := common.ParsePublicHash(v.KeyHash)
+		if err != nil {
+			panic(err)
+		}
+		am, err := amount.ParseAmount(v.Amount)
+		if err != nil {
+			panic(err)
+		}
+		Accounts = append(Accounts, &DevAccount{
+			Address: addr,
+			KeyHash: pubhash,
+			Amount:  am,
+		})
+	}
+
+	cm := closer.NewManager()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigc
+		cm.CloseAll()
+	}()
+	defer cm.CloseAll()
+
+	ChainID := uint8(0xFF)
+	Name := "FLETA Dev"
+	Version := uint16(0x0001)
+
+	back, err := backend.Create("buntdb", cfg.StoreRoot+"/context")
+	if err != nil {
+		panic(err)
+	}
+	cdb, err := pile.Open(cfg.StoreRoot + "/chain")
+	if err != nil {
+		panic(err)
+	}
+	cdb.SetSyncMode(true)
+	st, err := chain.NewStore(back, cdb, ChainID, Name, Version)
+	if err != nil {
+		panic(err)
+	}
+	cm.Add("store", st)
+
+	cs := dev.NewConsensus(genkey, Generator)
+	app := NewDevApp(Generator, common.NewPublicHash(genkey.PublicKey()), Accounts)
+	cn := chain.NewChain(cs, app, st)
+	cn.MustAddProcess(admin.NewAdmin(1))
+	cn.MustAddProcess(vault.NewVault(2))
+	cn.MustAddProcess(formulator.NewFormulator(3))
+	cn.MustAddProcess(gateway.NewGateway(4))
+	cn.MustAddProcess(payment.NewPayment(5))
+	cn.MustAddProcess(token.NewToken(6))
+	cn.MustAddProcess(htlc.NewHTLC(7))
+	cn.MustAddProcess(escrow.NewEscrow(8))
+	as := apiserver.NewAPIServer()
+	cn.MustAddService(as)
+	if err := cn.Init(); err != nil {
+		panic(err)
+	}
+	cm.RemoveAll()
+	cm.Add("chain", cn)
+
+	if err := st.IterBlockAfterContext(func(b *types.Block) error {
+		if cm.IsClosed() {
+			return chain.ErrStoreClosed
+		}
+		if err := cn.ConnectBlock(b); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		if err == chain.ErrStoreClosed {
+			return
+		}
+		panic(err)
+	}
+	cm.RemoveAll()
+	cm.Add("dev", cs)
+	cm.Add("chain", cn)
+
+	log.Println("Dev chain is started at height", cn.Provider().Height(), "by", Generator.String())
+
+	go cs.Run(time.Duration(cfg.BlockInterval) * time.Millisecond)
+	go as.Run(":" + strconv.Itoa(cfg.APIPort))
+
+	cm.Wait()
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/fletaio/fleta_testnet/cmd/config"
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/bls"
 	"github.com/fletaio/fleta_testnet/common/key"
 	"github.com/fletaio/fleta_testnet/common/rlog"
 	"github.com/fletaio/fleta_testnet/core/backend"
@@ -27,27 +28,45 @@ import (
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/pof"
 	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/escrow"
 	"github.com/fletaio/fleta_testnet/process/formulator"
 	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/htlc"
 	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/token"
 	"github.com/fletaio/fleta_testnet/process/vault"
 	"github.com/fletaio/fleta_testnet/service/apiserver"
+	"github.com/fletaio/fleta_testnet/service/signer"
 )
 
 // Config is a configuration for the cmd
 type Config struct {
-	SeedNodeMap    map[string]string
-	ObserverKeyMap map[string]string
-	GenKeyHex      string
-	NodeKeyHex     string
-	Formulator     string
-	Port           int
-	APIPort        int
-	StoreRoot      string
-	BackendVersion int
-	RLogHost       string
-	RLogPath       string
-	UseRLog        bool
+	SeedNodeMap         map[string]string
+	ObserverKeyMap      map[string]string
+	ObserverBLSKeyMap   map[string]string
+	ObserverBLSProofMap map[string]string
+	GenKeyHex           string
+	NodeKeyHex          string
+	Formulator          string
+	Port                int
+	APIPort             int
+	AdminPort           int
+	StoreRoot           string
+	BackendVersion      int
+	RLogHost            string
+	RLogPath            string
+	UseRLog             bool
+	Signer              *signer.Config
+	Lease               *LeaseConfig
+	SignProtectPath     string
+}
+
+// LeaseConfig is a configuration of the active/standby mode
+// Instances of the same formulator share the lease file and only the holder of the lease generates blocks
+type LeaseConfig struct {
+	Path   string
+	Holder string
+	TTL    int // seconds
 }
 
 func main() {
@@ -67,7 +86,13 @@ func main() {
 	}
 
 	var frkey key.Key
-	if bs, err := hex.DecodeString(cfg.GenKeyHex); err != nil {
+	if cfg.Signer != nil {
+		if Key, err := signer.NewRemoteKeyFromConfig(cfg.Signer); err != nil {
+			panic(err)
+		} else {
+			frkey = Key
+		}
+	} else if bs, err := hex.DecodeString(cfg.GenKeyHex); err != nil {
 		panic(err)
 	} else if Key, err := key.NewMemoryKeyFromBytes(bs); err != nil {
 		panic(err)
@@ -180,6 +205,35 @@ func main() {
 	}
 
 	cs := pof.NewConsensus(MaxBlocksPerFormulator, ObserverKeys)
+	if len(cfg.ObserverBLSKeyMap) > 0 {
+		BLSKeyMap := map[common.PublicHash]bls.PublicKey{}
+		for k, v := range cfg.ObserverBLSKeyMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			pubkey, err := bls.ParsePublicKey(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSKeyMap[pubhash] = pubkey
+		}
+		BLSProofMap := map[common.PublicHash]bls.Signature{}
+		for k, v := range cfg.ObserverBLSProofMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			proof, err := bls.ParseSignature(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSProofMap[pubhash] = proof
+		}
+		if err := cs.SetObserverBLSKeys(BLSKeyMap, BLSProofMap); err != nil {
+			panic(err)
+		}
+	}
 	app := app.NewFletaApp()
 	cn := chain.NewChain(cs, app, st)
 	cn.MustAddProcess(admin.NewAdmin(1))
@@ -187,6 +241,9 @@ func main() {
 	cn.MustAddProcess(formulator.NewFormulator(3))
 	cn.MustAddProcess(gateway.NewGateway(4))
 	cn.MustAddProcess(payment.NewPayment(5))
+	cn.MustAddProcess(token.NewToken(6))
+	cn.MustAddProcess(htlc.NewHTLC(7))
+	cn.MustAddProcess(escrow.NewEscrow(8))
 	as := apiserver.NewAPIServer()
 	cn.MustAddService(as)
 	ws := NewWatcher()
@@ -235,11 +292,35 @@ func main() {
 		MaxTransactionsPerBlock: 5000,
 		Addrs:                   Addrs,
 	}, frkey, ndkey, NetAddressMap, SeedNodeMap, cs, cfg.StoreRoot+"/peer")
+	if cfg.Lease != nil {
+		Holder := cfg.Lease.Holder
+		if len(Holder) == 0 {
+			if v, err := os.Hostname(); err != nil {
+				panic(err)
+			} else {
+				Holder = v
+			}
+		}
+		TTL := time.Duration(cfg.Lease.TTL) * time.Second
+		if TTL <= 0 {
+			TTL = 10 * time.Second
+		}
+		fr.SetLease(pof.NewFileLease(cfg.Lease.Path, Holder, TTL), TTL/3)
+	}
+	if len(cfg.SignProtectPath) == 0 {
+		cfg.SignProtectPath = cfg.StoreRoot + "/signprotect"
+	}
+	signDB, err := backend.Create("buntdb", cfg.SignProtectPath)
+	if err != nil {
+		panic(err)
+	}
+	fr.SetSignProtection(pof.NewSignProtection(signDB))
 	if err := fr.Init(); err != nil {
 		panic(err)
 	}
 	cm.RemoveAll()
 	cm.Add("formulator", fr)
+	cm.Add("signprotect", signDB)
 
 	waitMap := map[common.Address]*chan struct{}{}
 	if false {
@@ -341,6 +422,13 @@ func main() {
 
 	go fr.Run(":" + strconv.Itoa(cfg.Port))
 	go as.Run(":" + strconv.Itoa(cfg.APIPort))
+	if cfg.AdminPort > 0 {
+		adm := apiserver.NewAPIServer()
+		if err := fr.InitAdmin(adm); err != nil {
+			panic(err)
+		}
+		go adm.Run("127.0.0.1:" + strconv.Itoa(cfg.AdminPort))
+	}
 
 	cm.Wait()
 }
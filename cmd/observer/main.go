@@ -15,6 +15,7 @@ import (
 	"github.com/fletaio/fleta_testnet/cmd/closer"
 	"github.com/fletaio/fleta_testnet/cmd/config"
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
 	"github.com/fletaio/fleta_testnet/common/key"
 	"github.com/fletaio/fleta_testnet/common/rlog"
 	"github.com/fletaio/fleta_testnet/core/backend"
@@ -23,25 +24,34 @@ import (
 	"github.com/fletaio/fleta_testnet/core/chain"
 	"github.com/fletaio/fleta_testnet/pof"
 	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/escrow"
 	"github.com/fletaio/fleta_testnet/process/formulator"
 	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/htlc"
 	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/token"
 	"github.com/fletaio/fleta_testnet/process/vault"
 	"github.com/fletaio/fleta_testnet/service/apiserver"
+	"github.com/fletaio/fleta_testnet/service/signer"
 )
 
 // Config is a configuration for the cmd
 type Config struct {
-	ObserverKeyMap map[string]string
-	KeyHex         string
-	ObseverPort    int
-	FormulatorPort int
-	APIPort        int
-	StoreRoot      string
-	BackendVersion int
-	RLogHost       string
-	RLogPath       string
-	UseRLog        bool
+	ObserverKeyMap      map[string]string
+	ObserverBLSKeyMap   map[string]string
+	ObserverBLSProofMap map[string]string
+	KeyHex              string
+	BLSKeyHex           string
+	ObseverPort         int
+	FormulatorPort      int
+	APIPort             int
+	AdminPort           int
+	StoreRoot           string
+	BackendVersion      int
+	RLogHost            string
+	RLogPath            string
+	UseRLog             bool
+	Signer              *signer.Config
 }
 
 func main() {
@@ -61,7 +71,13 @@ func main() {
 	}
 
 	var obkey key.Key
-	if bs, err := hex.DecodeString(cfg.KeyHex); err != nil {
+	if cfg.Signer != nil {
+		if Key, err := signer.NewRemoteKeyFromConfig(cfg.Signer); err != nil {
+			panic(err)
+		} else {
+			obkey = Key
+		}
+	} else if bs, err := hex.DecodeString(cfg.KeyHex); err != nil {
 		panic(err)
 	} else if Key, err := key.NewMemoryKeyFromBytes(bs); err != nil {
 		panic(err)
@@ -133,6 +149,35 @@ func main() {
 	}
 
 	cs := pof.NewConsensus(MaxBlocksPerFormulator, ObserverKeys)
+	if len(cfg.ObserverBLSKeyMap) > 0 {
+		BLSKeyMap := map[common.PublicHash]bls.PublicKey{}
+		for k, v := range cfg.ObserverBLSKeyMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			pubkey, err := bls.ParsePublicKey(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSKeyMap[pubhash] = pubkey
+		}
+		BLSProofMap := map[common.PublicHash]bls.Signature{}
+		for k, v := range cfg.ObserverBLSProofMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			proof, err := bls.ParseSignature(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSProofMap[pubhash] = proof
+		}
+		if err := cs.SetObserverBLSKeys(BLSKeyMap, BLSProofMap); err != nil {
+			panic(err)
+		}
+	}
 	app := app.NewFletaApp()
 	cn := chain.NewChain(cs, app, st)
 	cn.MustAddProcess(admin.NewAdmin(1))
@@ -140,6 +185,9 @@ func main() {
 	cn.MustAddProcess(formulator.NewFormulator(3))
 	cn.MustAddProcess(gateway.NewGateway(4))
 	cn.MustAddProcess(payment.NewPayment(5))
+	cn.MustAddProcess(token.NewToken(6))
+	cn.MustAddProcess(htlc.NewHTLC(7))
+	cn.MustAddProcess(escrow.NewEscrow(8))
 	as := apiserver.NewAPIServer()
 	cn.MustAddService(as)
 	if err := cn.Init(); err != nil {
@@ -163,15 +211,47 @@ func main() {
 		panic(err)
 	}
 
+	evidenceDB, err := backend.Create("buntdb", cfg.StoreRoot+"/evidence")
+	if err != nil {
+		panic(err)
+	}
+
+	roundDB, err := backend.Create("buntdb", cfg.StoreRoot+"/round")
+	if err != nil {
+		panic(err)
+	}
+
 	ob := pof.NewObserverNode(obkey, NetAddressMap, cs)
+	ob.SetEvidenceStore(evidenceDB)
+	if err := ob.SetRoundStore(roundDB); err != nil {
+		panic(err)
+	}
+	if len(cfg.BLSKeyHex) > 0 {
+		if bs, err := hex.DecodeString(cfg.BLSKeyHex); err != nil {
+			panic(err)
+		} else if Key, err := bls.NewPrivateKeyFromBytes(bs); err != nil {
+			panic(err)
+		} else {
+			ob.SetBLSKey(Key)
+		}
+	}
 	if err := ob.Init(); err != nil {
 		panic(err)
 	}
 	cm.RemoveAll()
 	cm.Add("observer", ob)
+	cm.Add("evidence", evidenceDB)
+	cm.Add("round", roundDB)
 
 	go ob.Run(":"+strconv.Itoa(cfg.ObseverPort), ":"+strconv.Itoa(cfg.FormulatorPort))
 	go as.Run(":" + strconv.Itoa(cfg.APIPort))
+	if cfg.AdminPort > 0 {
+		adm := apiserver.NewAPIServer()
+		if err := ob.InitAdmin(adm); err != nil {
+			panic(err)
+		}
+		go adm.Run("127.0.0.1:" + strconv.Itoa(cfg.AdminPort))
+	}
 
 	cm.Wait()
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/hex"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fletaio/fleta_testnet/cmd/config"
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	_ "github.com/fletaio/fleta_testnet/core/backend/buntdb_driver"
+	"github.com/fletaio/fleta_testnet/service/signer"
+)
+
+// Config is a configuration for the cmd
+type Config struct {
+	KeyHex    string
+	ChainID   uint8
+	Network   string
+	Address   string
+	CertFile  string
+	KeyFile   string
+	CAFile    string
+	StoreRoot string
+}
+
+func main() {
+	var cfg Config
+	if err := config.LoadFile("./config.toml", &cfg); err != nil {
+		panic(err)
+	}
+	if len(cfg.Network) == 0 {
+		cfg.Network = "tcp"
+	}
+	if len(cfg.StoreRoot) == 0 {
+		cfg.StoreRoot = "./sdata"
+	}
+
+	var sgkey key.Key
+	if bs, err := hex.DecodeString(cfg.KeyHex); err != nil {
+		panic(err)
+	} else if Key, err := key.NewMemoryKeyFromBytes(bs); err != nil {
+		panic(err)
+	} else {
+		sgkey = Key
+	}
+
+	TLSConfig, err := signer.NewServerTLSConfig(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Network == "unix" {
+		os.Remove(cfg.Address)
+	}
+
+	recordDB, err := backend.Create("buntdb", cfg.StoreRoot+"/record")
+	if err != nil {
+		panic(err)
+	}
+	s, err := signer.NewServer(sgkey, cfg.ChainID, recordDB)
+	if err != nil {
+		panic(err)
+	}
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigc
+		s.Close()
+	}()
+
+	log.Println("Signer", common.NewPublicHash(sgkey.PublicKey()).String(), cfg.Network, cfg.Address)
+	if err := s.Run(cfg.Network, cfg.Address, TLSConfig); err != nil {
+		panic(err)
+	}
+}
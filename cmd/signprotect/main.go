@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/fletaio/fleta_testnet/core/backend"
+	_ "github.com/fletaio/fleta_testnet/core/backend/buntdb_driver"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// signprotect exports and imports records of headers signed by the formulator key
+// so that the record moves with the key, the formulator should be stopped while running it
+func main() {
+	if len(os.Args) != 4 || (os.Args[1] != "export" && os.Args[1] != "import") {
+		fmt.Println("usage: signprotect export|import SIGN_PROTECT_PATH JSON_FILE")
+		os.Exit(1)
+	}
+	Command := os.Args[1]
+	StorePath := os.Args[2]
+	FilePath := os.Args[3]
+
+	back, err := backend.Create("buntdb", StorePath)
+	if err != nil {
+		panic(err)
+	}
+	defer back.Close()
+	sp := pof.NewSignProtection(back)
+
+	switch Command {
+	case "export":
+		list, err := sp.Records()
+		if err != nil {
+			panic(err)
+		}
+		data, err := json.MarshalIndent(list, "", "\t")
+		if err != nil {
+			panic(err)
+		}
+		if err := ioutil.WriteFile(FilePath, data, 0600); err != nil {
+			panic(err)
+		}
+		log.Println("Export", len(list), "records")
+	case "import":
+		data, err := ioutil.ReadFile(FilePath)
+		if err != nil {
+			panic(err)
+		}
+		var list []*pof.SignRecord
+		if err := json.Unmarshal(data, &list); err != nil {
+			panic(err)
+		}
+		if err := sp.Import(list); err != nil {
+			panic(err)
+		}
+		log.Println("Import", len(list), "records")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/fletaio/fleta_testnet/cmd/config"
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/bls"
 	"github.com/fletaio/fleta_testnet/common/key"
 	"github.com/fletaio/fleta_testnet/common/rlog"
 	"github.com/fletaio/fleta_testnet/core/backend"
@@ -27,9 +28,12 @@ import (
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/pof"
 	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/escrow"
 	"github.com/fletaio/fleta_testnet/process/formulator"
 	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/htlc"
 	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/token"
 	"github.com/fletaio/fleta_testnet/process/vault"
 	"github.com/fletaio/fleta_testnet/service/apiserver"
 	"github.com/fletaio/fleta_testnet/service/p2p"
@@ -37,16 +41,18 @@ import (
 
 // Config is a configuration for the cmd
 type Config struct {
-	SeedNodeMap    map[string]string
-	NodeKeyHex     string
-	ObserverKeys   []string
-	Port           int
-	APIPort        int
-	StoreRoot      string
-	BackendVersion int
-	RLogHost       string
-	RLogPath       string
-	UseRLog        bool
+	SeedNodeMap         map[string]string
+	NodeKeyHex          string
+	ObserverKeys        []string
+	ObserverBLSKeyMap   map[string]string
+	ObserverBLSProofMap map[string]string
+	Port                int
+	APIPort             int
+	StoreRoot           string
+	BackendVersion      int
+	RLogHost            string
+	RLogPath            string
+	UseRLog             bool
 }
 
 func main() {
@@ -167,6 +173,35 @@ func main() {
 	}
 
 	cs := pof.NewConsensus(MaxBlocksPerFormulator, ObserverKeys)
+	if len(cfg.ObserverBLSKeyMap) > 0 {
+		BLSKeyMap := map[common.PublicHash]bls.PublicKey{}
+		for k, v := range cfg.ObserverBLSKeyMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			pubkey, err := bls.ParsePublicKey(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSKeyMap[pubhash] = pubkey
+		}
+		BLSProofMap := map[common.PublicHash]bls.Signature{}
+		for k, v := range cfg.ObserverBLSProofMap {
+			pubhash, err := common.ParsePublicHash(k)
+			if err != nil {
+				panic(err)
+			}
+			proof, err := bls.ParseSignature(v)
+			if err != nil {
+				panic(err)
+			}
+			BLSProofMap[pubhash] = proof
+		}
+		if err := cs.SetObserverBLSKeys(BLSKeyMap, BLSProofMap); err != nil {
+			panic(err)
+		}
+	}
 	app := app.NewFletaApp()
 	cn := chain.NewChain(cs, app, st)
 	cn.MustAddProcess(admin.NewAdmin(1))
@@ -174,6 +209,9 @@ func main() {
 	cn.MustAddProcess(formulator.NewFormulator(3))
 	cn.MustAddProcess(gateway.NewGateway(4))
 	cn.MustAddProcess(payment.NewPayment(5))
+	cn.MustAddProcess(token.NewToken(6))
+	cn.MustAddProcess(htlc.NewHTLC(7))
+	cn.MustAddProcess(escrow.NewEscrow(8))
 	as := apiserver.NewAPIServer()
 	cn.MustAddService(as)
 	ws := NewWatcher()
@@ -0,0 +1,324 @@
+// Package bls implements BLS signatures over the bn256 pairing curve
+// Signatures are points of G1 and public keys are points of G2, so that signatures of the same message can be aggregated into one
+// Aggregation of public keys is not safe against the rogue key attack by itself,
+// so every public key should be checked by VerifyPossession before it is aggregated
+package bls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// PublicKeySize is 128 bytes
+const PublicKeySize = 128
+
+// SignatureSize is 64 bytes
+const SignatureSize = 64
+
+// PublicKey is the [PublicKeySize]byte with methods
+type PublicKey [PublicKeySize]byte
+
+// MarshalJSON is a marshaler function
+func (pubkey PublicKey) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + pubkey.String() + `"`), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (pubkey *PublicKey) UnmarshalJSON(bs []byte) error {
+	if len(bs) < 3 {
+		return ErrInvalidPublicKeyFormat
+	}
+	if bs[0] != '"' || bs[len(bs)-1] != '"' {
+		return ErrInvalidPublicKeyFormat
+	}
+	v, err := ParsePublicKey(string(bs[1 : len(bs)-1]))
+	if err != nil {
+		return err
+	}
+	copy(pubkey[:], v[:])
+	return nil
+}
+
+// String returns the hex string of the public key
+func (pubkey PublicKey) String() string {
+	return hex.EncodeToString(pubkey[:])
+}
+
+// Clone returns the clonend value of it
+func (pubkey PublicKey) Clone() PublicKey {
+	var cp PublicKey
+	copy(cp[:], pubkey[:])
+	return cp
+}
+
+// ParsePublicKey parse the public key from the string
+func ParsePublicKey(str string) (PublicKey, error) {
+	if len(str) != PublicKeySize*2 {
+		return PublicKey{}, ErrInvalidPublicKeyFormat
+	}
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	var pubkey PublicKey
+	copy(pubkey[:], bs)
+	if _, err := pubkey.point(); err != nil {
+		return PublicKey{}, err
+	}
+	return pubkey, nil
+}
+
+// MustParsePublicKey panic when error occurred
+func MustParsePublicKey(str string) PublicKey {
+	pubkey, err := ParsePublicKey(str)
+	if err != nil {
+		panic(err)
+	}
+	return pubkey
+}
+
+func (pubkey PublicKey) point() (*bn256.G2, error) {
+	if pubkey == (PublicKey{}) {
+		return nil, ErrInvalidPublicKey
+	}
+	p, ok := new(bn256.G2).Unmarshal(pubkey[:])
+	if !ok {
+		return nil, ErrInvalidPublicKey
+	}
+	return p, nil
+}
+
+// Signature is the [SignatureSize]byte with methods
+type Signature [SignatureSize]byte
+
+// MarshalJSON is a marshaler function
+func (sig Signature) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + sig.String() + `"`), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (sig *Signature) UnmarshalJSON(bs []byte) error {
+	if len(bs) < 3 {
+		return ErrInvalidSignatureFormat
+	}
+	if bs[0] != '"' || bs[len(bs)-1] != '"' {
+		return ErrInvalidSignatureFormat
+	}
+	v, err := ParseSignature(string(bs[1 : len(bs)-1]))
+	if err != nil {
+		return err
+	}
+	copy(sig[:], v[:])
+	return nil
+}
+
+// String returns the hex string of the signature
+func (sig Signature) String() string {
+	return hex.EncodeToString(sig[:])
+}
+
+// Clone returns the clonend value of it
+func (sig Signature) Clone() Signature {
+	var cp Signature
+	copy(cp[:], sig[:])
+	return cp
+}
+
+// ParseSignature parse the signature from the string
+func ParseSignature(str string) (Signature, error) {
+	if len(str) != SignatureSize*2 {
+		return Signature{}, ErrInvalidSignatureFormat
+	}
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return Signature{}, err
+	}
+	var sig Signature
+	copy(sig[:], bs)
+	return sig, nil
+}
+
+// MustParseSignature panic when error occurred
+func MustParseSignature(str string) Signature {
+	sig, err := ParseSignature(str)
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+func (sig Signature) point() (*bn256.G1, error) {
+	if sig == (Signature{}) {
+		return nil, ErrInvalidSignature
+	}
+	p, ok := new(bn256.G1).Unmarshal(sig[:])
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return p, nil
+}
+
+// PrivateKey is the BLS private key
+type PrivateKey struct {
+	k      *big.Int
+	pubkey PublicKey
+}
+
+// NewPrivateKey returns a random PrivateKey
+func NewPrivateKey() (*PrivateKey, error) {
+	k, _, err := bn256.RandomG2(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(k), nil
+}
+
+// NewPrivateKeyFromBytes returns a PrivateKey from the big endian byte array
+func NewPrivateKeyFromBytes(bs []byte) (*PrivateKey, error) {
+	k := new(big.Int).SetBytes(bs)
+	if k.Sign() == 0 || k.Cmp(bn256.Order) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	return newPrivateKey(k), nil
+}
+
+func newPrivateKey(k *big.Int) *PrivateKey {
+	key := &PrivateKey{
+		k: k,
+	}
+	copy(key.pubkey[:], new(bn256.G2).ScalarBaseMult(k).Marshal())
+	return key
+}
+
+// Bytes returns the 32 bytes big endian byte array of the private key
+func (key *PrivateKey) Bytes() []byte {
+	bs := make([]byte, 32)
+	kbs := key.k.Bytes()
+	copy(bs[32-len(kbs):], kbs)
+	return bs
+}
+
+// PublicKey returns the public key of the private key
+func (key *PrivateKey) PublicKey() PublicKey {
+	return key.pubkey.Clone()
+}
+
+// Sign returns the signature of the hash
+func (key *PrivateKey) Sign(h hash.Hash256) Signature {
+	var sig Signature
+	copy(sig[:], new(bn256.G1).ScalarMult(hashToG1(h), key.k).Marshal())
+	return sig
+}
+
+// Verify checks that the signature is signed the hash by the public key
+func Verify(pubkey PublicKey, h hash.Hash256, sig Signature) bool {
+	pk, err := pubkey.point()
+	if err != nil {
+		return false
+	}
+	s, err := sig.point()
+	if err != nil {
+		return false
+	}
+	return verifyPoints(pk, hashToG1(h), s)
+}
+
+// ProvePossession returns the proof of possession of the private key that signs the public key in the separated domain
+func (key *PrivateKey) ProvePossession() Signature {
+	var sig Signature
+	copy(sig[:], new(bn256.G1).ScalarMult(hashToPossessionG1(key.pubkey), key.k).Marshal())
+	return sig
+}
+
+// VerifyPossession checks that the proof is made by the private key of the public key
+// The rogue public key that is derived from other public keys cannot have the valid proof
+func VerifyPossession(pubkey PublicKey, proof Signature) bool {
+	pk, err := pubkey.point()
+	if err != nil {
+		return false
+	}
+	s, err := proof.point()
+	if err != nil {
+		return false
+	}
+	return verifyPoints(pk, hashToPossessionG1(pubkey), s)
+}
+
+// AggregateSignatures returns the sum of signatures
+func AggregateSignatures(sigs []Signature) (Signature, error) {
+	if len(sigs) == 0 {
+		return Signature{}, ErrEmptyAggregation
+	}
+	var sum *bn256.G1
+	for _, sig := range sigs {
+		s, err := sig.point()
+		if err != nil {
+			return Signature{}, err
+		}
+		if sum == nil {
+			sum = s
+		} else {
+			sum = new(bn256.G1).Add(sum, s)
+		}
+	}
+	var agg Signature
+	copy(agg[:], sum.Marshal())
+	return agg, nil
+}
+
+// AggregatePublicKeys returns the sum of public keys
+func AggregatePublicKeys(pubkeys []PublicKey) (PublicKey, error) {
+	sum, err := aggregatePublicKeys(pubkeys)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	var agg PublicKey
+	copy(agg[:], sum.Marshal())
+	return agg, nil
+}
+
+func aggregatePublicKeys(pubkeys []PublicKey) (*bn256.G2, error) {
+	if len(pubkeys) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	var sum *bn256.G2
+	for _, pubkey := range pubkeys {
+		pk, err := pubkey.point()
+		if err != nil {
+			return nil, err
+		}
+		if sum == nil {
+			sum = pk
+		} else {
+			sum = new(bn256.G2).Add(sum, pk)
+		}
+	}
+	return sum, nil
+}
+
+// VerifyAggregated checks that the aggregated signature is signed the hash by all of public keys
+func VerifyAggregated(pubkeys []PublicKey, h hash.Hash256, sig Signature) bool {
+	pk, err := aggregatePublicKeys(pubkeys)
+	if err != nil {
+		return false
+	}
+	s, err := sig.point()
+	if err != nil {
+		return false
+	}
+	return verifyPoints(pk, hashToG1(h), s)
+}
+
+// verifyPoints checks e(sig, g2) == e(hm, pk) where hm is the message mapped to G1
+func verifyPoints(pk *bn256.G2, hm *bn256.G1, s *bn256.G1) bool {
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	lhs := bn256.Pair(s, g2).Marshal()
+	rhs := bn256.Pair(hm, pk).Marshal()
+	return bytes.Equal(lhs, rhs)
+}
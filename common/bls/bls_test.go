@@ -0,0 +1,178 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"golang.org/x/crypto/bn256"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+type testVector struct {
+	PrivateKey string
+	PublicKey  string
+	Signature  string
+}
+
+// test vectors sign hash.Hash([]byte("FLETA"))
+var testMessage = "e6454ca43a69a4fb795afc658d27051f7061d73afb7b935cd06aa5986e45ddd5"
+
+var testVectors = []testVector{
+	{
+		PrivateKey: "000000000000000000000000000000008174099687a26621f4e2cdd7cc03b3da",
+		PublicKey:  "6e4ece2e42f0471f5619afdfd17f5b673619dfea4c327b59e04bbf2b930c502e13a78ddb67e71f7f1698c19df95b04b9c8442646190ea4d3ab2471606e3b7d402f8f7f9cabbccb76992360094044ada913c2ce8b7c80de7d9aacfadbe705067b33423795f4e21899e645a0c8b9b4d6ddfdac3939abf1293287fd14e4bd156c40",
+		Signature:  "347624774cce1c45b1201e289d1c6d8a6ece144d7335fbfdc83dc4718b5a47811469bef41b6724cf36954ae496859770e659683cf5593df37b71038cbdbb0bb5",
+	},
+	{
+		PrivateKey: "00000000000000000000000000000000b10253764c8b233fb37542e23401c7b4",
+		PublicKey:  "7a1f012acce75212d8111862530d0620d34a2b2928b84732f39d394115ed6d337df8ec47f509915e57e5eb4deb3f0f99826f910b380ef6fe8f86a6a28d4a0e4f258e7f9abd6b39cc82179303929279877a27af0e04941e0b5a75a3aa2b2476a172972082d6cd9703f3dc8a6e6cbac16f05926649412898c56b24fcab3391f8e9",
+		Signature:  "49b6fe6668073c01da273d5354d791109b75a4c2d3a9cf3028f2009ac760fb3523f8f432fda8fbb4720aeae6517a31eaff20ddeb0c1741194f5316355ad4171f",
+	},
+	{
+		PrivateKey: "00000000000000000000000000000000f576104eebeab09651d83acffc77c8b8",
+		PublicKey:  "1f7cd47e51b33456ee9ca3f8951c83300980e00866ea148803ca7616bfb739c46cfec9ca954cfd31ac831d92b702562ad2006b028bae304d2ddf2ca9d3364b45258efbb27a983dee16ae20678ad61ef0432b9f082b814ae7a990d7b65a67854954156a458ea940672d997b120eae51d430f41c712c3412a4a1672b06b4116127",
+		Signature:  "4b06d82dd7cce89a915573b8a4302526edd7830ce5659d449eeae374446e41b686855a0fd0a16bfced0ef2f110d158e7dee400e38dd18742944807d4d5730266",
+	},
+}
+
+var testAggregatedSignature = "4d662822c9353c3f14db4a26407cd916b628a7a17daeb3d7138513f806a7471479cc23a3d1ebe4af4c8dd8bccdbaad985653eed5538c168db8adc71b5503ba36"
+
+func TestVectors(t *testing.T) {
+	msg := hash.MustParseHash(testMessage)
+	if msg != hash.Hash([]byte("FLETA")) {
+		t.Fatal("invalid test message")
+	}
+	for i, v := range testVectors {
+		bs, err := hex.DecodeString(v.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := NewPrivateKeyFromBytes(bs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key.Bytes()) != v.PrivateKey {
+			t.Errorf("vector %d: private key mismatch", i)
+		}
+		if key.PublicKey().String() != v.PublicKey {
+			t.Errorf("vector %d: public key mismatch %v", i, key.PublicKey())
+		}
+		sig := key.Sign(msg)
+		if sig.String() != v.Signature {
+			t.Errorf("vector %d: signature mismatch %v", i, sig)
+		}
+		pubkey, err := ParsePublicKey(v.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(pubkey, msg, sig) {
+			t.Errorf("vector %d: verification failed", i)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	msg := hash.MustParseHash(testMessage)
+	pubkeys := []PublicKey{}
+	sigs := []Signature{}
+	for _, v := range testVectors {
+		pubkeys = append(pubkeys, MustParsePublicKey(v.PublicKey))
+		sigs = append(sigs, MustParseSignature(v.Signature))
+	}
+	agg, err := AggregateSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agg.String() != testAggregatedSignature {
+		t.Fatalf("aggregated signature mismatch %v", agg)
+	}
+	if !VerifyAggregated(pubkeys, msg, agg) {
+		t.Fatal("aggregated verification failed")
+	}
+	if VerifyAggregated(pubkeys[:2], msg, agg) {
+		t.Fatal("aggregated signature verified without a signer")
+	}
+	aggPubkey, err := AggregatePublicKeys(pubkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(aggPubkey, msg, agg) {
+		t.Fatal("verification by the aggregated public key failed")
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	msg := hash.MustParseHash(testMessage)
+	pubkey := MustParsePublicKey(testVectors[0].PublicKey)
+	sig := MustParseSignature(testVectors[0].Signature)
+	if Verify(pubkey, hash.Hash([]byte("FLETA2")), sig) {
+		t.Fatal("verified with the other message")
+	}
+	if Verify(MustParsePublicKey(testVectors[1].PublicKey), msg, sig) {
+		t.Fatal("verified with the other public key")
+	}
+	if Verify(PublicKey{}, msg, Signature{}) {
+		t.Fatal("verified with the zero values")
+	}
+	if _, err := NewPrivateKeyFromBytes(make([]byte, 32)); err != ErrInvalidPrivateKey {
+		t.Fatal("zero private key is allowed")
+	}
+	var bad Signature
+	copy(bad[:], sig[:])
+	bad[10] ^= 0x01
+	if Verify(pubkey, msg, bad) {
+		t.Fatal("verified with the modified signature")
+	}
+}
+
+func TestPossession(t *testing.T) {
+	keys := []*PrivateKey{}
+	for _, v := range testVectors {
+		bs, err := hex.DecodeString(v.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := NewPrivateKeyFromBytes(bs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	for i, key := range keys {
+		if !VerifyPossession(key.PublicKey(), key.ProvePossession()) {
+			t.Fatalf("key %d: proof verification failed", i)
+		}
+	}
+	if VerifyPossession(keys[1].PublicKey(), keys[0].ProvePossession()) {
+		t.Fatal("verified with the proof of the other key")
+	}
+	msg := hash.MustParseHash(testMessage)
+	if VerifyPossession(keys[0].PublicKey(), keys[0].Sign(msg)) {
+		t.Fatal("verified with the signature of the message")
+	}
+
+	// the rogue key pk' = g^x - pk forges the aggregated signature of pk and pk' by x alone
+	x, err := NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := keys[0].PublicKey().point()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gx, err := x.PublicKey().point()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rogue PublicKey
+	neg := new(bn256.G2).ScalarMult(target, new(big.Int).Sub(bn256.Order, big.NewInt(1)))
+	copy(rogue[:], new(bn256.G2).Add(gx, neg).Marshal())
+	if !VerifyAggregated([]PublicKey{keys[0].PublicKey(), rogue}, msg, x.Sign(msg)) {
+		t.Fatal("rogue key is not able to forge the aggregation")
+	}
+	if VerifyPossession(rogue, x.ProvePossession()) {
+		t.Fatal("rogue key is verified with the proof")
+	}
+}
@@ -0,0 +1,13 @@
+package bls
+
+import "errors"
+
+// bls errors
+var (
+	ErrInvalidPrivateKey      = errors.New("invalid private key")
+	ErrInvalidPublicKey       = errors.New("invalid public key")
+	ErrInvalidSignature       = errors.New("invalid signature")
+	ErrInvalidPublicKeyFormat = errors.New("invalid public key format")
+	ErrInvalidSignatureFormat = errors.New("invalid signature format")
+	ErrEmptyAggregation       = errors.New("empty aggregation")
+)
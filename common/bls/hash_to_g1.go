@@ -0,0 +1,71 @@
+package bls
+
+import (
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// fieldPrime is the prime of the base field of bn256
+var fieldPrime, _ = new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+
+// sqrtExp is (p+1)/4, the prime is 3 mod 4 so that a^sqrtExp is the square root of a when it exists
+var sqrtExp = new(big.Int).Rsh(new(big.Int).Add(fieldPrime, big.NewInt(1)), 2)
+
+var curveB = big.NewInt(3)
+
+// hashToG1 maps the hash of the message to the point of G1
+func hashToG1(h hash.Hash256) *bn256.G1 {
+	return hashToPoint(hashToG1Tag, h)
+}
+
+// hashToPossessionG1 maps the public key to the point of G1 in the domain of proofs of possession
+// so that the proof cannot be used as the signature of any message
+func hashToPossessionG1(pubkey PublicKey) *bn256.G1 {
+	return hashToPoint(hashToPossessionTag, hash.Hash(pubkey[:]))
+}
+
+// hashToPoint maps the hash to the point of G1 by the try-and-increment method in the domain of the tag
+// G1 of bn256 has the cofactor 1, so every point on y^2 = x^3 + 3 is in G1
+func hashToPoint(tag []byte, h hash.Hash256) *bn256.G1 {
+	data := make([]byte, 0, len(tag)+hash.Hash256Size+1)
+	data = append(data, tag...)
+	data = append(data, h[:]...)
+	data = append(data, 0)
+	for i := 0; ; i++ {
+		data[len(data)-1] = byte(i)
+		hx := hash.Hash(data)
+		x := new(big.Int).SetBytes(hx[:])
+		x.Mod(x, fieldPrime)
+
+		rhs := new(big.Int).Mul(x, x)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, curveB)
+		rhs.Mod(rhs, fieldPrime)
+
+		y := new(big.Int).Exp(rhs, sqrtExp, fieldPrime)
+		if new(big.Int).Exp(y, big.NewInt(2), fieldPrime).Cmp(rhs) != 0 {
+			continue
+		}
+		// use the smaller root to make the mapping deterministic
+		if ny := new(big.Int).Sub(fieldPrime, y); ny.Cmp(y) < 0 {
+			y = ny
+		}
+		bs := make([]byte, 64)
+		xbs := x.Bytes()
+		ybs := y.Bytes()
+		copy(bs[32-len(xbs):], xbs)
+		copy(bs[64-len(ybs):], ybs)
+		p, ok := new(bn256.G1).Unmarshal(bs)
+		if !ok {
+			continue
+		}
+		return p
+	}
+}
+
+var hashToG1Tag = []byte("FLETA_BLS_HASH_TO_G1")
+
+var hashToPossessionTag = []byte("FLETA_BLS_POSSESSION_TO_G1")
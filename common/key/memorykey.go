@@ -10,6 +10,7 @@ import (
 	"github.com/fletaio/fleta_testnet/common"
 	ecrypto "github.com/fletaio/fleta_testnet/common/crypto"
 	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/vrf"
 )
 
 func init() {
@@ -113,3 +114,8 @@ func (ac *MemoryKey) Verify(h hash.Hash256, sig common.Signature) bool {
 func (ac *MemoryKey) Bytes() []byte {
 	return ac.PrivKey.D.Bytes()
 }
+
+// ProveVRF returns the VRF output and the proof of alpha
+func (ac *MemoryKey) ProveVRF(alpha []byte) (hash.Hash256, []byte, error) {
+	return vrf.Prove(ac.PrivKey, alpha)
+}
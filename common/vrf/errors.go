@@ -0,0 +1,10 @@
+package vrf
+
+import "errors"
+
+// vrf errors
+var (
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrInvalidProof      = errors.New("invalid proof")
+)
@@ -0,0 +1,189 @@
+// Package vrf implements the verifiable random function over secp256k1 keys
+// It follows the ECVRF construction: the proof of alpha is (Gamma, c, s) where Gamma = x*H(Y, alpha),
+// c = Hash(H, Gamma, k*G, k*H) and s = k + c*x, and the output is the hash of Gamma
+// The output is unique for the key and alpha, so the prover cannot choose it among candidates
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/crypto/ethereum/crypto/secp256k1"
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// ProofSize is 97 bytes (compressed Gamma, c and s)
+const ProofSize = common.PublicKeySize + 32 + 32
+
+var curve = secp256k1.S256()
+
+// sqrtExp is (p+1)/4, the prime is 3 mod 4 so that a^sqrtExp is the square root of a when it exists
+var sqrtExp = new(big.Int).Rsh(new(big.Int).Add(curve.P, big.NewInt(1)), 2)
+
+var (
+	hashToCurveTag = []byte("FLETA_VRF_HASH_TO_CURVE")
+	nonceTag       = []byte("FLETA_VRF_NONCE")
+	challengeTag   = []byte("FLETA_VRF_CHALLENGE")
+	outputTag      = []byte("FLETA_VRF_OUTPUT")
+)
+
+// Prove returns the output and the proof of alpha by the private key
+func Prove(PrivKey *ecdsa.PrivateKey, alpha []byte) (hash.Hash256, []byte, error) {
+	if PrivKey == nil || PrivKey.D == nil || PrivKey.D.Sign() <= 0 || PrivKey.D.Cmp(curve.N) >= 0 {
+		return hash.Hash256{}, nil, ErrInvalidPrivateKey
+	}
+	pubkey := secp256k1.CompressPubkey(PrivKey.PublicKey.X, PrivKey.PublicKey.Y)
+	Hx, Hy := hashToCurve(pubkey, alpha)
+	Gx, Gy := curve.ScalarMult(Hx, Hy, scalarBytes(PrivKey.D))
+	if Gx == nil {
+		return hash.Hash256{}, nil, ErrInvalidPrivateKey
+	}
+	k := nonce(PrivKey.D, Hx, Hy)
+	Ux, Uy := curve.ScalarBaseMult(scalarBytes(k))
+	Vx, Vy := curve.ScalarMult(Hx, Hy, scalarBytes(k))
+	if Ux == nil || Vx == nil {
+		return hash.Hash256{}, nil, ErrInvalidPrivateKey
+	}
+	c := challenge(Hx, Hy, Gx, Gy, Ux, Uy, Vx, Vy)
+	s := new(big.Int).Mul(c, PrivKey.D)
+	s.Add(s, k)
+	s.Mod(s, curve.N)
+
+	proof := make([]byte, 0, ProofSize)
+	proof = append(proof, secp256k1.CompressPubkey(Gx, Gy)...)
+	proof = append(proof, scalarBytes(c)...)
+	proof = append(proof, scalarBytes(s)...)
+	return outputHash(proof[:common.PublicKeySize]), proof, nil
+}
+
+// Verify checks that the proof of alpha is generated by the public key and returns the output of it
+func Verify(pubkey common.PublicKey, alpha []byte, proof []byte) (hash.Hash256, error) {
+	if len(proof) != ProofSize {
+		return hash.Hash256{}, ErrInvalidProof
+	}
+	Yx, Yy := secp256k1.DecompressPubkey(pubkey[:])
+	if Yx == nil {
+		return hash.Hash256{}, ErrInvalidPublicKey
+	}
+	Gx, Gy := secp256k1.DecompressPubkey(proof[:common.PublicKeySize])
+	if Gx == nil {
+		return hash.Hash256{}, ErrInvalidProof
+	}
+	c := new(big.Int).SetBytes(proof[common.PublicKeySize : common.PublicKeySize+32])
+	s := new(big.Int).SetBytes(proof[common.PublicKeySize+32:])
+	if c.Sign() == 0 || c.Cmp(curve.N) >= 0 || s.Sign() == 0 || s.Cmp(curve.N) >= 0 {
+		return hash.Hash256{}, ErrInvalidProof
+	}
+	negC := new(big.Int).Sub(curve.N, c)
+	Hx, Hy := hashToCurve(pubkey[:], alpha)
+
+	// U = s*G - c*Y
+	sGx, sGy := curve.ScalarBaseMult(scalarBytes(s))
+	cYx, cYy := curve.ScalarMult(Yx, Yy, scalarBytes(negC))
+	Ux, Uy := addPoints(sGx, sGy, cYx, cYy)
+	// V = s*H - c*Gamma
+	sHx, sHy := curve.ScalarMult(Hx, Hy, scalarBytes(s))
+	cGx, cGy := curve.ScalarMult(Gx, Gy, scalarBytes(negC))
+	Vx, Vy := addPoints(sHx, sHy, cGx, cGy)
+	if Ux == nil || Vx == nil {
+		return hash.Hash256{}, ErrInvalidProof
+	}
+	if challenge(Hx, Hy, Gx, Gy, Ux, Uy, Vx, Vy).Cmp(c) != 0 {
+		return hash.Hash256{}, ErrInvalidProof
+	}
+	return outputHash(proof[:common.PublicKeySize]), nil
+}
+
+// hashToCurve maps the public key and alpha to the point by the try-and-increment method
+// secp256k1 has the cofactor 1, so every point on y^2 = x^3 + 7 is in the group
+func hashToCurve(pubkey []byte, alpha []byte) (*big.Int, *big.Int) {
+	data := make([]byte, 0, len(hashToCurveTag)+len(pubkey)+len(alpha)+1)
+	data = append(data, hashToCurveTag...)
+	data = append(data, pubkey...)
+	data = append(data, alpha...)
+	data = append(data, 0)
+	for i := 0; ; i++ {
+		data[len(data)-1] = byte(i)
+		hx := hash.Hash(data)
+		x := new(big.Int).SetBytes(hx[:])
+		if x.Cmp(curve.P) >= 0 {
+			continue
+		}
+
+		rhs := new(big.Int).Mul(x, x)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, curve.B)
+		rhs.Mod(rhs, curve.P)
+
+		y := new(big.Int).Exp(rhs, sqrtExp, curve.P)
+		if new(big.Int).Exp(y, big.NewInt(2), curve.P).Cmp(rhs) != 0 {
+			continue
+		}
+		// use the even root to make the mapping deterministic
+		if y.Bit(0) == 1 {
+			y.Sub(curve.P, y)
+		}
+		return x, y
+	}
+}
+
+// nonce returns the deterministic nonce from the private key and the hashed point
+func nonce(D *big.Int, Hx *big.Int, Hy *big.Int) *big.Int {
+	data := make([]byte, 0, len(nonceTag)+32+common.PublicKeySize+1)
+	data = append(data, nonceTag...)
+	data = append(data, scalarBytes(D)...)
+	data = append(data, secp256k1.CompressPubkey(Hx, Hy)...)
+	data = append(data, 0)
+	for i := 0; ; i++ {
+		data[len(data)-1] = byte(i)
+		h := hash.Hash(data)
+		k := new(big.Int).SetBytes(h[:])
+		if k.Sign() > 0 && k.Cmp(curve.N) < 0 {
+			return k
+		}
+	}
+}
+
+func challenge(Hx, Hy, Gx, Gy, Ux, Uy, Vx, Vy *big.Int) *big.Int {
+	data := make([]byte, 0, len(challengeTag)+common.PublicKeySize*4)
+	data = append(data, challengeTag...)
+	data = append(data, secp256k1.CompressPubkey(Hx, Hy)...)
+	data = append(data, secp256k1.CompressPubkey(Gx, Gy)...)
+	data = append(data, secp256k1.CompressPubkey(Ux, Uy)...)
+	data = append(data, secp256k1.CompressPubkey(Vx, Vy)...)
+	h := hash.Hash(data)
+	c := new(big.Int).SetBytes(h[:])
+	return c.Mod(c, curve.N)
+}
+
+func outputHash(Gamma []byte) hash.Hash256 {
+	data := make([]byte, 0, len(outputTag)+len(Gamma))
+	data = append(data, outputTag...)
+	data = append(data, Gamma...)
+	return hash.Hash(data)
+}
+
+// addPoints adds points with handling the doubling and the point at infinity(nil) that the curve addition doesn't handle
+func addPoints(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) == 0 {
+			return curve.Double(x1, y1)
+		}
+		return nil, nil
+	}
+	return curve.Add(x1, y1, x2, y2)
+}
+
+func scalarBytes(v *big.Int) []byte {
+	bs := make([]byte, 32)
+	vbs := v.Bytes()
+	copy(bs[32-len(vbs):], vbs)
+	return bs
+}
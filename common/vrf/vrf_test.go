@@ -0,0 +1,98 @@
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	ecrypto "github.com/fletaio/fleta_testnet/common/crypto/ethereum/crypto"
+)
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, common.PublicKey) {
+	PrivKey, err := ecrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubkey common.PublicKey
+	copy(pubkey[:], ecrypto.CompressPubkey(&PrivKey.PublicKey))
+	return PrivKey, pubkey
+}
+
+func TestProveVerify(t *testing.T) {
+	PrivKey, pubkey := newTestKey(t)
+	alpha := []byte("FLETA")
+
+	output, proof, err := Prove(PrivKey, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof) != ProofSize {
+		t.Fatalf("invalid proof size %d", len(proof))
+	}
+	if v, err := Verify(pubkey, alpha, proof); err != nil {
+		t.Fatal(err)
+	} else if v != output {
+		t.Fatal("output mismatch")
+	}
+
+	output2, proof2, err := Prove(PrivKey, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output2 != output || string(proof2) != string(proof) {
+		t.Fatal("proof is not deterministic")
+	}
+	if output3, _, err := Prove(PrivKey, []byte("FLETA2")); err != nil {
+		t.Fatal(err)
+	} else if output3 == output {
+		t.Fatal("output is not changed by alpha")
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	PrivKey, pubkey := newTestKey(t)
+	_, other := newTestKey(t)
+	alpha := []byte("FLETA")
+
+	_, proof, err := Prove(PrivKey, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(pubkey, []byte("FLETA2"), proof); err != ErrInvalidProof {
+		t.Errorf("invalid alpha is accepted: %v", err)
+	}
+	if _, err := Verify(other, alpha, proof); err != ErrInvalidProof {
+		t.Errorf("invalid public key is accepted: %v", err)
+	}
+	for _, idx := range []int{1, common.PublicKeySize + 1, ProofSize - 1} {
+		tampered := make([]byte, len(proof))
+		copy(tampered, proof)
+		tampered[idx] ^= 0x01
+		if _, err := Verify(pubkey, alpha, tampered); err != ErrInvalidProof {
+			t.Errorf("tampered proof at %d is accepted: %v", idx, err)
+		}
+	}
+	if _, err := Verify(pubkey, alpha, proof[:ProofSize-1]); err != ErrInvalidProof {
+		t.Errorf("short proof is accepted: %v", err)
+	}
+
+	// s*G == c*Y makes U the point at infinity, it should be rejected without panics
+	c := big.NewInt(7)
+	s := new(big.Int).Mul(c, PrivKey.D)
+	s.Mod(s, curve.N)
+	crafted := make([]byte, 0, ProofSize)
+	crafted = append(crafted, proof[:common.PublicKeySize]...)
+	crafted = append(crafted, scalarBytes(c)...)
+	crafted = append(crafted, scalarBytes(s)...)
+	if _, err := Verify(pubkey, alpha, crafted); err != ErrInvalidProof {
+		t.Errorf("crafted proof is accepted: %v", err)
+	}
+	// Gamma that is not the compressed point
+	invalid := make([]byte, len(proof))
+	copy(invalid, proof)
+	invalid[0] = 0x05
+	if _, err := Verify(pubkey, alpha, invalid); err != ErrInvalidProof {
+		t.Errorf("invalid gamma is accepted: %v", err)
+	}
+}
@@ -118,7 +118,11 @@ func (st *Store) LastStatus() (uint32, hash.Hash256, uint64) {
 	height := st.Height()
 	h, err := st.Hash(height)
 	if err != nil {
-		panic(err)
+		if err != ErrStoreClosed {
+			// should not be reached
+			panic(err)
+		}
+		return 0, hash.Hash256{}, 0
 	}
 	if height == 0 {
 		return 0, h, 0
@@ -126,7 +130,7 @@ func (st *Store) LastStatus() (uint32, hash.Hash256, uint64) {
 	bh, err := st.Header(height)
 	if err != nil {
 		if err != ErrStoreClosed {
-			// should have not reabh
+			// should not be reached
 			panic(err)
 		}
 		return 0, hash.Hash256{}, 0
@@ -139,7 +143,7 @@ func (st *Store) LastHash() hash.Hash256 {
 	h, err := st.Hash(st.Height())
 	if err != nil {
 		if err != ErrStoreClosed {
-			// should have not reabh
+			// should not be reached
 			panic(err)
 		}
 		return hash.Hash256{}
@@ -155,7 +159,7 @@ func (st *Store) LastTimestamp() uint64 {
 	bh, err := st.Header(st.Height())
 	if err != nil {
 		if err != ErrStoreClosed {
-			// should have not reabh
+			// should not be reached
 			panic(err)
 		}
 		return 0
@@ -358,6 +362,31 @@ func (st *Store) Accounts() ([]types.Account, error) {
 	return list, nil
 }
 
+// Addresses returns addresses of all accounts including deleted accounts in the store
+func (st *Store) Addresses() ([]common.Address, error) {
+	st.closeLock.RLock()
+	defer st.closeLock.RUnlock()
+	if st.isClose {
+		return nil, ErrStoreClosed
+	}
+
+	list := []common.Address{}
+	if err := st.db.View(func(txn backend.StoreReader) error {
+		if err := txn.Iterate(tagAccount, func(key []byte, value []byte) error {
+			var addr common.Address
+			copy(addr[:], key[len(tagAccount):])
+			list = append(list, addr)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 // Seq returns the sequence of the transaction
 func (st *Store) Seq(addr common.Address) uint64 {
 	st.closeLock.RLock()
@@ -981,7 +1010,23 @@ func applyContextData(txn backend.StoreWriter, ctd *types.ContextData) error {
 			inErr = err
 			return false
 		}
-		if err := txn.Set(toAccountNameKey(acc.Name()), addr[:]); err != nil {
+		return true
+	})
+	if inErr != nil {
+		return inErr
+	}
+	ctd.AccountNameMap.EachAll(func(Name string, addr common.Address) bool {
+		if err := txn.Set(toAccountNameKey(Name), addr[:]); err != nil {
+			inErr = err
+			return false
+		}
+		return true
+	})
+	if inErr != nil {
+		return inErr
+	}
+	ctd.DeletedAccountNameMap.EachAll(func(Name string, value bool) bool {
+		if err := txn.Delete(toAccountNameKey(Name)); err != nil {
 			inErr = err
 			return false
 		}
@@ -1133,7 +1178,23 @@ func applyContextDataOld(txn backend.StoreWriter, ctd *types.ContextData) error
 			inErr = err
 			return false
 		}
-		if err := txn.Set(toAccountNameKey(acc.Name()), addr[:]); err != nil {
+		return true
+	})
+	if inErr != nil {
+		return inErr
+	}
+	ctd.AccountNameMap.EachAll(func(Name string, addr common.Address) bool {
+		if err := txn.Set(toAccountNameKey(Name), addr[:]); err != nil {
+			inErr = err
+			return false
+		}
+		return true
+	})
+	if inErr != nil {
+		return inErr
+	}
+	ctd.DeletedAccountNameMap.EachAll(func(Name string, value bool) bool {
+		if err := txn.Delete(toAccountNameKey(Name)); err != nil {
 			inErr = err
 			return false
 		}
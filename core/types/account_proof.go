@@ -0,0 +1,146 @@
+package types
+
+import (
+	"bytes"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/util"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// AccountProof has account changes of the block with hashes of other sections of the context data
+// It can be verified against the context hash of the header without the whole context data
+type AccountProof struct {
+	SeqMapHash             hash.Hash256
+	AccountMapData         []byte
+	DeletedAccounts        []common.Address
+	AccountNameMapHash     hash.Hash256
+	DeletedAccountNames    []string
+	AccountDataMapHash     hash.Hash256
+	DeletedAccountDataKeys []string
+	UTXOMapHash            hash.Hash256
+	CreatedUTXOMapHash     hash.Hash256
+	DeletedUTXOKeys        []uint64
+	EventHashes            []hash.Hash256
+	ProcessDataMapHash     hash.Hash256
+	DeletedProcessDataKeys []string
+}
+
+// NewAccountProof returns the AccountProof of the context data
+func NewAccountProof(ctd *ContextData) *AccountProof {
+	AccountMapData, err := encoding.Marshal(ctd.AccountMap)
+	if err != nil {
+		panic(err)
+	}
+	pf := &AccountProof{
+		SeqMapHash:             encoding.Hash(ctd.SeqMap),
+		AccountMapData:         AccountMapData,
+		DeletedAccounts:        []common.Address{},
+		AccountNameMapHash:     encoding.Hash(ctd.AccountNameMap),
+		DeletedAccountNames:    []string{},
+		AccountDataMapHash:     encoding.Hash(ctd.AccountDataMap),
+		DeletedAccountDataKeys: []string{},
+		UTXOMapHash:            encoding.Hash(ctd.UTXOMap),
+		CreatedUTXOMapHash:     encoding.Hash(ctd.CreatedUTXOMap),
+		DeletedUTXOKeys:        []uint64{},
+		EventHashes:            make([]hash.Hash256, 0, len(ctd.Events)),
+		ProcessDataMapHash:     encoding.Hash(ctd.ProcessDataMap),
+		DeletedProcessDataKeys: []string{},
+	}
+	ctd.DeletedAccountMap.EachAll(func(addr common.Address, acc Account) bool {
+		pf.DeletedAccounts = append(pf.DeletedAccounts, addr)
+		return true
+	})
+	ctd.DeletedAccountNameMap.EachAll(func(key string, value bool) bool {
+		pf.DeletedAccountNames = append(pf.DeletedAccountNames, key)
+		return true
+	})
+	ctd.DeletedAccountDataMap.EachAll(func(key string, value bool) bool {
+		pf.DeletedAccountDataKeys = append(pf.DeletedAccountDataKeys, key)
+		return true
+	})
+	ctd.DeletedUTXOMap.EachAll(func(key uint64, utxo *UTXO) bool {
+		pf.DeletedUTXOKeys = append(pf.DeletedUTXOKeys, key)
+		return true
+	})
+	for _, e := range ctd.Events {
+		pf.EventHashes = append(pf.EventHashes, encoding.Hash(e))
+	}
+	ctd.DeletedProcessDataMap.EachAll(func(key string, value bool) bool {
+		pf.DeletedProcessDataKeys = append(pf.DeletedProcessDataKeys, key)
+		return true
+	})
+	return pf
+}
+
+// AccountMap returns account changes of the block
+func (pf *AccountProof) AccountMap() (*AddressAccountMap, error) {
+	AccountMap := NewAddressAccountMap()
+	if err := encoding.Unmarshal(pf.AccountMapData, &AccountMap); err != nil {
+		return nil, err
+	}
+	return AccountMap, nil
+}
+
+// DataHash returns the hash of the context data that is proved by it
+func (pf *AccountProof) DataHash(ChainID uint8, Name string, Version uint16, Height uint32, PrevHash hash.Hash256) hash.Hash256 {
+	var buffer bytes.Buffer
+	buffer.WriteString("ChainID")
+	buffer.Write([]byte{ChainID})
+	buffer.WriteString("ChainName")
+	buffer.WriteString(Name)
+	buffer.WriteString("ChainVersion")
+	buffer.Write(util.Uint16ToBytes(Version))
+	buffer.WriteString("Height")
+	buffer.Write(util.Uint32ToBytes(Height))
+	buffer.WriteString("PrevHash")
+	buffer.Write(PrevHash[:])
+	buffer.WriteString("SeqMap")
+	buffer.WriteString(pf.SeqMapHash.String())
+	buffer.WriteString("AccountMap")
+	buffer.WriteString(hash.Hash(pf.AccountMapData).String())
+	buffer.WriteString("DeletedAccountMap")
+	for _, addr := range pf.DeletedAccounts {
+		buffer.Write(addr[:])
+	}
+	buffer.WriteString("AccountNameMap")
+	buffer.WriteString(pf.AccountNameMapHash.String())
+	if len(pf.DeletedAccountNames) > 0 {
+		// written only when names are released to keep hashes of previous blocks
+		buffer.WriteString("DeletedAccountNameMap")
+		for _, key := range pf.DeletedAccountNames {
+			buffer.WriteString(key)
+		}
+	}
+	buffer.WriteString("AccountDataMap")
+	buffer.WriteString(pf.AccountDataMapHash.String())
+	buffer.WriteString("DeletedAccountDataMap")
+	for _, key := range pf.DeletedAccountDataKeys {
+		buffer.WriteString(key)
+	}
+	buffer.WriteString("UTXOMap")
+	buffer.WriteString(pf.UTXOMapHash.String())
+	buffer.WriteString("CreatedUTXOMap")
+	buffer.WriteString(pf.CreatedUTXOMapHash.String())
+	buffer.WriteString("DeletedUTXOMap")
+	for _, key := range pf.DeletedUTXOKeys {
+		buffer.Write(util.Uint64ToBytes(key))
+	}
+	buffer.WriteString("Events")
+	for _, h := range pf.EventHashes {
+		buffer.Write(h[:])
+	}
+	buffer.WriteString("ProcessDataMap")
+	buffer.WriteString(pf.ProcessDataMapHash.String())
+	buffer.WriteString("DeletedProcessDataMap")
+	for _, key := range pf.DeletedProcessDataKeys {
+		buffer.WriteString(key)
+	}
+	return hash.DoubleHash(buffer.Bytes())
+}
+
+// ContextHash returns the context hash of the header that is proved by it
+func (pf *AccountProof) ContextHash(Name string, bh *Header) hash.Hash256 {
+	return hash.Hashes(bh.PrevHash, pf.DataHash(bh.ChainID, Name, bh.Version, bh.Height, bh.PrevHash))
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"bytes"
+
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/encoding"
 )
 
 // Header is validation informations
@@ -17,3 +20,13 @@ type Header struct {
 	Generator     common.Address
 	ConsensusData []byte
 }
+
+// DecodeTimeoutCount returns the timeout count of the round that is encoded at the front of the consensus data
+func DecodeTimeoutCount(ConsensusData []byte) (uint32, error) {
+	dec := encoding.NewDecoder(bytes.NewReader(ConsensusData))
+	TimeoutCount, err := dec.DecodeUint32()
+	if err != nil {
+		return 0, err
+	}
+	return TimeoutCount, nil
+}
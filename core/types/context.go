@@ -15,6 +15,7 @@ type Context struct {
 	stack           []*ContextData
 	isLatestHash    bool
 	dataHash        hash.Hash256
+	accountProof    *AccountProof
 }
 
 // NewContext returns a Context
@@ -63,12 +64,23 @@ func (ctx *Context) NextContext(NextHash hash.Hash256, Timestamp uint64) *Contex
 // Hash returns the hash value of it
 func (ctx *Context) Hash() hash.Hash256 {
 	if !ctx.isLatestHash {
-		ctx.dataHash = hash.Hashes(ctx.genLastHash, ctx.Top().Hash())
+		h, pf := ctx.Top().hashWithProof()
+		ctx.dataHash = hash.Hashes(ctx.genLastHash, h)
+		ctx.accountProof = pf
 		ctx.isLatestHash = true
 	}
 	return ctx.dataHash
 }
 
+// AccountProof returns the account proof of the last computed hash
+// The chain computes the hash before saving data of the block, so it proves the context hash of the connected block
+func (ctx *Context) AccountProof() *AccountProof {
+	if ctx.accountProof == nil {
+		ctx.Hash()
+	}
+	return ctx.accountProof
+}
+
 // TargetHeight returns the recorded target height when context generation
 func (ctx *Context) TargetHeight() uint32 {
 	return ctx.genTargetHeight
@@ -126,6 +138,11 @@ func (ctx *Context) HasAccountName(Name string) (bool, error) {
 	return ctx.Top().HasAccountName(Name)
 }
 
+// Addresses returns addresses of all accounts including deleted accounts in the ascending order
+func (ctx *Context) Addresses() ([]common.Address, error) {
+	return ctx.Top().Addresses()
+}
+
 // CreateAccount inserts the account to the top snapshot
 func (ctx *Context) CreateAccount(acc Account) error {
 	ctx.isLatestHash = false
@@ -138,6 +155,18 @@ func (ctx *Context) DeleteAccount(acc Account) error {
 	return ctx.Top().DeleteAccount(acc)
 }
 
+// SetAccountName binds the name to the address in the top snapshot
+func (ctx *Context) SetAccountName(Name string, addr common.Address) error {
+	ctx.isLatestHash = false
+	return ctx.Top().SetAccountName(Name, addr)
+}
+
+// DeleteAccountName unbinds the name in the top snapshot
+func (ctx *Context) DeleteAccountName(Name string) error {
+	ctx.isLatestHash = false
+	return ctx.Top().DeleteAccountName(Name)
+}
+
 // AccountData returns the account data from the top snapshot
 func (ctx *Context) AccountData(addr common.Address, pid uint8, name []byte) []byte {
 	return ctx.Top().AccountData(addr, pid, name)
@@ -232,6 +261,12 @@ func (ctx *Context) Commit(sn int) {
 		})
 		ctd.AccountNameMap.EachAll(func(key string, addr common.Address) bool {
 			top.AccountNameMap.Put(key, addr)
+			top.DeletedAccountNameMap.Delete(key)
+			return true
+		})
+		ctd.DeletedAccountNameMap.EachAll(func(key string, value bool) bool {
+			top.AccountNameMap.Delete(key)
+			top.DeletedAccountNameMap.Put(key, value)
 			return true
 		})
 		ctd.AccountDataMap.EachAll(func(key string, value []byte) bool {
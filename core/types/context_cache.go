@@ -166,3 +166,8 @@ func (cc *contextCache) ProcessData(pid uint8, name []byte) []byte {
 		return value
 	}
 }
+
+// Addresses returns addresses of all accounts including deleted accounts of the loader
+func (cc *contextCache) Addresses() ([]common.Address, error) {
+	return cc.ctx.loader.Addresses()
+}
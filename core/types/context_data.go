@@ -3,10 +3,9 @@ package types
 import (
 	"bytes"
 	"encoding/hex"
+	"sort"
 	"strconv"
 
-	"github.com/fletaio/fleta_testnet/common/util"
-
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/common/hash"
 	"github.com/fletaio/fleta_testnet/encoding"
@@ -20,6 +19,7 @@ type ContextData struct {
 	AccountMap            *AddressAccountMap
 	DeletedAccountMap     *AddressAccountMap
 	AccountNameMap        *StringAddressMap
+	DeletedAccountNameMap *StringBoolMap
 	AccountDataMap        *StringBytesMap
 	DeletedAccountDataMap *StringBoolMap
 	ProcessDataMap        *StringBytesMap
@@ -45,6 +45,7 @@ func NewContextData(loader internalLoader, Parent *ContextData) *ContextData {
 		AccountMap:            NewAddressAccountMap(),
 		DeletedAccountMap:     NewAddressAccountMap(),
 		AccountNameMap:        NewStringAddressMap(),
+		DeletedAccountNameMap: NewStringBoolMap(),
 		AccountDataMap:        NewStringBytesMap(),
 		DeletedAccountDataMap: NewStringBoolMap(),
 		ProcessDataMap:        NewStringBytesMap(),
@@ -125,6 +126,9 @@ func (ctd *ContextData) Account(addr common.Address) (Account, error) {
 
 // AddressByName returns the account address of the name
 func (ctd *ContextData) AddressByName(Name string) (common.Address, error) {
+	if ctd.DeletedAccountNameMap.Has(Name) {
+		return common.Address{}, ErrNotExistAccount
+	}
 	if addr, has := ctd.AccountNameMap.Get(Name); has {
 		return addr, nil
 	} else if ctd.Parent != nil {
@@ -170,6 +174,9 @@ func (ctd *ContextData) HasAccount(addr common.Address) (bool, error) {
 
 // HasAccountName checks that the account of the address is exist or not
 func (ctd *ContextData) HasAccountName(Name string) (bool, error) {
+	if ctd.DeletedAccountNameMap.Has(Name) {
+		return false, nil
+	}
 	if ctd.AccountNameMap.Has(Name) {
 		return true, nil
 	} else if ctd.Parent != nil {
@@ -179,6 +186,45 @@ func (ctd *ContextData) HasAccountName(Name string) (bool, error) {
 	}
 }
 
+// Addresses returns addresses of all accounts including deleted accounts in the ascending order
+// Deleted accounts are included because their data can be remained after the deletion
+func (ctd *ContextData) Addresses() ([]common.Address, error) {
+	var list []common.Address
+	if ctd.Parent != nil {
+		if addrs, err := ctd.Parent.Addresses(); err != nil {
+			return nil, err
+		} else {
+			list = addrs
+		}
+	} else {
+		if addrs, err := ctd.loader.Addresses(); err != nil {
+			return nil, err
+		} else {
+			list = addrs
+		}
+	}
+	addrMap := map[common.Address]bool{}
+	for _, addr := range list {
+		addrMap[addr] = true
+	}
+	ctd.AccountMap.EachAll(func(addr common.Address, acc Account) bool {
+		addrMap[addr] = true
+		return true
+	})
+	ctd.DeletedAccountMap.EachAll(func(addr common.Address, acc Account) bool {
+		addrMap[addr] = true
+		return true
+	})
+	addrs := make([]common.Address, 0, len(addrMap))
+	for addr := range addrMap {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs, nil
+}
+
 // CreateAccount inserts the account
 func (ctd *ContextData) CreateAccount(acc Account) error {
 	if acc.Address().Height() != ctd.loader.TargetHeight() {
@@ -206,6 +252,7 @@ func (ctd *ContextData) CreateAccount(acc Account) error {
 	}
 	ctd.AccountMap.Put(acc.Address(), acc)
 	ctd.AccountNameMap.Put(acc.Name(), acc.Address())
+	ctd.DeletedAccountNameMap.Delete(acc.Name())
 	return nil
 }
 
@@ -216,7 +263,35 @@ func (ctd *ContextData) DeleteAccount(acc Account) error {
 	}
 	ctd.DeletedAccountMap.Put(acc.Address(), acc)
 	ctd.AccountMap.Delete(acc.Address())
-	ctd.AccountNameMap.Delete(acc.Name())
+	// the name can be bound to the other account by the name service
+	if addr, has := ctd.AccountNameMap.Get(acc.Name()); has && addr == acc.Address() {
+		ctd.AccountNameMap.Delete(acc.Name())
+	}
+	return nil
+}
+
+// SetAccountName binds the name to the address of the existing account
+func (ctd *ContextData) SetAccountName(Name string, addr common.Address) error {
+	if _, err := common.ParseAddress(Name); err == nil {
+		return ErrNotAllowedAddressAccountName
+	}
+	if _, err := ctd.Account(addr); err != nil {
+		return err
+	}
+	ctd.AccountNameMap.Put(Name, addr)
+	ctd.DeletedAccountNameMap.Delete(Name)
+	return nil
+}
+
+// DeleteAccountName unbinds the name from its address
+func (ctd *ContextData) DeleteAccountName(Name string) error {
+	if has, err := ctd.HasAccountName(Name); err != nil {
+		return err
+	} else if !has {
+		return ErrNotExistAccount
+	}
+	ctd.AccountNameMap.Delete(Name)
+	ctd.DeletedAccountNameMap.Put(Name, true)
 	return nil
 }
 
@@ -404,60 +479,13 @@ func (ctd *ContextData) SetProcessData(pid uint8, name []byte, value []byte) {
 
 // Hash returns the hash value of it
 func (ctd *ContextData) Hash() hash.Hash256 {
-	var buffer bytes.Buffer
-	buffer.WriteString("ChainID")
-	buffer.Write([]byte{ctd.loader.ChainID()})
-	buffer.WriteString("ChainName")
-	buffer.WriteString(ctd.loader.Name())
-	buffer.WriteString("ChainVersion")
-	buffer.Write(util.Uint16ToBytes(ctd.loader.Version()))
-	buffer.WriteString("Height")
-	buffer.Write(util.Uint32ToBytes(ctd.loader.TargetHeight()))
-	buffer.WriteString("PrevHash")
-	lastHash := ctd.loader.LastHash()
-	buffer.Write(lastHash[:])
-	buffer.WriteString("SeqMap")
-	buffer.WriteString(encoding.Hash(ctd.SeqMap).String())
-	buffer.WriteString("AccountMap")
-	buffer.WriteString(encoding.Hash(ctd.AccountMap).String())
-	buffer.WriteString("DeletedAccountMap")
-	ctd.DeletedAccountMap.EachAll(func(addr common.Address, acc Account) bool {
-		buffer.Write(addr[:])
-		return true
-	})
-	buffer.WriteString("AccountNameMap")
-	buffer.WriteString(encoding.Hash(ctd.AccountNameMap).String())
-	buffer.WriteString("AccountDataMap")
-	buffer.WriteString(encoding.Hash(ctd.AccountDataMap).String())
-	buffer.WriteString("DeletedAccountDataMap")
-	ctd.DeletedAccountDataMap.EachAll(func(key string, value bool) bool {
-		buffer.WriteString(key)
-		return true
-	})
-	buffer.WriteString("UTXOMap")
-	buffer.WriteString(encoding.Hash(ctd.UTXOMap).String())
-	buffer.WriteString("CreatedUTXOMap")
-	buffer.WriteString(encoding.Hash(ctd.CreatedUTXOMap).String())
-	buffer.WriteString("DeletedUTXOMap")
-	ctd.DeletedUTXOMap.EachAll(func(key uint64, utxo *UTXO) bool {
-		buffer.Write(util.Uint64ToBytes(key))
-		return true
-	})
-	buffer.WriteString("Events")
-	if len(ctd.Events) > 0 {
-		for _, e := range ctd.Events {
-			h := encoding.Hash(e)
-			buffer.Write(h[:])
-		}
-	}
-	buffer.WriteString("ProcessDataMap")
-	buffer.WriteString(encoding.Hash(ctd.ProcessDataMap).String())
-	buffer.WriteString("DeletedProcessDataMap")
-	ctd.DeletedProcessDataMap.EachAll(func(key string, value bool) bool {
-		buffer.WriteString(key)
-		return true
-	})
-	return hash.DoubleHash(buffer.Bytes())
+	h, _ := ctd.hashWithProof()
+	return h
+}
+
+func (ctd *ContextData) hashWithProof() (hash.Hash256, *AccountProof) {
+	pf := NewAccountProof(ctd)
+	return pf.DataHash(ctd.loader.ChainID(), ctd.loader.Name(), ctd.loader.Version(), ctd.loader.TargetHeight(), ctd.loader.LastHash()), pf
 }
 
 // Dump prints the context data
@@ -513,6 +541,13 @@ func (ctd *ContextData) Dump() string {
 		return true
 	})
 	buffer.WriteString("\n")
+	buffer.WriteString("DeletedAccountNameMap\n")
+	ctd.DeletedAccountNameMap.EachAll(func(key string, value bool) bool {
+		buffer.WriteString(key)
+		buffer.WriteString("\n")
+		return true
+	})
+	buffer.WriteString("\n")
 	buffer.WriteString("AccountDataMap\n")
 	ctd.AccountDataMap.EachAll(func(key string, value []byte) bool {
 		buffer.WriteString(hex.EncodeToString([]byte(key)) + ":" + hash.Hash([]byte(key)).String())
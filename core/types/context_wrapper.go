@@ -112,6 +112,11 @@ func (ctw *ContextWrapper) HasAccount(addr common.Address) (bool, error) {
 	return ctw.ctx.HasAccount(addr)
 }
 
+// Addresses returns addresses of all accounts including deleted accounts in the ascending order
+func (ctw *ContextWrapper) Addresses() ([]common.Address, error) {
+	return ctw.ctx.Addresses()
+}
+
 // HasAccountName checks that the account of the name is exist or not
 func (ctw *ContextWrapper) HasAccountName(Name string) (bool, error) {
 	return ctw.ctx.HasAccountName(Name)
@@ -127,6 +132,16 @@ func (ctw *ContextWrapper) DeleteAccount(acc Account) error {
 	return ctw.ctx.DeleteAccount(acc)
 }
 
+// SetAccountName binds the name to the address in the top snapshot
+func (ctw *ContextWrapper) SetAccountName(Name string, addr common.Address) error {
+	return ctw.ctx.SetAccountName(Name, addr)
+}
+
+// DeleteAccountName unbinds the name in the top snapshot
+func (ctw *ContextWrapper) DeleteAccountName(Name string) error {
+	return ctw.ctx.DeleteAccountName(Name)
+}
+
 // AccountData returns the account data from the top snapshot
 func (ctw *ContextWrapper) AccountData(addr common.Address, name []byte) []byte {
 	return ctw.ctx.AccountData(addr, ctw.pid, name)
@@ -26,6 +26,7 @@ type internalLoader interface {
 	LastTimestamp() uint64
 	AccountData(addr common.Address, pid uint8, name []byte) []byte
 	ProcessData(pid uint8, name []byte) []byte
+	Addresses() ([]common.Address, error)
 }
 
 type emptyLoader struct {
@@ -115,3 +116,8 @@ func (st *emptyLoader) UTXO(id uint64) (*UTXO, error) {
 func (st *emptyLoader) ProcessData(pid uint8, name []byte) []byte {
 	return nil
 }
+
+// Addresses returns no addresses
+func (st *emptyLoader) Addresses() ([]common.Address, error) {
+	return []common.Address{}, nil
+}
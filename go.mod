@@ -34,6 +34,7 @@ require (
 	github.com/urfave/cli v1.20.0
 	github.com/vmihailenco/msgpack v4.0.4+incompatible
 	github.com/xujiajun/nutsdb v0.4.0
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 	golang.org/x/net v0.0.0-20190620200207-3b0461eec859
 	golang.org/x/sys v0.0.0-20190626221950-04f50cda93cb
 	gopkg.in/tucnak/telebot.v2 v2.0.0-20190915201756-d408d5d2680d
@@ -20,9 +20,12 @@ import (
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/pof"
 	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/escrow"
 	"github.com/fletaio/fleta_testnet/process/formulator"
 	"github.com/fletaio/fleta_testnet/process/gateway"
+	"github.com/fletaio/fleta_testnet/process/htlc"
 	"github.com/fletaio/fleta_testnet/process/payment"
+	"github.com/fletaio/fleta_testnet/process/token"
 	"github.com/fletaio/fleta_testnet/process/vault"
 )
 
@@ -118,6 +121,9 @@ func test() error {
 		cn.MustAddProcess(fp)
 		cn.MustAddProcess(gateway.NewGateway(4))
 		cn.MustAddProcess(payment.NewPayment(5))
+		cn.MustAddProcess(token.NewToken(6))
+		cn.MustAddProcess(htlc.NewHTLC(7))
+		cn.MustAddProcess(escrow.NewEscrow(8))
 		if err := cn.Init(); err != nil {
 			return err
 		}
@@ -187,6 +193,9 @@ func test() error {
 		cn.MustAddProcess(fp)
 		cn.MustAddProcess(gateway.NewGateway(4))
 		cn.MustAddProcess(payment.NewPayment(5))
+		cn.MustAddProcess(token.NewToken(6))
+		cn.MustAddProcess(htlc.NewHTLC(7))
+		cn.MustAddProcess(escrow.NewEscrow(8))
 		if err := cn.Init(); err != nil {
 			return err
 		}
@@ -229,6 +238,9 @@ func test() error {
 			cn.MustAddProcess(fp)
 			cn.MustAddProcess(gateway.NewGateway(4))
 			cn.MustAddProcess(payment.NewPayment(5))
+			cn.MustAddProcess(token.NewToken(6))
+			cn.MustAddProcess(htlc.NewHTLC(7))
+			cn.MustAddProcess(escrow.NewEscrow(8))
 			ws := NewWatcher()
 			cn.MustAddService(ws)
 			if err := cn.Init(); err != nil {
@@ -0,0 +1,56 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// PeerStatus is the connection status of the peer that is reported by the admin rpc
+type PeerStatus struct {
+	ID            string
+	Name          string
+	GuessHeight   uint32
+	ConnectedTime int64
+}
+
+// MarshalJSON is a marshaler function
+func (ps *PeerStatus) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"id":`)
+	if bs, err := json.Marshal(ps.ID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(ps.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"guess_height":`)
+	if bs, err := json.Marshal(ps.GuessHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"connected_time":`)
+	if bs, err := json.Marshal(ps.ConnectedTime); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+func sortPeerStatuses(list []*PeerStatus) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+}
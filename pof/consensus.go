@@ -1,13 +1,16 @@
 package pof
 
 import (
-	"bytes"
 	"sync"
 
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
+	"github.com/fletaio/fleta_testnet/common/hash"
 	"github.com/fletaio/fleta_testnet/core/chain"
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/formulator"
 	"github.com/fletaio/fleta_testnet/service/apiserver"
 )
 
@@ -20,11 +23,19 @@ type Consensus struct {
 	maxBlocksPerFormulator uint32
 	blocksBySameFormulator uint32
 	observerKeyMap         *types.PublicHashBoolMap
+	observerBLSKeyMap      map[common.PublicHash]bls.PublicKey
 	rt                     *RankTable
+	seed                   hash.Hash256
+	observerQuorum         uint32
+	maxPhaseDiffPolicy     uint32
+	hasPolicy              bool
 	maxPhaseDiff           func(Height uint32) uint32
+	admin                  *admin.Admin
+	formulator             *formulator.Formulator
 }
 
 // NewConsensus returns a Consensus
+// MaxBlocksPerFormulator is the genesis value, it is updated by the consensus policy of the admin process after the genesis
 func NewConsensus(MaxBlocksPerFormulator uint32, ObserverKeys []common.PublicHash) *Consensus {
 	ObserverKeyMap := types.NewPublicHashBoolMap()
 	for _, pubhash := range ObserverKeys {
@@ -33,6 +44,7 @@ func NewConsensus(MaxBlocksPerFormulator uint32, ObserverKeys []common.PublicHas
 	cs := &Consensus{
 		maxBlocksPerFormulator: MaxBlocksPerFormulator,
 		observerKeyMap:         ObserverKeyMap,
+		observerBLSKeyMap:      map[common.PublicHash]bls.PublicKey{},
 		rt:                     NewRankTable(),
 		maxPhaseDiff:           nil,
 	}
@@ -44,6 +56,17 @@ func (cs *Consensus) Init(cn *chain.Chain, ct chain.Committer) error {
 	cs.cn = cn
 	cs.ct = ct
 
+	if p, err := cn.ProcessByName("fleta.admin"); err != nil {
+		//ignore when not loaded, the consensus policy is not updated
+	} else if v, is := p.(*admin.Admin); is {
+		cs.admin = v
+	}
+	if p, err := cn.ProcessByName("fleta.formulator"); err != nil {
+		//ignore when not loaded, evidences of observers are kept
+	} else if v, is := p.(*formulator.Formulator); is {
+		cs.formulator = v
+	}
+
 	if vs, err := cn.ServiceByName("fleta.apiserver"); err != nil {
 		//ignore when not loaded
 	} else if v, is := vs.(*apiserver.APIServer); !is {
@@ -53,10 +76,59 @@ func (cs *Consensus) Init(cn *chain.Chain, ct chain.Committer) error {
 		if err != nil {
 			return err
 		}
+		s.Set("params", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			return cs.Params()
+		})
 		s.Set("getRanks", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
 			list := cs.rt.Candidates()
 			return list, nil
 		})
+		s.Set("schedule", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			Count := 10
+			var Formulator common.Address
+			if arg.Len() > 2 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			if arg.Len() > 0 {
+				v, err := arg.Int(0)
+				if err != nil {
+					return nil, err
+				}
+				if v < 1 || v > 1000 {
+					return nil, apiserver.ErrInvalidArgument
+				}
+				Count = v
+			}
+			if arg.Len() > 1 {
+				v, err := arg.String(1)
+				if err != nil {
+					return nil, err
+				}
+				addr, err := common.ParseAddress(v)
+				if err != nil {
+					return nil, err
+				}
+				Formulator = addr
+			}
+			return cs.Schedule(Count, Formulator), nil
+		})
+		s.Set("formulatorStats", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() > 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			if arg.Len() == 0 {
+				return cs.CandidateStats()
+			}
+			arg0, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			addr, err := common.ParseAddress(arg0)
+			if err != nil {
+				return nil, err
+			}
+			return cs.FormulatorStats(addr)
+		})
 	}
 
 	return nil
@@ -79,6 +151,7 @@ func (cs *Consensus) InitGenesis(ctw *types.ContextWrapper) error {
 }
 
 // SetMaxPhaseDiff returns the max phase difference from the smallest phase(0 means no limit)
+// It is only used until the consensus policy is set on chain, MaxPhaseDiff of the policy is authoritative after it
 func (cs *Consensus) SetMaxPhaseDiff(fn func(Height uint32) uint32) {
 	cs.Lock()
 	defer cs.Unlock()
@@ -91,41 +164,23 @@ func (cs *Consensus) OnLoadChain(loader types.LoaderWrapper) error {
 	cs.Lock()
 	defer cs.Unlock()
 
-	dec := encoding.NewDecoder(bytes.NewReader(loader.ProcessData(tagState)))
-	if v, err := dec.DecodeUint32(); err != nil {
+	sd, err := decodeSaveData(loader.ProcessData(tagState))
+	if err != nil {
 		return err
-	} else {
-		if cs.maxBlocksPerFormulator != v {
-			return ErrInvalidMaxBlocksPerFormulator
-		}
 	}
-	ObserverKeyMap := types.NewPublicHashBoolMap()
-	if err := dec.Decode(&ObserverKeyMap); err != nil {
-		return err
-	} else {
-		if ObserverKeyMap.Len() != cs.observerKeyMap.Len() {
-			return ErrInvalidObserverKey
-		}
-		var inErr error
-		ObserverKeyMap.EachAll(func(pubhash common.PublicHash, value bool) bool {
-			if !cs.observerKeyMap.Has(pubhash) {
-				inErr = ErrInvalidObserverKey
-				return false
-			}
-			return true
-		})
-		if inErr != nil {
-			return inErr
-		}
+	if sd.MaxBlocksPerFormulator == 0 {
+		return ErrInvalidMaxBlocksPerFormulator
 	}
-	if v, err := dec.DecodeUint32(); err != nil {
-		return err
-	} else {
-		cs.blocksBySameFormulator = v
-	}
-	if err := dec.Decode(&cs.rt); err != nil {
-		return err
+	if !isSameObserverKeyMap(sd.ObserverKeyMap, cs.observerKeyMap) {
+		return ErrInvalidObserverKey
 	}
+	cs.maxBlocksPerFormulator = sd.MaxBlocksPerFormulator
+	cs.blocksBySameFormulator = sd.BlocksBySameFormulator
+	cs.rt = sd.rt
+	cs.seed = sd.Seed
+	cs.observerQuorum = sd.ObserverQuorum
+	cs.maxPhaseDiffPolicy = sd.MaxPhaseDiff
+	cs.hasPolicy = sd.HasPolicy
 	return nil
 }
 
@@ -153,8 +208,19 @@ func (cs *Consensus) ValidateSignature(bh *types.Header, sigs []common.Signature
 	if Top.PublicHash != pubhash {
 		return ErrInvalidTopSignature
 	}
+	if err := validateVRF(bh, pubkey, cs.seed); err != nil {
+		return err
+	}
 
-	if len(sigs) != cs.observerKeyMap.Len()/2+2 {
+	bs := types.BlockSign{
+		HeaderHash:         encoding.Hash(bh),
+		GeneratorSignature: sigs[0],
+	}
+	if IsBLSVersion(bh.Version) {
+		return cs.validateBLSSignatures(&bs, sigs[1:])
+	}
+	Quorum := cs.ObserverQuorum()
+	if len(sigs) != Quorum+1 {
 		return ErrInvalidSignatureCount
 	}
 	KeyMap := map[common.PublicHash]bool{}
@@ -162,12 +228,8 @@ func (cs *Consensus) ValidateSignature(bh *types.Header, sigs []common.Signature
 		KeyMap[pubhash] = true
 		return true
 	})
-	bs := types.BlockSign{
-		HeaderHash:         encoding.Hash(bh),
-		GeneratorSignature: sigs[0],
-	}
 	ObserverSignatures := sigs[1:]
-	if err := common.ValidateSignaturesMajority(encoding.Hash(bs), ObserverSignatures, KeyMap); err != nil {
+	if err := common.ValidateSignaturesQuorum(encoding.Hash(bs), ObserverSignatures, KeyMap, Quorum); err != nil {
 		return err
 	}
 	return nil
@@ -178,27 +240,30 @@ func (cs *Consensus) OnSaveData(b *types.Block, ctw *types.ContextWrapper) error
 	cs.Lock()
 	defer cs.Unlock()
 
-	HeaderHash := encoding.Hash(b.Header)
-
 	TimeoutCount, err := cs.DecodeConsensusData(b.Header.ConsensusData)
 	if err != nil {
 		return err
 	}
-	if TimeoutCount > 0 {
-		if err := cs.rt.forwardCandidates(int(TimeoutCount)); err != nil {
-			return err
-		}
-		cs.blocksBySameFormulator = 0
+	if err := cs.recordFormulatorStats(ctw, &b.Header, int(TimeoutCount)); err != nil {
+		return err
+	}
+	Seed, err := nextSeed(&b.Header)
+	if err != nil {
+		return err
 	}
-	cs.blocksBySameFormulator++
-	if cs.blocksBySameFormulator >= cs.maxBlocksPerFormulator {
-		cs.rt.forwardTop(HeaderHash)
-		cs.blocksBySameFormulator = 0
+	if v, err := forwardRankTable(cs.rt, TimeoutCount, Seed, cs.blocksBySameFormulator, cs.maxBlocksPerFormulator); err != nil {
+		return err
+	} else {
+		cs.blocksBySameFormulator = v
 	}
+	cs.seed = Seed
 
 	if err := cs.updateFormulatorList(ctw); err != nil {
 		return err
 	}
+	if err := cs.applyPendingPolicy(ctw); err != nil {
+		return err
+	}
 	if data, err := cs.buildSaveData(); err != nil {
 		return err
 	} else {
@@ -0,0 +1,160 @@
+package pof
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// BLSSignatureVersion is the first header version that uses the aggregated BLS signature of observers
+// Blocks of the version have the generator signature and the packed BLS signature instead of observer signatures
+// The packed BLS signature is the aggregated signature followed by the signer bitmap,
+// it is split into signatures of common.SignatureSize bytes and the last one is padded by zeros
+// The bit i of the signer bitmap is the i-th observer in the order of public hash bytes
+const BLSSignatureVersion = uint16(2)
+
+// IsBLSVersion returns true when the header version uses the aggregated BLS signature
+func IsBLSVersion(Version uint16) bool {
+	return Version >= BLSSignatureVersion
+}
+
+// SetObserverBLSKeys sets BLS public keys of observers, it should be called before the chain init
+// Each key should have the proof of possession to prevent the rogue key attack on the aggregated signature
+func (cs *Consensus) SetObserverBLSKeys(KeyMap map[common.PublicHash]bls.PublicKey, ProofMap map[common.PublicHash]bls.Signature) error {
+	cs.Lock()
+	defer cs.Unlock()
+
+	if len(KeyMap) != cs.observerKeyMap.Len() {
+		return ErrInvalidObserverKey
+	}
+	blsKeyMap := map[common.PublicHash]bls.PublicKey{}
+	for pubhash, pubkey := range KeyMap {
+		if !cs.observerKeyMap.Has(pubhash) {
+			return ErrInvalidObserverKey
+		}
+		if proof, has := ProofMap[pubhash]; !has || !bls.VerifyPossession(pubkey, proof) {
+			return ErrInvalidObserverBLSProof
+		}
+		blsKeyMap[pubhash] = pubkey.Clone()
+	}
+	cs.observerBLSKeyMap = blsKeyMap
+	return nil
+}
+
+// ObserverBLSKey returns the BLS public key of the observer
+func (cs *Consensus) ObserverBLSKey(pubhash common.PublicHash) (bls.PublicKey, error) {
+	cs.Lock()
+	defer cs.Unlock()
+
+	pubkey, has := cs.observerBLSKeyMap[pubhash]
+	if !has {
+		return bls.PublicKey{}, ErrNotExistObserverBLSKey
+	}
+	return pubkey, nil
+}
+
+// observerOrder returns observers in the order of the signer bitmap
+func (cs *Consensus) observerOrder() []common.PublicHash {
+	list := make([]common.PublicHash, 0, cs.observerKeyMap.Len())
+	cs.observerKeyMap.EachAll(func(pubhash common.PublicHash, value bool) bool {
+		list = append(list, pubhash)
+		return true
+	})
+	return ObserverOrder(list)
+}
+
+// ObserverOrder returns observers in the order of the signer bitmap
+func ObserverOrder(ObserverKeys []common.PublicHash) []common.PublicHash {
+	list := make([]common.PublicHash, len(ObserverKeys))
+	copy(list, ObserverKeys)
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i][:], list[j][:]) < 0
+	})
+	return list
+}
+
+func packedBLSSignatureCount(ObserverCount int) int {
+	Size := bls.SignatureSize + (ObserverCount+7)/8
+	return (Size + common.SignatureSize - 1) / common.SignatureSize
+}
+
+// PackBLSSignatures aggregates BLS signatures of observers and packs it with the signer bitmap
+func (cs *Consensus) PackBLSSignatures(SigMap map[common.PublicHash]bls.Signature) ([]common.Signature, error) {
+	order := cs.observerOrder()
+	sigs := make([]bls.Signature, 0, len(SigMap))
+	bitmap := make([]byte, (len(order)+7)/8)
+	for i, pubhash := range order {
+		if sig, has := SigMap[pubhash]; has {
+			sigs = append(sigs, sig)
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+	if len(sigs) != len(SigMap) {
+		return nil, ErrInvalidObserverKey
+	}
+	agg, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, packedBLSSignatureCount(len(order))*common.SignatureSize)
+	copy(data, agg[:])
+	copy(data[bls.SignatureSize:], bitmap)
+
+	packed := make([]common.Signature, 0, len(data)/common.SignatureSize)
+	for i := 0; i < len(data); i += common.SignatureSize {
+		var sig common.Signature
+		copy(sig[:], data[i:])
+		packed = append(packed, sig)
+	}
+	return packed, nil
+}
+
+// validateBLSSignatures checks that the quorum of observers signed the block sign by the packed BLS signature
+func (cs *Consensus) validateBLSSignatures(bs *types.BlockSign, packed []common.Signature) error {
+	cs.Lock()
+	blsKeyMap := cs.observerBLSKeyMap
+	cs.Unlock()
+
+	return ValidateBLSSignatures(bs, packed, cs.observerOrder(), blsKeyMap, cs.ObserverQuorum())
+}
+
+// ValidateBLSSignatures checks that the quorum of observers signed the block sign by the packed BLS signature
+// Observers should be in the order of ObserverOrder
+func ValidateBLSSignatures(bs *types.BlockSign, packed []common.Signature, Observers []common.PublicHash, BLSKeyMap map[common.PublicHash]bls.PublicKey, Quorum int) error {
+	if len(packed) != packedBLSSignatureCount(len(Observers)) {
+		return ErrInvalidSignatureCount
+	}
+	data := make([]byte, 0, len(packed)*common.SignatureSize)
+	for _, sig := range packed {
+		data = append(data, sig[:]...)
+	}
+	var agg bls.Signature
+	copy(agg[:], data)
+	bitmap := data[bls.SignatureSize:]
+
+	pubkeys := []bls.PublicKey{}
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		if i >= len(Observers) {
+			return ErrInvalidSignerBitmap
+		}
+		pubkey, has := BLSKeyMap[Observers[i]]
+		if !has {
+			return ErrNotExistObserverBLSKey
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	if len(pubkeys) < Quorum {
+		return ErrInsufficientSignerCount
+	}
+	if !bls.VerifyAggregated(pubkeys, encoding.Hash(bs), agg) {
+		return ErrInvalidAggregatedSignature
+	}
+	return nil
+}
@@ -18,22 +18,35 @@ func (cs *Consensus) Candidates() []*Rank {
 }
 
 func (cs *Consensus) updateFormulatorList(ctw *types.ContextWrapper) error {
+	DeletedAccounts := []common.Address{}
+	ctw.Top().DeletedAccountMap.EachAll(func(addr common.Address, a types.Account) bool {
+		if acc, is := a.(FormulatorAccount); is && acc.IsFormulator() {
+			DeletedAccounts = append(DeletedAccounts, acc.Address())
+		}
+		return true
+	})
+	return updateRankTable(cs.rt, phaseDiffFunc(cs.maxPhaseDiff, cs.maxPhaseDiffPolicy, cs.hasPolicy), ctw.TargetHeight(), ctw.Top().AccountMap, DeletedAccounts)
+}
+
+// updateRankTable applies formulator account changes of the block to the rank table
+// Removing addresses that are not in the rank table has no effect, so deleted accounts can include other accounts
+func updateRankTable(rt *RankTable, maxPhaseDiff func(Height uint32) uint32, TargetHeight uint32, AccountMap *types.AddressAccountMap, DeletedAccounts []common.Address) error {
 	var inErr error
-	phase := cs.rt.smallestPhase() + 2
-	if cs.maxPhaseDiff != nil {
-		diff := cs.maxPhaseDiff(ctw.TargetHeight())
-		phase = cs.rt.largestPhase() + 1
+	phase := rt.smallestPhase() + 2
+	if maxPhaseDiff != nil {
+		diff := maxPhaseDiff(TargetHeight)
+		phase = rt.largestPhase() + 1
 		if diff != 0 {
-			maxPhase := cs.rt.smallestPhase() + diff
+			maxPhase := rt.smallestPhase() + diff
 			if phase > maxPhase {
 				phase = maxPhase
 			}
-			if cs.rt.largestPhase() > maxPhase {
-				list := cs.rt.Candidates()
+			if rt.largestPhase() > maxPhase {
+				list := rt.Candidates()
 				for _, r := range list {
 					if r.phase > maxPhase {
-						cs.rt.removeRank(r.Address)
-						if err := cs.rt.addRank(NewRank(r.Address, r.PublicHash, maxPhase, r.hashSpace)); err != nil {
+						rt.removeRank(r.Address)
+						if err := rt.addRank(NewRank(r.Address, r.PublicHash, maxPhase, r.hashSpace)); err != nil {
 							return err
 						}
 					}
@@ -41,24 +54,24 @@ func (cs *Consensus) updateFormulatorList(ctw *types.ContextWrapper) error {
 			}
 		}
 	}
-	ctw.Top().AccountMap.EachAll(func(addr common.Address, a types.Account) bool {
+	AccountMap.EachAll(func(addr common.Address, a types.Account) bool {
 		if acc, is := a.(FormulatorAccount); is && acc.IsFormulator() {
-			if a.Address().Height() == ctw.TargetHeight() {
+			if a.Address().Height() == TargetHeight {
 				if acc.IsActivated() {
-					if err := cs.rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
+					if err := rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
 						inErr = err
 						return false
 					}
 				}
 			} else {
-				r, has := cs.rt.rankMap[addr]
+				r, has := rt.rankMap[addr]
 				if has {
 					if !acc.IsActivated() {
-						cs.rt.removeRank(addr)
+						rt.removeRank(addr)
 					} else {
 						if r.PublicHash != acc.GeneratorHash() {
-							cs.rt.removeRank(addr)
-							if err := cs.rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
+							rt.removeRank(addr)
+							if err := rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
 								inErr = err
 								return false
 							}
@@ -66,7 +79,7 @@ func (cs *Consensus) updateFormulatorList(ctw *types.ContextWrapper) error {
 					}
 				} else {
 					if acc.IsActivated() {
-						if err := cs.rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
+						if err := rt.addRank(NewRank(addr, acc.GeneratorHash(), phase, hash.DoubleHash(addr[:]))); err != nil {
 							inErr = err
 							return false
 						}
@@ -79,13 +92,10 @@ func (cs *Consensus) updateFormulatorList(ctw *types.ContextWrapper) error {
 	if inErr != nil {
 		return inErr
 	}
-	ctw.Top().DeletedAccountMap.EachAll(func(addr common.Address, a types.Account) bool {
-		if acc, is := a.(FormulatorAccount); is && acc.IsFormulator() {
-			cs.rt.removeRank(acc.Address())
-		}
-		return true
-	})
-	if cs.rt.CandidateCount() == 0 {
+	for _, addr := range DeletedAccounts {
+		rt.removeRank(addr)
+	}
+	if rt.CandidateCount() == 0 {
 		return ErrInsufficientCandidateCount
 	}
 	return nil
@@ -93,12 +103,7 @@ func (cs *Consensus) updateFormulatorList(ctw *types.ContextWrapper) error {
 
 // DecodeConsensusData decodes header's consensus data
 func (cs *Consensus) DecodeConsensusData(ConsensusData []byte) (uint32, error) {
-	dec := encoding.NewDecoder(bytes.NewReader(ConsensusData))
-	TimeoutCount, err := dec.DecodeUint32()
-	if err != nil {
-		return 0, err
-	}
-	return TimeoutCount, nil
+	return types.DecodeTimeoutCount(ConsensusData)
 }
 
 func (cs *Consensus) encodeConsensusData(TimeoutCount uint32) ([]byte, error) {
@@ -110,6 +115,32 @@ func (cs *Consensus) encodeConsensusData(TimeoutCount uint32) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+// forwardRankTable forwards the rank table by the block and returns the updated count of blocks by the same formulator
+// The seed is the header hash or the VRF output of the block by nextSeed
+func forwardRankTable(rt *RankTable, TimeoutCount uint32, Seed hash.Hash256, BlocksBySameFormulator uint32, MaxBlocksPerFormulator uint32) (uint32, error) {
+	if TimeoutCount > 0 {
+		if err := rt.forwardCandidates(int(TimeoutCount)); err != nil {
+			return 0, err
+		}
+		BlocksBySameFormulator = 0
+	}
+	BlocksBySameFormulator++
+	if BlocksBySameFormulator >= MaxBlocksPerFormulator {
+		rt.forwardTop(Seed)
+		BlocksBySameFormulator = 0
+	}
+	return BlocksBySameFormulator, nil
+}
+
+// SaveData returns the saved data of the consensus at the last connected block
+// It is the initial state of the RankTracker
+func (cs *Consensus) SaveData() ([]byte, error) {
+	cs.Lock()
+	defer cs.Unlock()
+
+	return cs.buildSaveData()
+}
+
 func (cs *Consensus) buildSaveData() ([]byte, error) {
 	var buffer bytes.Buffer
 	enc := encoding.NewEncoder(&buffer)
@@ -125,5 +156,100 @@ func (cs *Consensus) buildSaveData() ([]byte, error) {
 	if err := enc.Encode(cs.rt); err != nil {
 		return nil, err
 	}
+	// appended fields are omitted until they are set to keep the genesis data of existing chains
+	if cs.seed != (hash.Hash256{}) || cs.observerQuorum != 0 || cs.maxPhaseDiffPolicy != 0 || cs.hasPolicy {
+		if err := enc.Encode(cs.seed); err != nil {
+			return nil, err
+		}
+		if err := enc.EncodeUint32(cs.observerQuorum); err != nil {
+			return nil, err
+		}
+		if err := enc.EncodeUint32(cs.maxPhaseDiffPolicy); err != nil {
+			return nil, err
+		}
+	}
+	if cs.hasPolicy {
+		if err := enc.EncodeBool(cs.hasPolicy); err != nil {
+			return nil, err
+		}
+	}
 	return buffer.Bytes(), nil
 }
+
+// consensusSaveData is the decoded saved data of the consensus
+type consensusSaveData struct {
+	MaxBlocksPerFormulator uint32
+	ObserverKeyMap         *types.PublicHashBoolMap
+	BlocksBySameFormulator uint32
+	rt                     *RankTable
+	Seed                   hash.Hash256
+	ObserverQuorum         uint32
+	MaxPhaseDiff           uint32
+	HasPolicy              bool
+}
+
+// decodeSaveData decodes the saved data of the consensus
+// The seed and the policy are appended later, so they are zero when the saved data is written before them
+func decodeSaveData(data []byte) (*consensusSaveData, error) {
+	r := bytes.NewReader(data)
+	dec := encoding.NewDecoder(r)
+	sd := &consensusSaveData{}
+	if v, err := dec.DecodeUint32(); err != nil {
+		return nil, err
+	} else {
+		sd.MaxBlocksPerFormulator = v
+	}
+	sd.ObserverKeyMap = types.NewPublicHashBoolMap()
+	if err := dec.Decode(&sd.ObserverKeyMap); err != nil {
+		return nil, err
+	}
+	if v, err := dec.DecodeUint32(); err != nil {
+		return nil, err
+	} else {
+		sd.BlocksBySameFormulator = v
+	}
+	sd.rt = NewRankTable()
+	if err := dec.Decode(&sd.rt); err != nil {
+		return nil, err
+	}
+	if r.Len() > 0 {
+		if err := dec.Decode(&sd.Seed); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() > 0 {
+		if v, err := dec.DecodeUint32(); err != nil {
+			return nil, err
+		} else {
+			sd.ObserverQuorum = v
+		}
+		if v, err := dec.DecodeUint32(); err != nil {
+			return nil, err
+		} else {
+			sd.MaxPhaseDiff = v
+		}
+	}
+	if r.Len() > 0 {
+		if v, err := dec.DecodeBool(); err != nil {
+			return nil, err
+		} else {
+			sd.HasPolicy = v
+		}
+	}
+	return sd, nil
+}
+
+func isSameObserverKeyMap(a *types.PublicHashBoolMap, b *types.PublicHashBoolMap) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	isSame := true
+	a.EachAll(func(pubhash common.PublicHash, value bool) bool {
+		if !b.Has(pubhash) {
+			isSame = false
+			return false
+		}
+		return true
+	})
+	return isSame
+}
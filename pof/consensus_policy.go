@@ -0,0 +1,160 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+)
+
+// ConsensusParams is the consensus policy of the last block and the pending policy that is reported by the rpc
+type ConsensusParams struct {
+	MaxBlocksPerFormulator uint32
+	ObserverQuorum         int
+	MaxPhaseDiff           uint32
+	Pending                *admin.PendingConsensusPolicy
+}
+
+// MarshalJSON is a marshaler function
+func (cp *ConsensusParams) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"max_blocks_per_formulator":`)
+	if bs, err := json.Marshal(cp.MaxBlocksPerFormulator); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"observer_quorum":`)
+	if bs, err := json.Marshal(cp.ObserverQuorum); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"max_phase_diff":`)
+	if bs, err := json.Marshal(cp.MaxPhaseDiff); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"pending":`)
+	if cp.Pending == nil {
+		buffer.WriteString(`null`)
+	} else if bs, err := cp.Pending.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// Params returns the consensus policy of the last block and the pending policy
+func (cs *Consensus) Params() (*ConsensusParams, error) {
+	cs.Lock()
+	cp := &ConsensusParams{
+		MaxBlocksPerFormulator: cs.maxBlocksPerFormulator,
+		ObserverQuorum:         observerQuorum(cs.observerKeyMap.Len(), cs.observerQuorum),
+		MaxPhaseDiff:           cs.maxPhaseDiffPolicy,
+	}
+	cs.Unlock()
+
+	if cs.admin != nil {
+		pc, err := cs.admin.PendingConsensusPolicy(cs.cn.Provider().NewContextWrapper(0))
+		if err != nil {
+			if err != admin.ErrNotExistPendingConsensusPolicy {
+				return nil, err
+			}
+		} else {
+			cp.Pending = pc
+		}
+	}
+	return cp, nil
+}
+
+// ObserverQuorum returns the number of observer signatures that is required for a block
+func (cs *Consensus) ObserverQuorum() int {
+	cs.Lock()
+	defer cs.Unlock()
+
+	return observerQuorum(cs.observerKeyMap.Len(), cs.observerQuorum)
+}
+
+// evidenceValidBlocks returns the evidence valid blocks of the slash policy of the formulator process (0 means no limit)
+func (cs *Consensus) evidenceValidBlocks() uint32 {
+	if cs.formulator == nil {
+		return 0
+	}
+	policy, err := cs.formulator.GetSlashPolicy(cs.cn.Provider().NewContextWrapper(0))
+	if err != nil {
+		return 0
+	}
+	return policy.EvidenceValidBlocks
+}
+
+// roundVoteQuorum returns the number of round votes that starts the round vote ack
+// It is one more than the observer quorum as like the majority plus one of the original rule
+func (cs *Consensus) roundVoteQuorum() int {
+	Quorum := cs.ObserverQuorum() + 1
+	if Quorum > cs.observerKeyMap.Len() {
+		Quorum = cs.observerKeyMap.Len()
+	}
+	return Quorum
+}
+
+// observerQuorum returns the quorum of the policy value
+// The quorum cannot be lower than the majority, so the lower value including 0 means the majority
+func observerQuorum(ObserverCount int, Quorum uint32) int {
+	q := ObserverCount/2 + 1
+	if int(Quorum) > q && int(Quorum) <= ObserverCount {
+		q = int(Quorum)
+	}
+	return q
+}
+
+// phaseDiffFunc returns the max phase difference function of the policy value
+// The local function is only used by chains that never set the policy value, so all nodes follow the same on-chain value after it is set
+func phaseDiffFunc(fn func(Height uint32) uint32, MaxPhaseDiff uint32, HasPolicy bool) func(Height uint32) uint32 {
+	if !HasPolicy && MaxPhaseDiff == 0 {
+		return fn
+	}
+	if MaxPhaseDiff == 0 {
+		return nil
+	}
+	return func(Height uint32) uint32 {
+		return MaxPhaseDiff
+	}
+}
+
+// applyPendingPolicy applies the pending policy of the admin process after the block of the previous height of the activation height
+// The applied policy is removed from the admin process in the same context, so it is not reported as pending anymore
+// The top rank is forwarded when the same formulator already generated blocks more than the new limit
+func (cs *Consensus) applyPendingPolicy(ctw *types.ContextWrapper) error {
+	if cs.admin == nil {
+		return nil
+	}
+	pc, err := cs.admin.PendingConsensusPolicy(ctw)
+	if err != nil {
+		if err == admin.ErrNotExistPendingConsensusPolicy {
+			return nil
+		}
+		return err
+	}
+	if pc.ActivationHeight != ctw.TargetHeight()+1 {
+		return nil
+	}
+	cs.maxBlocksPerFormulator = pc.Policy.MaxBlocksPerFormulator
+	cs.observerQuorum = pc.Policy.ObserverQuorum
+	cs.maxPhaseDiffPolicy = pc.Policy.MaxPhaseDiff
+	cs.hasPolicy = true
+	cs.admin.RemovePendingConsensusPolicy(ctw)
+	if cs.blocksBySameFormulator >= cs.maxBlocksPerFormulator {
+		cs.rt.forwardTop(cs.seed)
+		cs.blocksBySameFormulator = 0
+	}
+	return nil
+}
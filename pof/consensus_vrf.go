@@ -0,0 +1,94 @@
+package pof
+
+import (
+	"bytes"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/vrf"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// VRFVersion is the first header version that includes the VRF output of the generator
+// The consensus data of the version is the timeout count followed by the VRF output and the proof of the last seed,
+// and the output becomes the next seed that reshuffles the rank table instead of the header hash
+// The generator cannot grind the output because it is unique for the key of the generator and the last seed
+// It is the later version of BLSSignatureVersion, so blocks of the version also use the aggregated BLS signature
+const VRFVersion = uint16(3)
+
+// IsVRFVersion returns true when the header version includes the VRF output
+func IsVRFVersion(Version uint16) bool {
+	return Version >= VRFVersion
+}
+
+// Seed returns the seed of the next block that is the VRF output of the last block(or the last header hash before VRFVersion)
+func (cs *Consensus) Seed() hash.Hash256 {
+	cs.Lock()
+	defer cs.Unlock()
+
+	return cs.seed
+}
+
+func encodeVRFConsensusData(TimeoutCount uint32, Output hash.Hash256, Proof []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	enc := encoding.NewEncoder(&buffer)
+	if err := enc.EncodeUint32(TimeoutCount); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(Output); err != nil {
+		return nil, err
+	}
+	if err := enc.EncodeBytes(Proof); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeVRFConsensusData(ConsensusData []byte) (uint32, hash.Hash256, []byte, error) {
+	dec := encoding.NewDecoder(bytes.NewReader(ConsensusData))
+	TimeoutCount, err := dec.DecodeUint32()
+	if err != nil {
+		return 0, hash.Hash256{}, nil, err
+	}
+	var Output hash.Hash256
+	if err := dec.Decode(&Output); err != nil {
+		return 0, hash.Hash256{}, nil, err
+	}
+	Proof, err := dec.DecodeBytes()
+	if err != nil {
+		return 0, hash.Hash256{}, nil, err
+	}
+	return TimeoutCount, Output, Proof, nil
+}
+
+// nextSeed returns the seed after the block
+func nextSeed(bh *types.Header) (hash.Hash256, error) {
+	if !IsVRFVersion(bh.Version) {
+		return encoding.Hash(bh), nil
+	}
+	_, Output, _, err := decodeVRFConsensusData(bh.ConsensusData)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	return Output, nil
+}
+
+// validateVRF checks that the VRF output of the header is proved by the generator over the last seed
+func validateVRF(bh *types.Header, pubkey common.PublicKey, Seed hash.Hash256) error {
+	if !IsVRFVersion(bh.Version) {
+		return nil
+	}
+	_, Output, Proof, err := decodeVRFConsensusData(bh.ConsensusData)
+	if err != nil {
+		return err
+	}
+	v, err := vrf.Verify(pubkey, Seed[:], Proof)
+	if err != nil {
+		return err
+	}
+	if v != Output {
+		return ErrInvalidVRFOutput
+	}
+	return nil
+}
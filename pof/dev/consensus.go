@@ -0,0 +1,257 @@
+// Package dev implements the single node consensus for the local development
+// One key generates blocks at the fixed interval or on demand by the dev.mine RPC,
+// so that it can run the chain with same processes without observers and formulators
+package dev
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/chain"
+	"github.com/fletaio/fleta_testnet/core/txpool"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// Consensus generates blocks by the one key
+type Consensus struct {
+	sync.Mutex
+	*chain.ConsensusBase
+	cn        *chain.Chain
+	ct        chain.Committer
+	key       key.Key
+	keyHash   common.PublicHash
+	generator common.Address
+	txpool    *txpool.TransactionPool
+	closeCh   chan struct{}
+	isClose   bool
+}
+
+// NewConsensus returns a Consensus that generates blocks by the key as the generator
+func NewConsensus(Key key.Key, Generator common.Address) *Consensus {
+	cs := &Consensus{
+		key:       Key,
+		keyHash:   common.NewPublicHash(Key.PublicKey()),
+		generator: Generator,
+		txpool:    txpool.NewTransactionPool(),
+		closeCh:   make(chan struct{}),
+	}
+	return cs
+}
+
+// Init initializes the consensus
+func (cs *Consensus) Init(cn *chain.Chain, ct chain.Committer) error {
+	cs.cn = cn
+	cs.ct = ct
+
+	if vs, err := cn.ServiceByName("fleta.apiserver"); err != nil {
+		//ignore when not loaded
+	} else if v, is := vs.(*apiserver.APIServer); !is {
+		//ignore when not loaded
+	} else {
+		s, err := v.JRPC("dev")
+		if err != nil {
+			return err
+		}
+		s.Set("mine", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			Count := 1
+			if arg.Len() > 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			if arg.Len() > 0 {
+				v, err := arg.Int(0)
+				if err != nil {
+					return nil, err
+				}
+				if v < 1 || v > 1000 {
+					return nil, apiserver.ErrInvalidArgument
+				}
+				Count = v
+			}
+			hashes := []hash.Hash256{}
+			for i := 0; i < Count; i++ {
+				b, err := cs.Mine()
+				if err != nil {
+					return nil, err
+				}
+				hashes = append(hashes, encoding.Hash(b.Header))
+			}
+			return hashes, nil
+		})
+		s.Set("sendTx", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() < 2 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			t, err := arg.Uint16(0)
+			if err != nil {
+				return nil, err
+			}
+			arg1, err := arg.String(1)
+			if err != nil {
+				return nil, err
+			}
+			bs, err := hex.DecodeString(arg1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := encoding.Factory("transaction").Create(t)
+			if err != nil {
+				return nil, err
+			}
+			if err := encoding.Unmarshal(bs, v); err != nil {
+				return nil, err
+			}
+			tx, is := v.(types.Transaction)
+			if !is {
+				return nil, ErrInvalidTransactionType
+			}
+			sigs := []common.Signature{}
+			for i := 2; i < arg.Len(); i++ {
+				str, err := arg.String(i)
+				if err != nil {
+					return nil, err
+				}
+				sig, err := common.ParseSignature(str)
+				if err != nil {
+					return nil, err
+				}
+				sigs = append(sigs, sig)
+			}
+			return cs.AddTx(t, tx, sigs)
+		})
+	}
+	return nil
+}
+
+// ValidateSignature called when required to validate signatures
+func (cs *Consensus) ValidateSignature(bh *types.Header, sigs []common.Signature) error {
+	if bh.Generator != cs.generator {
+		return ErrInvalidGenerator
+	}
+	if len(sigs) != 1 {
+		return ErrInvalidSignatureCount
+	}
+	pubkey, err := common.RecoverPubkey(encoding.Hash(bh), sigs[0])
+	if err != nil {
+		return err
+	}
+	if common.NewPublicHash(pubkey) != cs.keyHash {
+		return ErrInvalidGeneratorSignature
+	}
+	return nil
+}
+
+// AddTx validates the transaction and pushes it to the pool, it is included in the next block
+func (cs *Consensus) AddTx(t uint16, tx types.Transaction, sigs []common.Signature) (hash.Hash256, error) {
+	cp := cs.cn.Provider()
+	TxHash := chain.HashTransactionByType(cp.ChainID(), t, tx)
+	signers := make([]common.PublicHash, 0, len(sigs))
+	for _, sig := range sigs {
+		pubkey, err := common.RecoverPubkey(TxHash, sig)
+		if err != nil {
+			return hash.Hash256{}, err
+		}
+		signers = append(signers, common.NewPublicHash(pubkey))
+	}
+	if atx, is := tx.(chain.AccountTransaction); is {
+		if atx.Seq() <= cp.Seq(atx.From()) {
+			return hash.Hash256{}, txpool.ErrPastSeq
+		}
+	}
+	pid := uint8(t >> 8)
+	p, err := cs.cn.Process(pid)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	ctw := types.NewContextWrapper(pid, cs.ct.NewContext())
+	if err := tx.Validate(p, ctw, signers); err != nil {
+		return hash.Hash256{}, err
+	}
+	if err := cs.txpool.Push(cp.ChainID(), t, TxHash, tx, sigs, signers); err != nil {
+		return hash.Hash256{}, err
+	}
+	return TxHash, nil
+}
+
+// Mine generates a block that includes transactions of the pool and connects it
+func (cs *Consensus) Mine() (*types.Block, error) {
+	cs.Lock()
+	defer cs.Unlock()
+
+	if cs.isClose {
+		return nil, ErrConsensusClosed
+	}
+
+	ctx := cs.ct.NewContext()
+	bc := chain.NewBlockCreator(cs.cn, ctx, cs.generator, []byte{})
+	if err := bc.Init(); err != nil {
+		return nil, err
+	}
+	for {
+		sn := ctx.Snapshot()
+		item := cs.txpool.Pop(ctx)
+		ctx.Revert(sn)
+		if item == nil {
+			break
+		}
+		if err := bc.UnsafeAddTx(cs.generator, item.TxType, item.TxHash, item.Transaction, item.Signatures, item.Signers); err != nil {
+			continue
+		}
+	}
+
+	Timestamp := uint64(time.Now().UnixNano())
+	if Timestamp <= ctx.LastTimestamp() {
+		Timestamp = ctx.LastTimestamp() + 1
+	}
+	b, err := bc.Finalize(Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := cs.key.Sign(encoding.Hash(b.Header))
+	if err != nil {
+		return nil, err
+	}
+	b.Signatures = append(b.Signatures, sig)
+
+	if err := cs.ct.ValidateHeader(&b.Header); err != nil {
+		return nil, err
+	}
+	if err := cs.ct.ConnectBlockWithContext(b, ctx); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Run generates blocks at the interval until closed (zero interval means that blocks are generated only by Mine)
+func (cs *Consensus) Run(Interval time.Duration) {
+	if Interval <= 0 {
+		<-cs.closeCh
+		return
+	}
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cs.closeCh:
+			return
+		case <-ticker.C:
+			cs.Mine()
+		}
+	}
+}
+
+// Close terminates the consensus
+func (cs *Consensus) Close() {
+	cs.Lock()
+	defer cs.Unlock()
+
+	if !cs.isClose {
+		cs.isClose = true
+		close(cs.closeCh)
+	}
+}
@@ -0,0 +1,12 @@
+package dev
+
+import "errors"
+
+// dev consensus errors
+var (
+	ErrInvalidGenerator          = errors.New("invalid generator")
+	ErrInvalidGeneratorSignature = errors.New("invalid generator signature")
+	ErrInvalidSignatureCount     = errors.New("invalid signature count")
+	ErrConsensusClosed           = errors.New("consensus closed")
+	ErrInvalidTransactionType    = errors.New("invalid transaction type")
+)
@@ -20,4 +20,19 @@ var (
 	ErrAlreadyVoted                  = errors.New("already voted")
 	ErrNotExistObserverPeer          = errors.New("not exist observer peer")
 	ErrNotExistFormulatorPeer        = errors.New("not exist formulator peer")
+	ErrInvalidEvidence               = errors.New("invalid evidence")
+	ErrNotExistObserverBLSKey        = errors.New("not exist observer bls key")
+	ErrInvalidSignerBitmap           = errors.New("invalid signer bitmap")
+	ErrInsufficientSignerCount       = errors.New("insufficient signer count")
+	ErrInvalidAggregatedSignature    = errors.New("invalid aggregated signature")
+	ErrInvalidBLSSignature           = errors.New("invalid bls signature")
+	ErrInvalidObserverBLSProof       = errors.New("invalid observer bls proof")
+	ErrNotHoldLease                  = errors.New("not hold lease")
+	ErrAlreadySignedHeight           = errors.New("already signed height")
+	ErrNotSupportedPlatform          = errors.New("not supported platform")
+	ErrInvalidSignRecord             = errors.New("invalid sign record")
+	ErrAlreadySignedRound            = errors.New("already signed round")
+	ErrGenerationPaused              = errors.New("generation paused")
+	ErrInvalidVRFOutput              = errors.New("invalid vrf output")
+	ErrNotSupportedVRF               = errors.New("not supported vrf")
 )
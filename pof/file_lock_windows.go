@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package pof
+
+import (
+	"os"
+)
+
+func lockFile(file *os.File) error {
+	return ErrNotSupportedPlatform
+}
+
+func unlockFile(file *os.File) error {
+	return ErrNotSupportedPlatform
+}
@@ -0,0 +1,156 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// FormulatorStatus is the runtime status of the formulator that is reported by the admin rpc
+type FormulatorStatus struct {
+	Height        uint32
+	IsActive      bool
+	IsPaused      bool
+	LastGenHeight uint32
+	LastGenTime   int64
+	PoolSize      int
+}
+
+// MarshalJSON is a marshaler function
+func (st *FormulatorStatus) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(st.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"is_active":`)
+	if bs, err := json.Marshal(st.IsActive); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"is_paused":`)
+	if bs, err := json.Marshal(st.IsPaused); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"last_gen_height":`)
+	if bs, err := json.Marshal(st.LastGenHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"last_gen_time":`)
+	if bs, err := json.Marshal(st.LastGenTime); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"pool_size":`)
+	if bs, err := json.Marshal(st.PoolSize); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// Pause stops generating blocks until resumed
+// It disconnects observers like the standby instance so that observers skip the formulator without timeouts,
+// and the formulator keeps syncing blocks by the node mesh while paused
+func (fr *FormulatorNode) Pause() {
+	fr.leaseLock.Lock()
+	defer fr.leaseLock.Unlock()
+
+	fr.isPaused = true
+	fr.updateMeshActive()
+}
+
+// Resume restarts generating blocks that is stopped by the pause
+func (fr *FormulatorNode) Resume() {
+	fr.leaseLock.Lock()
+	defer fr.leaseLock.Unlock()
+
+	fr.isPaused = false
+	fr.updateMeshActive()
+}
+
+// updateMeshActive should be called with the lease lock
+func (fr *FormulatorNode) updateMeshActive() {
+	fr.ms.SetActive((fr.lease == nil || fr.isLeaseHeld) && !fr.isPaused)
+}
+
+// IsPaused returns true when generating blocks is stopped by the pause
+func (fr *FormulatorNode) IsPaused() bool {
+	fr.leaseLock.Lock()
+	defer fr.leaseLock.Unlock()
+
+	return fr.isPaused
+}
+
+// Status returns the runtime status of the formulator
+func (fr *FormulatorNode) Status() *FormulatorStatus {
+	st := &FormulatorStatus{
+		Height:   fr.cs.cn.Provider().Height(),
+		IsActive: fr.IsActive(),
+		IsPaused: fr.IsPaused(),
+		PoolSize: fr.txpool.Size(),
+	}
+	fr.Lock()
+	st.LastGenHeight = fr.lastGenHeight
+	st.LastGenTime = fr.lastGenTime
+	fr.Unlock()
+	return st
+}
+
+// ObserverPeers returns connection statuses of observers
+func (fr *FormulatorNode) ObserverPeers() []*PeerStatus {
+	list := []*PeerStatus{}
+	for _, p := range fr.ms.Peers() {
+		var pubhash common.PublicHash
+		copy(pubhash[:], []byte(p.ID()))
+		list = append(list, &PeerStatus{
+			ID:            pubhash.String(),
+			Name:          p.Name(),
+			GuessHeight:   p.GuessHeight(),
+			ConnectedTime: p.ConnectedTime(),
+		})
+	}
+	sortPeerStatuses(list)
+	return list
+}
+
+// InitAdmin registers the admin rpc of the formulator to the api server that is bound to the admin port
+func (fr *FormulatorNode) InitAdmin(as *apiserver.APIServer) error {
+	js, err := as.JRPC("admin")
+	if err != nil {
+		return err
+	}
+	js.Set("status", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return fr.Status(), nil
+	})
+	js.Set("observers", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return fr.ObserverPeers(), nil
+	})
+	js.Set("pause", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		fr.Pause()
+		return true, nil
+	})
+	js.Set("resume", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		fr.Resume()
+		return true, nil
+	})
+	return nil
+}
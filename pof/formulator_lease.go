@@ -0,0 +1,162 @@
+package pof
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// FormulatorLease allows only one of instances of the same formulator to be active
+// The active instance connects to observers and generates blocks, standby instances only sync blocks by the node mesh
+type FormulatorLease interface {
+	// Hold acquires or renews the lease and returns true when this instance holds it
+	Hold() (bool, error)
+	// Release gives up the lease so that a standby instance can take over without waiting the expiration
+	Release() error
+	// RecordSign records the sign record before the header is signed, it fails when the lease is not held
+	// or another header is recorded in the same or a later round (the height and the timeout count) by any instance
+	RecordSign(rec *SignRecord) error
+}
+
+type fileLeaseRecord struct {
+	Holder           string
+	Expiry           int64
+	LastHeight       uint32
+	LastTimeoutCount uint32
+	LastHash         hash.Hash256
+}
+
+// FileLease is the FormulatorLease that is held by the lease file on the storage shared between instances
+// Updates of the file are serialized by the file lock, so the storage should support it (e.g. the local disk or NFSv4)
+// Instances compare expiry times by their clocks, so the TTL should be much larger than the clock difference of hosts
+type FileLease struct {
+	sync.Mutex
+	path   string
+	holder string
+	ttl    time.Duration
+	clock  Clock
+}
+
+// NewFileLease returns a FileLease of the holder that is expired after the TTL without renewal
+func NewFileLease(Path string, Holder string, TTL time.Duration) *FileLease {
+	return &FileLease{
+		path:   Path,
+		holder: Holder,
+		ttl:    TTL,
+		clock:  &systemClock{},
+	}
+}
+
+// SetClock sets the clock of the lease
+func (fl *FileLease) SetClock(clock Clock) {
+	fl.Lock()
+	defer fl.Unlock()
+
+	fl.clock = clock
+}
+
+// TTL returns the time to live of the lease
+func (fl *FileLease) TTL() time.Duration {
+	return fl.ttl
+}
+
+// Hold acquires or renews the lease and returns true when this instance holds it
+func (fl *FileLease) Hold() (bool, error) {
+	var isHeld bool
+	if err := fl.update(func(rec *fileLeaseRecord, now int64) bool {
+		if rec.Holder != fl.holder && rec.Expiry > now {
+			return false
+		}
+		rec.Holder = fl.holder
+		rec.Expiry = now + int64(fl.ttl)
+		isHeld = true
+		return true
+	}); err != nil {
+		return false, err
+	}
+	return isHeld, nil
+}
+
+// Release gives up the lease so that a standby instance can take over without waiting the expiration
+func (fl *FileLease) Release() error {
+	return fl.update(func(rec *fileLeaseRecord, now int64) bool {
+		if rec.Holder != fl.holder {
+			return false
+		}
+		rec.Expiry = 0
+		return true
+	})
+}
+
+// RecordSign records the sign record before the header is signed
+// Only the last round is kept, so the round before it is refused because it cannot be checked
+func (fl *FileLease) RecordSign(sr *SignRecord) error {
+	var inErr error
+	if err := fl.update(func(rec *fileLeaseRecord, now int64) bool {
+		if rec.Holder != fl.holder || rec.Expiry <= now {
+			inErr = ErrNotHoldLease
+			return false
+		}
+		if sr.Height < rec.LastHeight || (sr.Height == rec.LastHeight && sr.TimeoutCount < rec.LastTimeoutCount) {
+			inErr = ErrAlreadySignedHeight
+			return false
+		}
+		if sr.Height == rec.LastHeight && sr.TimeoutCount == rec.LastTimeoutCount && sr.HeaderHash != rec.LastHash {
+			inErr = ErrAlreadySignedHeight
+			return false
+		}
+		rec.LastHeight = sr.Height
+		rec.LastTimeoutCount = sr.TimeoutCount
+		rec.LastHash = sr.HeaderHash
+		return true
+	}); err != nil {
+		return err
+	}
+	return inErr
+}
+
+// update applies the function to the record under the file lock, the record is written only when the function returns true
+func (fl *FileLease) update(fn func(rec *fileLeaseRecord, now int64) bool) error {
+	fl.Lock()
+	defer fl.Unlock()
+
+	file, err := os.OpenFile(fl.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := lockFile(file); err != nil {
+		return err
+	}
+	defer unlockFile(file)
+
+	bs, err := ioutil.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	rec := &fileLeaseRecord{}
+	if len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, rec); err != nil {
+			return err
+		}
+	}
+	if !fn(rec, fl.clock.Now().UnixNano()) {
+		return nil
+	}
+	data, err := encoding.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt(data, 0); err != nil {
+		return err
+	}
+	return file.Sync()
+}
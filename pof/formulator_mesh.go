@@ -23,6 +23,8 @@ type FormulatorNodeMesh struct {
 	key           key.Key
 	netAddressMap map[common.PublicHash]string
 	peerMap       map[string]peer.Peer
+	isActive      bool
+	isClose       bool
 }
 
 func NewFormulatorNodeMesh(key key.Key, NetAddressMap map[common.PublicHash]string, fr *FormulatorNode) *FormulatorNodeMesh {
@@ -30,6 +32,7 @@ func NewFormulatorNodeMesh(key key.Key, NetAddressMap map[common.PublicHash]stri
 		key:           key,
 		netAddressMap: NetAddressMap,
 		peerMap:       map[string]peer.Peer{},
+		isActive:      true,
 		fr:            fr,
 	}
 	return ms
@@ -39,22 +42,59 @@ func NewFormulatorNodeMesh(key key.Key, NetAddressMap map[common.PublicHash]stri
 func (ms *FormulatorNodeMesh) Run() {
 	for PubHash, v := range ms.netAddressMap {
 		go func(pubhash common.PublicHash, NetAddr string) {
-			time.Sleep(1 * time.Second)
+			ms.fr.clock.Sleep(1 * time.Second)
 			for {
 				ms.Lock()
+				isClose := ms.isClose
 				_, has := ms.peerMap[string(pubhash[:])]
+				isActive := ms.isActive
 				ms.Unlock()
-				if !has {
+				if isClose {
+					return
+				}
+				if !has && isActive {
 					if err := ms.client(NetAddr, pubhash); err != nil {
 						rlog.Println("[client]", err, NetAddr)
 					}
 				}
-				time.Sleep(1 * time.Second)
+				ms.fr.clock.Sleep(1 * time.Second)
 			}
 		}(PubHash, v)
 	}
 }
 
+// SetActive sets whether the mesh connects to observers or not, it disconnects all peers when inactivated
+func (ms *FormulatorNodeMesh) SetActive(isActive bool) {
+	ms.Lock()
+	ms.isActive = isActive
+	peers := []peer.Peer{}
+	if !isActive {
+		for _, p := range ms.peerMap {
+			peers = append(peers, p)
+		}
+	}
+	ms.Unlock()
+
+	for _, p := range peers {
+		p.Close()
+	}
+}
+
+// Close terminates the formulator mesh and disconnects all peers
+func (ms *FormulatorNodeMesh) Close() {
+	ms.Lock()
+	ms.isClose = true
+	peers := []peer.Peer{}
+	for _, p := range ms.peerMap {
+		peers = append(peers, p)
+	}
+	ms.Unlock()
+
+	for _, p := range peers {
+		p.Close()
+	}
+}
+
 // Peers returns peers of the formulator mesh
 func (ms *FormulatorNodeMesh) Peers() []peer.Peer {
 	ms.Lock()
@@ -162,7 +202,7 @@ func (ms *FormulatorNodeMesh) client(Address string, TargetPubHash common.Public
 		}
 		defer conn.Close()
 	*/
-	conn, err := net.DialTimeout("tcp", Address, 10*time.Second)
+	conn, err := ms.fr.transport.DialTimeout(Address, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -185,8 +225,8 @@ func (ms *FormulatorNodeMesh) client(Address string, TargetPubHash common.Public
 	}
 
 	ID := string(pubhash[:])
-	//p := p2p.NewWebsocketPeer(conn, ID, pubhash.String(), time.Now().UnixNano())
-	p := p2p.NewTCPPeer(conn, ID, pubhash.String(), time.Now().UnixNano())
+	//p := p2p.NewWebsocketPeer(conn, ID, pubhash.String(), ms.fr.clock.Now().UnixNano())
+	p := p2p.NewTCPPeer(conn, ID, pubhash.String(), ms.fr.clock.Now().UnixNano())
 	ms.RemovePeer(ID)
 	ms.Lock()
 	ms.peerMap[ID] = p
@@ -229,7 +269,7 @@ func (ms *FormulatorNodeMesh) recvHandshake(conn net.Conn) error {
 		return chain.ErrInvalidChainID
 	}
 	timestamp := binary.LittleEndian.Uint64(req[32:])
-	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	diff := time.Duration(uint64(ms.fr.clock.Now().UnixNano()) - timestamp)
 	if diff < 0 {
 		diff = -diff
 	}
@@ -237,7 +277,7 @@ func (ms *FormulatorNodeMesh) recvHandshake(conn net.Conn) error {
 		return p2p.ErrInvalidHandshake
 	}
 	//rlog.Println("sendHandshakeAck")
-	if sig, err := ms.key.Sign(hash.Hash(req)); err != nil {
+	if sig, err := signHandshake(ms.key, req); err != nil {
 		return err
 	} else if _, err := conn.Write(sig[:]); err != nil {
 		return err
@@ -252,7 +292,7 @@ func (ms *FormulatorNodeMesh) sendHandshake(conn net.Conn) (common.PublicHash, e
 		return common.PublicHash{}, err
 	}
 	req[0] = ms.fr.cs.cn.Provider().ChainID()
-	binary.LittleEndian.PutUint64(req[32:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(req[32:], uint64(ms.fr.clock.Now().UnixNano()))
 	copy(req[40:], ms.fr.Config.Formulator[:])
 	if _, err := conn.Write(req); err != nil {
 		return common.PublicHash{}, err
@@ -285,7 +325,7 @@ func (ms *FormulatorNodeMesh) recvHandshake(conn *websocket.Conn) error {
 		return chain.ErrInvalidChainID
 	}
 	timestamp := binary.LittleEndian.Uint64(req[32:])
-	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	diff := time.Duration(uint64(ms.fr.clock.Now().UnixNano()) - timestamp)
 	if diff < 0 {
 		diff = -diff
 	}
@@ -308,7 +348,7 @@ func (ms *FormulatorNodeMesh) sendHandshake(conn *websocket.Conn) (common.Public
 		return common.PublicHash{}, err
 	}
 	req[0] = ms.fr.cs.cn.Provider().ChainID()
-	binary.LittleEndian.PutUint64(req[32:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(req[32:], uint64(ms.fr.clock.Now().UnixNano()))
 	copy(req[40:], ms.fr.Config.Formulator[:])
 	if err := conn.WriteMessage(websocket.BinaryMessage, req); err != nil {
 		return common.PublicHash{}, err
@@ -71,6 +71,14 @@ type FormulatorNode struct {
 	isClose              bool
 	cache                gcache.Cache
 	genMap               map[uint32]*BlockGenMessage
+	clock                Clock
+	transport            p2p.Transport
+	leaseLock            sync.Mutex
+	lease                FormulatorLease
+	leaseInterval        time.Duration
+	isLeaseHeld          bool
+	isPaused             bool
+	signProtection       *SignProtection
 
 	//TEMP
 	Txs      []types.Transaction
@@ -110,8 +118,10 @@ func NewFormulatorNode(Config *FormulatorConfig, key key.Key, ndkey key.Key, Net
 			queue.NewQueue(), //tx
 			queue.NewQueue(), //peer
 		},
-		cache:  gcache.New(500).LRU().Build(),
-		genMap: map[uint32]*BlockGenMessage{},
+		cache:     gcache.New(500).LRU().Build(),
+		genMap:    map[uint32]*BlockGenMessage{},
+		clock:     &systemClock{},
+		transport: p2p.NewTCPTransport(),
 	}
 	fr.ms = NewFormulatorNodeMesh(key, NetAddressMap, fr)
 	fr.nm = p2p.NewNodeMesh(fr.cs.cn.Provider().ChainID(), ndkey, SeedNodeMap, fr, peerStorePath)
@@ -130,6 +140,77 @@ func NewFormulatorNode(Config *FormulatorConfig, key key.Key, ndkey key.Key, Net
 	return fr
 }
 
+// SetClock sets the clock of the formulator (it should be called before running)
+func (fr *FormulatorNode) SetClock(clock Clock) {
+	fr.Lock()
+	defer fr.Unlock()
+
+	fr.clock = clock
+	fr.requestTimer.SetClock(clock)
+	fr.requestNodeTimer.SetClock(clock)
+}
+
+// SetTransport sets the transport of the formulator mesh and the node mesh (it should be called before running)
+func (fr *FormulatorNode) SetTransport(tr p2p.Transport) {
+	fr.Lock()
+	defer fr.Unlock()
+
+	fr.transport = tr
+	fr.nm.SetTransport(tr)
+}
+
+// SetSignProtection sets the durable record of signed headers that is checked before every signature (it should be called before running)
+func (fr *FormulatorNode) SetSignProtection(sp *SignProtection) {
+	fr.Lock()
+	defer fr.Unlock()
+
+	fr.signProtection = sp
+}
+
+// SetLease sets the lease for the active/standby mode, it is renewed at the interval that should be shorter than the TTL of it
+// (it should be called before running)
+func (fr *FormulatorNode) SetLease(lease FormulatorLease, Interval time.Duration) {
+	fr.leaseLock.Lock()
+	defer fr.leaseLock.Unlock()
+
+	fr.lease = lease
+	fr.leaseInterval = Interval
+}
+
+// IsActive returns true when the formulator generates blocks (it is always true without the lease)
+func (fr *FormulatorNode) IsActive() bool {
+	fr.leaseLock.Lock()
+	defer fr.leaseLock.Unlock()
+
+	return fr.lease == nil || fr.isLeaseHeld
+}
+
+func (fr *FormulatorNode) leaseLoop() {
+	fr.leaseLock.Lock()
+	lease := fr.lease
+	Interval := fr.leaseInterval
+	fr.leaseLock.Unlock()
+
+	for !fr.isClose {
+		fr.leaseLock.Lock()
+		if fr.isClose {
+			fr.leaseLock.Unlock()
+			break
+		}
+		isHeld, err := lease.Hold()
+		if err != nil {
+			rlog.Println("Formulator", fr.Config.Formulator.String(), "Lease.Hold", err)
+		}
+		if fr.isLeaseHeld != isHeld {
+			rlog.Println("Formulator", fr.Config.Formulator.String(), "LeaseChanged", isHeld)
+			fr.isLeaseHeld = isHeld
+			fr.updateMeshActive()
+		}
+		fr.leaseLock.Unlock()
+		fr.clock.Sleep(Interval)
+	}
+}
+
 // Close terminates the formulator
 func (fr *FormulatorNode) Close() {
 	fr.closeLock.Lock()
@@ -139,6 +220,16 @@ func (fr *FormulatorNode) Close() {
 	defer fr.Unlock()
 
 	fr.isClose = true
+	fr.leaseLock.Lock()
+	if fr.lease != nil && fr.isLeaseHeld {
+		fr.isLeaseHeld = false
+		if err := fr.lease.Release(); err != nil {
+			rlog.Println("Formulator", fr.Config.Formulator.String(), "Lease.Release", err)
+		}
+	}
+	fr.leaseLock.Unlock()
+	fr.ms.Close()
+	fr.nm.Close()
 	fr.cs.cn.Close()
 }
 
@@ -168,6 +259,12 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 	fr.isRunning = true
 	fr.Unlock()
 
+	fr.leaseLock.Lock()
+	if fr.lease != nil {
+		fr.ms.SetActive(false)
+		go fr.leaseLoop()
+	}
+	fr.leaseLock.Unlock()
 	go fr.ms.Run()
 	go fr.nm.Run(BindAddress)
 	go fr.requestTimer.Run()
@@ -212,7 +309,7 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 						break
 					}
 				}
-				time.Sleep(50 * time.Millisecond)
+				fr.clock.Sleep(50 * time.Millisecond)
 			}
 		}()
 	}
@@ -227,8 +324,8 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 
 	go func() {
 		for !fr.isClose {
-			hasMessage := false
 			for !fr.isClose {
+				hasMessage := false
 				for _, rq := range fr.recvQueues {
 					if v := rq.Pop(); v != nil {
 						hasMessage = true
@@ -244,20 +341,24 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 					break
 				}
 			}
-			time.Sleep(10 * time.Millisecond)
+			fr.clock.Sleep(10 * time.Millisecond)
 		}
 	}()
 	go fr.sendLoop()
 	go fr.sendLoop()
 
-	blockTimer := time.NewTimer(time.Millisecond)
-	blockRequestTimer := time.NewTimer(time.Millisecond)
+	blockTimer := fr.clock.NewTimer(time.Millisecond)
+	blockRequestTimer := fr.clock.NewTimer(time.Millisecond)
 	for !fr.isClose {
 		select {
-		case <-blockTimer.C:
+		case <-blockTimer.C():
 			p := debug.Start("LockWait")
 			fr.Lock()
 			p.Stop()
+			if fr.isClose {
+				fr.Unlock()
+				break
+			}
 			hasItem := false
 			TargetHeight := uint64(fr.cs.cn.Provider().Height() + 1)
 			Count := 0
@@ -286,7 +387,7 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 			}
 
 			blockTimer.Reset(50 * time.Millisecond)
-		case <-blockRequestTimer.C:
+		case <-blockRequestTimer.C():
 			fr.tryRequestBlocks()
 			fr.tryRequestNext()
 			blockRequestTimer.Reset(500 * time.Millisecond)
@@ -296,8 +397,8 @@ func (fr *FormulatorNode) Run(BindAddress string) {
 
 func (fr *FormulatorNode) sendLoop() {
 	for !fr.isClose {
-		hasMessage := false
 		for !fr.isClose {
+			hasMessage := false
 			for _, sq := range fr.sendQueues {
 				if v := sq.Pop(); v != nil {
 					hasMessage = true
@@ -336,7 +437,7 @@ func (fr *FormulatorNode) sendLoop() {
 				break
 			}
 		}
-		time.Sleep(10 * time.Millisecond)
+		fr.clock.Sleep(10 * time.Millisecond)
 	}
 }
 
@@ -484,10 +585,10 @@ func (fr *FormulatorNode) onObserverRecv(ID string, m interface{}) error {
 	case *BlockObSignMessage:
 		rlog.Println("ObSignMessage", base58.Encode([]byte(ID[:])), reflect.ValueOf(m).Elem().Type().Name(), msg.TargetHeight)
 		fr.Lock()
-		defer fr.Unlock()
-
 		GenMessage, has := fr.genMap[msg.TargetHeight]
 		if has {
+			defer fr.Unlock()
+
 			b := &types.Block{
 				Header:                GenMessage.Block.Header,
 				TransactionTypes:      GenMessage.Block.TransactionTypes,
@@ -500,8 +601,10 @@ func (fr *FormulatorNode) onObserverRecv(ID string, m interface{}) error {
 				return err
 			}
 			delete(fr.genMap, msg.TargetHeight)
+			return nil
 		}
-		return nil
+		fr.Unlock()
+		// blocks generated by the formulator are not in the gen map, they are connected with contexts of the generation
 	default:
 	}
 	if err := fr.handleObserverMessage(ID, m, 0); err != nil {
@@ -701,7 +804,7 @@ func (fr *FormulatorNode) handleObserverMessage(ID string, m interface{}, RetryC
 		defer fr.Unlock()
 
 		Height := cp.Height()
-		if msg.TargetHeight <= fr.lastGenHeight && fr.lastGenTime+int64(30*time.Second) > time.Now().UnixNano() {
+		if msg.TargetHeight <= fr.lastGenHeight && fr.lastGenTime+int64(30*time.Second) > fr.clock.Now().UnixNano() {
 			return nil
 		}
 		if fr.lastReqMessage != nil {
@@ -732,7 +835,7 @@ func (fr *FormulatorNode) handleObserverMessage(ID string, m interface{}, RetryC
 				}
 			}
 			go func() {
-				time.Sleep(50 * time.Millisecond)
+				fr.clock.Sleep(50 * time.Millisecond)
 				fr.handleObserverMessage(ID, m, RetryCount+1)
 			}()
 			return nil
@@ -1025,7 +1128,7 @@ func (fr *FormulatorNode) temp() {
 	TxHashes := []hash.Hash256{}
 	for _, Addr := range fr.Config.Addrs {
 		tx := &vault.Transfer{
-			Timestamp_: uint64(time.Now().UnixNano()),
+			Timestamp_: uint64(fr.clock.Now().UnixNano()),
 			From_:      Addr,
 			To:         Addr,
 			Amount:     amount.NewCoinAmount(1, 0),
@@ -1046,14 +1149,20 @@ func (fr *FormulatorNode) temp() {
 }
 
 func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
+	if !fr.IsActive() {
+		return ErrNotHoldLease
+	}
+	if fr.IsPaused() {
+		return ErrGenerationPaused
+	}
 	cp := fr.cs.cn.Provider()
 
 	fr.lastGenMessages = []*BlockGenMessage{}
 	fr.lastObSignMessageMap = map[uint32]*BlockObSignMessage{}
 	fr.lastContextes = []*types.Context{}
 
-	start := time.Now().UnixNano()
-	StartTime := uint64(time.Now().UnixNano())
+	start := fr.clock.Now().UnixNano()
+	StartTime := uint64(fr.clock.Now().UnixNano())
 	StartBlockTime := StartTime
 
 	RemainBlocks := fr.cs.maxBlocksPerFormulator
@@ -1075,6 +1184,7 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 
 	var lastHeader *types.Header
 	ctx := fr.cs.ct.NewContext()
+	Seed := fr.cs.Seed()
 	for i := uint32(0); i < RemainBlocks; i++ {
 		var TimeoutCount uint32
 		if i == 0 {
@@ -1083,9 +1193,9 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 			ctx = ctx.NextContext(encoding.Hash(lastHeader), lastHeader.Timestamp)
 		}
 
-		Timestamp := uint64(time.Now().UnixNano())
+		Timestamp := uint64(fr.clock.Now().UnixNano())
 		log.Println("StartBlockTime", StartBlockTime, Timestamp > StartTime+uint64(3*time.Second), StartTime, Timestamp, StartTime+uint64(3*time.Second))
-		TooFar := uint64(time.Now().UnixNano() + int64(2*time.Second))
+		TooFar := uint64(fr.clock.Now().UnixNano() + int64(2*time.Second))
 		TooClose := StartBlockTime + uint64(i)*uint64(500*time.Millisecond)
 		if Timestamp > TooFar {
 			Timestamp = TooFar
@@ -1097,18 +1207,34 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 			Timestamp = ctx.LastTimestamp() + 1
 		}
 
-		var buffer bytes.Buffer
-		enc := encoding.NewEncoder(&buffer)
-		if err := enc.EncodeUint32(TimeoutCount); err != nil {
-			return err
+		var ConsensusData []byte
+		if IsVRFVersion(ctx.Version()) {
+			Output, Proof, err := proveVRF(fr.key, Seed[:])
+			if err != nil {
+				rlog.Println("Formulator", fr.Config.Formulator.String(), "ProveVRF", ctx.TargetHeight(), err)
+				return err
+			}
+			if data, err := encodeVRFConsensusData(TimeoutCount, Output, Proof); err != nil {
+				return err
+			} else {
+				ConsensusData = data
+			}
+			Seed = Output
+		} else {
+			var buffer bytes.Buffer
+			enc := encoding.NewEncoder(&buffer)
+			if err := enc.EncodeUint32(TimeoutCount); err != nil {
+				return err
+			}
+			ConsensusData = buffer.Bytes()
 		}
-		bc := chain.NewBlockCreator(fr.cs.cn, ctx, msg.Formulator, buffer.Bytes())
+		bc := chain.NewBlockCreator(fr.cs.cn, ctx, msg.Formulator, ConsensusData)
 		if err := bc.Init(); err != nil {
 			return err
 		}
 
 		/*
-				timer := time.NewTimer(200 * time.Millisecond)
+				timer := fr.clock.NewTimer(200 * time.Millisecond)
 
 				rlog.Println("Formulator", fr.Config.Formulator.String(), "BlockGenBegin", msg.TargetHeight)
 
@@ -1117,7 +1243,7 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 			TxLoop:
 				for {
 					select {
-					case <-timer.C:
+					case <-timer.C():
 						break TxLoop
 					default:
 						sn := ctx.Snapshot()
@@ -1160,7 +1286,26 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 		}
 		lastHeader = &b.Header
 
-		if sig, err := fr.key.Sign(encoding.Hash(b.Header)); err != nil {
+		rec, err := NewSignRecord(&b.Header)
+		if err != nil {
+			return err
+		}
+		fr.leaseLock.Lock()
+		lease := fr.lease
+		fr.leaseLock.Unlock()
+		if lease != nil {
+			if err := lease.RecordSign(rec); err != nil {
+				rlog.Println("Formulator", fr.Config.Formulator.String(), "Lease.RecordSign", b.Header.Height, err)
+				return err
+			}
+		}
+		if fr.signProtection != nil {
+			if err := fr.signProtection.Record(rec); err != nil {
+				rlog.Println("Formulator", fr.Config.Formulator.String(), "SignProtection.RecordSign", b.Header.Height, err)
+				return err
+			}
+		}
+		if sig, err := signHeader(fr.key, &b.Header); err != nil {
 			rlog.Println("Formulator", fr.Config.Formulator.String(), "BlockGenMessage.Sign", nm.Block.Header.Height, err)
 			return err
 		} else {
@@ -1180,14 +1325,14 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 		fr.lastGenMessages = append(fr.lastGenMessages, nm)
 		fr.lastContextes = append(fr.lastContextes, ctx)
 		fr.lastGenHeight = ctx.TargetHeight()
-		fr.lastGenTime = time.Now().UnixNano()
+		fr.lastGenTime = fr.clock.Now().UnixNano()
 
 		ExpectedTime := time.Duration(i+1) * 500 * time.Millisecond
 		Threshold := uint32(fr.Config.MaxTransactionsPerBlock) - 3
 		if i >= Threshold {
 			ExpectedTime = 500*time.Duration(Threshold)*time.Millisecond + time.Duration(i-Threshold+1)*200*time.Millisecond
 		}
-		PastTime := time.Duration(time.Now().UnixNano() - start)
+		PastTime := time.Duration(fr.clock.Now().UnixNano() - start)
 		if ExpectedTime > PastTime {
 			IsEnd := false
 			fr.Unlock()
@@ -1195,7 +1340,7 @@ func (fr *FormulatorNode) genBlock(ID string, msg *BlockReqMessage) error {
 				IsEnd = true
 			}
 			if !IsEnd {
-				time.Sleep(ExpectedTime - PastTime)
+				fr.clock.Sleep(ExpectedTime - PastTime)
 				if fr.lastReqMessage == nil {
 					IsEnd = true
 				}
@@ -0,0 +1,121 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// FormulatorStats is the liveness record of the formulator
+type FormulatorStats struct {
+	Address    common.Address
+	Blocks     uint64
+	Timeouts   uint64
+	LastHeight uint32
+}
+
+// MarshalJSON is a marshaler function
+func (st *FormulatorStats) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"address":`)
+	if bs, err := st.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"blocks":`)
+	if bs, err := json.Marshal(st.Blocks); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timeouts":`)
+	if bs, err := json.Marshal(st.Timeouts); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"last_height":`)
+	if bs, err := json.Marshal(st.LastHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// FormulatorStats returns the liveness record of the formulator
+func (cs *Consensus) FormulatorStats(addr common.Address) (*FormulatorStats, error) {
+	return loadFormulatorStats(cs.cn.Provider().NewContextWrapper(0), addr)
+}
+
+// CandidateStats returns liveness records of all candidates in the rank order
+func (cs *Consensus) CandidateStats() ([]*FormulatorStats, error) {
+	ctw := cs.cn.Provider().NewContextWrapper(0)
+	list := []*FormulatorStats{}
+	for _, r := range cs.Candidates() {
+		st, err := loadFormulatorStats(ctw, r.Address)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, st)
+	}
+	return list, nil
+}
+
+// recordFormulatorStats should be called with the lock before forwarding candidates
+// Top ranks that are skipped by the timeout count are recorded as they caused timeouts
+func (cs *Consensus) recordFormulatorStats(ctw *types.ContextWrapper, bh *types.Header, TimeoutCount int) error {
+	if TimeoutCount >= len(cs.rt.candidates) {
+		return ErrExceedCandidateCount
+	}
+	for i := 0; i < TimeoutCount; i++ {
+		st, err := loadFormulatorStats(ctw, cs.rt.candidates[i].Address)
+		if err != nil {
+			return err
+		}
+		st.Timeouts++
+		if err := storeFormulatorStats(ctw, st); err != nil {
+			return err
+		}
+	}
+	st, err := loadFormulatorStats(ctw, bh.Generator)
+	if err != nil {
+		return err
+	}
+	st.Blocks++
+	st.LastHeight = bh.Height
+	if err := storeFormulatorStats(ctw, st); err != nil {
+		return err
+	}
+	return nil
+}
+
+func loadFormulatorStats(ctw *types.ContextWrapper, addr common.Address) (*FormulatorStats, error) {
+	st := &FormulatorStats{
+		Address: addr,
+	}
+	if bs := ctw.ProcessData(toFormulatorStatsKey(addr)); len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, &st); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func storeFormulatorStats(ctw *types.ContextWrapper, st *FormulatorStats) error {
+	bs, err := encoding.Marshal(st)
+	if err != nil {
+		return err
+	}
+	ctw.SetProcessData(toFormulatorStatsKey(st.Address), bs)
+	return nil
+}
@@ -0,0 +1,93 @@
+package pof
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// HeaderSigner is the key that checks headers before signing them such as the remote signer
+// Nodes use it instead of signing hashes of headers and block signs when the key supports it
+type HeaderSigner interface {
+	SignHeader(bh *types.Header) (common.Signature, error)
+	SignBlockSign(bh *types.Header, GeneratorSignature common.Signature) (common.Signature, error)
+}
+
+// MessageSigner is the key that checks consensus messages and handshakes before signing them such as the remote signer
+// Nodes use it instead of signing hashes of them when the key supports it
+type MessageSigner interface {
+	SignRoundVote(vt *RoundVote) (common.Signature, error)
+	SignRoundVoteAck(vt *RoundVoteAck) (common.Signature, error)
+	SignBlockVote(vt *BlockVote) (common.Signature, error)
+	SignBlockGenRequest(req *BlockGenRequest) (common.Signature, error)
+	SignHandshake(req []byte) (common.Signature, error)
+}
+
+func signHeader(k key.Key, bh *types.Header) (common.Signature, error) {
+	if hs, is := k.(HeaderSigner); is {
+		return hs.SignHeader(bh)
+	}
+	return k.Sign(encoding.Hash(bh))
+}
+
+func signBlockSign(k key.Key, bh *types.Header, GeneratorSignature common.Signature) (common.Signature, error) {
+	if hs, is := k.(HeaderSigner); is {
+		return hs.SignBlockSign(bh, GeneratorSignature)
+	}
+	s := &types.BlockSign{
+		HeaderHash:         encoding.Hash(bh),
+		GeneratorSignature: GeneratorSignature,
+	}
+	return k.Sign(encoding.Hash(s))
+}
+
+func signRoundVote(k key.Key, vt *RoundVote) (common.Signature, error) {
+	if ms, is := k.(MessageSigner); is {
+		return ms.SignRoundVote(vt)
+	}
+	return k.Sign(encoding.Hash(vt))
+}
+
+func signRoundVoteAck(k key.Key, vt *RoundVoteAck) (common.Signature, error) {
+	if ms, is := k.(MessageSigner); is {
+		return ms.SignRoundVoteAck(vt)
+	}
+	return k.Sign(encoding.Hash(vt))
+}
+
+func signBlockVote(k key.Key, vt *BlockVote) (common.Signature, error) {
+	if ms, is := k.(MessageSigner); is {
+		return ms.SignBlockVote(vt)
+	}
+	return k.Sign(encoding.Hash(vt))
+}
+
+func signBlockGenRequest(k key.Key, req *BlockGenRequest) (common.Signature, error) {
+	if ms, is := k.(MessageSigner); is {
+		return ms.SignBlockGenRequest(req)
+	}
+	return k.Sign(encoding.Hash(req))
+}
+
+func signHandshake(k key.Key, req []byte) (common.Signature, error) {
+	if ms, is := k.(MessageSigner); is {
+		return ms.SignHandshake(req)
+	}
+	return k.Sign(hash.Hash(req))
+}
+
+// VRFProver is the key that proves VRF outputs, generators of VRFVersion blocks should use it
+// The remote key of the signer daemon proves them by the key of the daemon
+type VRFProver interface {
+	ProveVRF(alpha []byte) (hash.Hash256, []byte, error)
+}
+
+func proveVRF(k key.Key, alpha []byte) (hash.Hash256, []byte, error) {
+	vp, is := k.(VRFProver)
+	if !is {
+		return hash.Hash256{}, nil, ErrNotSupportedVRF
+	}
+	return vp.ProveVRF(alpha)
+}
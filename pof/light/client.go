@@ -0,0 +1,216 @@
+// Package light implements the light client that verifies headers without the chain data
+// Headers are verified by the generator signature and the majority of observer signatures from the trusted checkpoint,
+// and generators are checked against the rank table only when the client follows it by account proofs of blocks
+package light
+
+import (
+	"sync"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// Client verifies headers from the trusted checkpoint
+type Client struct {
+	sync.Mutex
+	chainID           uint8
+	name              string
+	observerKeys      []common.PublicHash
+	observerKeyMap    map[common.PublicHash]bool
+	observerBLSKeyMap map[common.PublicHash]bls.PublicKey
+	height            uint32
+	lastHash          hash.Hash256
+	lastTimestamp     uint64
+	lastVersion       uint16
+	tracker           *pof.RankTracker
+}
+
+// NewClient returns a Client that starts from the trusted height, hash and timestamp of the chain
+func NewClient(ChainID uint8, Name string, ObserverKeys []common.PublicHash, Height uint32, LastHash hash.Hash256, LastTimestamp uint64) *Client {
+	ObserverKeyMap := map[common.PublicHash]bool{}
+	for _, pubhash := range ObserverKeys {
+		ObserverKeyMap[pubhash] = true
+	}
+	c := &Client{
+		chainID:           ChainID,
+		name:              Name,
+		observerKeys:      pof.ObserverOrder(ObserverKeys),
+		observerKeyMap:    ObserverKeyMap,
+		observerBLSKeyMap: map[common.PublicHash]bls.PublicKey{},
+		height:            Height,
+		lastHash:          LastHash,
+		lastTimestamp:     LastTimestamp,
+	}
+	return c
+}
+
+// SetObserverBLSKeys sets BLS public keys of observers to verify headers of the BLS version
+// Each key should have the proof of possession to prevent the rogue key attack on the aggregated signature
+func (c *Client) SetObserverBLSKeys(KeyMap map[common.PublicHash]bls.PublicKey, ProofMap map[common.PublicHash]bls.Signature) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(KeyMap) != len(c.observerKeyMap) {
+		return ErrInvalidObserverKey
+	}
+	blsKeyMap := map[common.PublicHash]bls.PublicKey{}
+	for pubhash, pubkey := range KeyMap {
+		if !c.observerKeyMap[pubhash] {
+			return ErrInvalidObserverKey
+		}
+		if proof, has := ProofMap[pubhash]; !has || !bls.VerifyPossession(pubkey, proof) {
+			return ErrInvalidObserverBLSProof
+		}
+		blsKeyMap[pubhash] = pubkey.Clone()
+	}
+	c.observerBLSKeyMap = blsKeyMap
+	return nil
+}
+
+// SetRankTracker enables checking generators by the rank tracker
+// The tracker should be the state after the block of the trusted checkpoint,
+// and account proofs are required to verify next headers after it is set
+func (c *Client) SetRankTracker(tr *pof.RankTracker) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.tracker = tr
+}
+
+// Height returns the height of the last verified header
+func (c *Client) Height() uint32 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.height
+}
+
+// LastHash returns the hash of the last verified header
+func (c *Client) LastHash() hash.Hash256 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lastHash
+}
+
+// Candidates returns candidates of the tracked rank table (nil when generators are not checked)
+func (c *Client) Candidates() []*pof.Rank {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.tracker == nil {
+		return nil
+	}
+	return c.tracker.Candidates()
+}
+
+// VerifyHeader verifies the next header and updates the last status
+// The account proof is only used when the rank tracker is set
+func (c *Client) VerifyHeader(bh *types.Header, sigs []common.Signature, proof *types.AccountProof) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if bh.ChainID != c.chainID {
+		return ErrInvalidChainID
+	}
+	if bh.Version < c.lastVersion {
+		return ErrInvalidVersion
+	}
+	if bh.PrevHash != c.lastHash {
+		return ErrInvalidPrevHash
+	}
+	if bh.Height != c.height+1 {
+		return ErrInvalidHeight
+	}
+	if bh.Timestamp <= c.lastTimestamp {
+		return ErrInvalidTimestamp
+	}
+	if len(sigs) < 2 {
+		return ErrInvalidSignatureCount
+	}
+
+	var tracker *pof.RankTracker
+	if c.tracker != nil {
+		if proof == nil {
+			return ErrRequiredAccountProof
+		}
+		if err := c.tracker.ValidateGenerator(bh, sigs[0]); err != nil {
+			return err
+		}
+	}
+	if err := c.validateObserverSignatures(bh, sigs); err != nil {
+		return err
+	}
+	if c.tracker != nil {
+		if proof.ContextHash(c.name, bh) != bh.ContextHash {
+			return ErrInvalidContextHash
+		}
+		AccountMap, err := proof.AccountMap()
+		if err != nil {
+			return err
+		}
+		tracker = c.tracker.Clone()
+		if err := tracker.Apply(bh, AccountMap, proof.DeletedAccounts); err != nil {
+			return err
+		}
+	}
+
+	c.height = bh.Height
+	c.lastHash = encoding.Hash(bh)
+	c.lastTimestamp = bh.Timestamp
+	c.lastVersion = bh.Version
+	if tracker != nil {
+		c.tracker = tracker
+	}
+	return nil
+}
+
+// VerifyHeaders verifies headers in order, proofs can be nil when the rank tracker is not set
+func (c *Client) VerifyHeaders(bhs []*types.Header, sigsList [][]common.Signature, proofs []*types.AccountProof) error {
+	if len(sigsList) != len(bhs) {
+		return ErrInvalidHeaderCount
+	}
+	if proofs != nil && len(proofs) != len(bhs) {
+		return ErrInvalidHeaderCount
+	}
+	for i, bh := range bhs {
+		var proof *types.AccountProof
+		if proofs != nil {
+			proof = proofs[i]
+		}
+		if err := c.VerifyHeader(bh, sigsList[i], proof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) validateObserverSignatures(bh *types.Header, sigs []common.Signature) error {
+	bs := types.BlockSign{
+		HeaderHash:         encoding.Hash(bh),
+		GeneratorSignature: sigs[0],
+	}
+	Quorum := c.observerQuorum()
+	if pof.IsBLSVersion(bh.Version) {
+		return pof.ValidateBLSSignatures(&bs, sigs[1:], c.observerKeys, c.observerBLSKeyMap, Quorum)
+	}
+	if len(sigs) != Quorum+1 {
+		return ErrInvalidSignatureCount
+	}
+	if err := common.ValidateSignaturesQuorum(encoding.Hash(bs), sigs[1:], c.observerKeyMap, Quorum); err != nil {
+		return err
+	}
+	return nil
+}
+
+// observerQuorum returns the quorum of the consensus policy that is tracked by the rank tracker or the majority of observers
+func (c *Client) observerQuorum() int {
+	if c.tracker != nil {
+		return c.tracker.ObserverQuorum()
+	}
+	return len(c.observerKeyMap)/2 + 1
+}
@@ -0,0 +1,21 @@
+package light
+
+import "errors"
+
+// light client errors
+var (
+	ErrInvalidChainID          = errors.New("invalid chain id")
+	ErrInvalidVersion          = errors.New("invalid version")
+	ErrInvalidPrevHash         = errors.New("invalid prev hash")
+	ErrInvalidHeight           = errors.New("invalid height")
+	ErrInvalidTimestamp        = errors.New("invalid timestamp")
+	ErrInvalidSignatureCount   = errors.New("invalid signature count")
+	ErrInvalidContextHash      = errors.New("invalid context hash")
+	ErrInvalidObserverKey      = errors.New("invalid observer key")
+	ErrInvalidObserverBLSProof = errors.New("invalid observer bls proof")
+	ErrRequiredAccountProof    = errors.New("required account proof")
+	ErrInvalidHeaderCount      = errors.New("invalid header count")
+	ErrNotExistAccountProof    = errors.New("not exist account proof")
+	ErrNotExistRankState       = errors.New("not exist rank state")
+	ErrInvalidHeaderItemFormat = errors.New("invalid header item format")
+)
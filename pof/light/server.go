@@ -0,0 +1,247 @@
+package light
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// MaxHeaderRange is the max count of headers in one request
+const MaxHeaderRange = 100
+
+// HeaderItem is the header with signatures of the block
+type HeaderItem struct {
+	Header     *types.Header
+	Signatures []common.Signature
+}
+
+// MarshalJSON is a marshaler function
+func (item *HeaderItem) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(item.Header.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"header":`)
+	if data, err := encoding.Marshal(item.Header); err != nil {
+		return nil, err
+	} else if bs, err := json.Marshal(hex.EncodeToString(data)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signatures":`)
+	buffer.WriteString(`[`)
+	for i, sig := range item.Signatures {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := sig.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (item *HeaderItem) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Header     string             `json:"header"`
+		Signatures []common.Signature `json:"signatures"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	data, err := hex.DecodeString(v.Header)
+	if err != nil {
+		return err
+	}
+	bh := &types.Header{}
+	if err := encoding.Unmarshal(data, bh); err != nil {
+		return err
+	}
+	if len(v.Signatures) == 0 {
+		return ErrInvalidHeaderItemFormat
+	}
+	item.Header = bh
+	item.Signatures = v.Signatures
+	return nil
+}
+
+// Server serves headers, signatures and account proofs of recent blocks to light clients
+// Account proofs are kept in memory only for blocks connected after the start
+type Server struct {
+	sync.Mutex
+	types.ServiceBase
+	cs              *pof.Consensus
+	cn              types.Provider
+	proofLimit      uint32
+	proofMap        map[uint32]*types.AccountProof
+	rankStateHeight uint32
+	rankState       []byte
+}
+
+// NewServer returns a Server that keeps account proofs of the last ProofLimit blocks
+func NewServer(cs *pof.Consensus, ProofLimit uint32) *Server {
+	s := &Server{
+		cs:         cs,
+		proofLimit: ProofLimit,
+		proofMap:   map[uint32]*types.AccountProof{},
+	}
+	return s
+}
+
+// Name returns the name of the service
+func (s *Server) Name() string {
+	return "fleta.light"
+}
+
+// Init called when initialize service
+func (s *Server) Init(pm types.ProcessManager, cn types.Provider) error {
+	s.cn = cn
+
+	if vs, err := pm.ServiceByName("fleta.apiserver"); err != nil {
+		//ignore when not loaded
+	} else if v, is := vs.(*apiserver.APIServer); !is {
+		//ignore when not loaded
+	} else {
+		js, err := v.JRPC("light")
+		if err != nil {
+			return err
+		}
+		js.Set("headers", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 2 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			From, err := arg.Uint32(0)
+			if err != nil {
+				return nil, err
+			}
+			To, err := arg.Uint32(1)
+			if err != nil {
+				return nil, err
+			}
+			return s.Headers(From, To)
+		})
+		js.Set("accountProof", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			Height, err := arg.Uint32(0)
+			if err != nil {
+				return nil, err
+			}
+			pf, err := s.AccountProof(Height)
+			if err != nil {
+				return nil, err
+			}
+			data, err := encoding.Marshal(pf)
+			if err != nil {
+				return nil, err
+			}
+			return hex.EncodeToString(data), nil
+		})
+		js.Set("rankState", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			Height, data, err := s.RankState()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"height": Height,
+				"data":   hex.EncodeToString(data),
+			}, nil
+		})
+	}
+	return nil
+}
+
+// OnLoadChain called when the chain loaded
+func (s *Server) OnLoadChain(loader types.Loader) error {
+	s.Lock()
+	defer s.Unlock()
+
+	data, err := s.cs.SaveData()
+	if err != nil {
+		return err
+	}
+	s.rankStateHeight = s.cn.Height()
+	s.rankState = data
+	return nil
+}
+
+// OnBlockConnected called when a block is connected to the chain
+func (s *Server) OnBlockConnected(b *types.Block, events []types.Event, loader types.Loader) {
+	s.Lock()
+	defer s.Unlock()
+
+	Height := b.Header.Height
+	if ctx, is := loader.(*types.Context); is {
+		s.proofMap[Height] = ctx.AccountProof()
+		if Height > s.proofLimit {
+			delete(s.proofMap, Height-s.proofLimit)
+		}
+	}
+	if data, err := s.cs.SaveData(); err == nil {
+		s.rankStateHeight = Height
+		s.rankState = data
+	}
+}
+
+// Headers returns headers with signatures from the height to the height
+func (s *Server) Headers(From uint32, To uint32) ([]*HeaderItem, error) {
+	if From == 0 || From > To || To-From >= MaxHeaderRange || To > s.cn.Height() {
+		return nil, apiserver.ErrInvalidArgument
+	}
+	list := make([]*HeaderItem, 0, To-From+1)
+	for h := From; h <= To; h++ {
+		b, err := s.cn.Block(h)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &HeaderItem{
+			Header:     &b.Header,
+			Signatures: b.Signatures,
+		})
+	}
+	return list, nil
+}
+
+// AccountProof returns the account proof of the block
+func (s *Server) AccountProof(Height uint32) (*types.AccountProof, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	pf, has := s.proofMap[Height]
+	if !has {
+		return nil, ErrNotExistAccountProof
+	}
+	return pf, nil
+}
+
+// RankState returns the saved data of the consensus at the last connected block
+// Light clients should get it from the trusted node because it is not included in the context hash
+func (s *Server) RankState() (uint32, []byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.rankState == nil {
+		return 0, nil, ErrNotExistRankState
+	}
+	return s.rankStateHeight, s.rankState, nil
+}
@@ -2,6 +2,7 @@ package pof
 
 import (
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
 	"github.com/fletaio/fleta_testnet/common/hash"
 	"github.com/fletaio/fleta_testnet/core/types"
 )
@@ -67,11 +68,12 @@ type BlockGenMessage struct {
 
 // BlockVote is message for a block vote
 type BlockVote struct {
-	TargetHeight       uint32
-	Header             *types.Header
-	GeneratorSignature common.Signature
-	ObserverSignature  common.Signature
-	IsReply            bool
+	TargetHeight         uint32
+	Header               *types.Header
+	GeneratorSignature   common.Signature
+	ObserverSignature    common.Signature
+	ObserverBLSSignature bls.Signature
+	IsReply              bool
 }
 
 // BlockVoteMessage is a message for a round vote
@@ -0,0 +1,158 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// ObserverStatus is the runtime status of the observer that is reported by the admin rpc
+// Formulator and TimeoutCount are set after the round vote acks are agreed
+type ObserverStatus struct {
+	Height        uint32
+	RoundState    int
+	TargetHeight  uint32
+	VoteFailCount int
+	Formulator    common.Address
+	TimeoutCount  uint32
+	IgnoreMap     map[common.Address]int64 // ignored until the unix nano time
+}
+
+// MarshalJSON is a marshaler function
+func (st *ObserverStatus) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(st.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"round_state":`)
+	if bs, err := json.Marshal(st.RoundState); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"target_height":`)
+	if bs, err := json.Marshal(st.TargetHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vote_fail_count":`)
+	if bs, err := json.Marshal(st.VoteFailCount); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"formulator":`)
+	if bs, err := st.Formulator.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timeout_count":`)
+	if bs, err := json.Marshal(st.TimeoutCount); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"ignore_map":`)
+	IgnoreMap := map[string]int64{}
+	for addr, until := range st.IgnoreMap {
+		IgnoreMap[addr.String()] = until
+	}
+	if bs, err := json.Marshal(IgnoreMap); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// Status returns the runtime status of the observer
+func (ob *ObserverNode) Status() *ObserverStatus {
+	ob.Lock()
+	defer ob.Unlock()
+
+	st := &ObserverStatus{
+		Height:        ob.cs.cn.Provider().Height(),
+		RoundState:    ob.round.RoundState,
+		TargetHeight:  ob.round.TargetHeight,
+		VoteFailCount: ob.round.VoteFailCount,
+		IgnoreMap:     map[common.Address]int64{},
+	}
+	if ob.round.MinRoundVoteAck != nil {
+		st.Formulator = ob.round.MinRoundVoteAck.Formulator
+		st.TimeoutCount = ob.round.MinRoundVoteAck.TimeoutCount
+	}
+	now := ob.clock.Now().UnixNano()
+	for addr, until := range ob.ignoreMap {
+		if now < until {
+			st.IgnoreMap[addr] = until
+		}
+	}
+	return st
+}
+
+// ObserverPeers returns connection statuses of other observers
+func (ob *ObserverNode) ObserverPeers() []*PeerStatus {
+	list := []*PeerStatus{}
+	for _, p := range ob.ms.Peers() {
+		var pubhash common.PublicHash
+		copy(pubhash[:], []byte(p.ID()))
+		list = append(list, &PeerStatus{
+			ID:            pubhash.String(),
+			Name:          p.Name(),
+			GuessHeight:   p.GuessHeight(),
+			ConnectedTime: p.ConnectedTime(),
+		})
+	}
+	sortPeerStatuses(list)
+	return list
+}
+
+// FormulatorPeers returns connection statuses of formulators with their guessed heights
+func (ob *ObserverNode) FormulatorPeers() []*PeerStatus {
+	list := []*PeerStatus{}
+	for _, p := range ob.fs.Peers() {
+		var addr common.Address
+		copy(addr[:], []byte(p.ID()))
+		list = append(list, &PeerStatus{
+			ID:            addr.String(),
+			Name:          p.Name(),
+			GuessHeight:   p.GuessHeight(),
+			ConnectedTime: p.ConnectedTime(),
+		})
+	}
+	sortPeerStatuses(list)
+	return list
+}
+
+// InitAdmin registers the admin rpc of the observer to the api server that is bound to the admin port
+func (ob *ObserverNode) InitAdmin(as *apiserver.APIServer) error {
+	js, err := as.JRPC("admin")
+	if err != nil {
+		return err
+	}
+	js.Set("status", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return ob.Status(), nil
+	})
+	js.Set("observers", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return ob.ObserverPeers(), nil
+	})
+	js.Set("formulators", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return ob.FormulatorPeers(), nil
+	})
+	return nil
+}
@@ -0,0 +1,225 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// evidence types
+const (
+	RoundVoteAckEvidenceType = uint8(1)
+	BlockVoteEvidenceType    = uint8(2)
+	BlockSignEvidenceType    = uint8(3)
+)
+
+// SignedVote is the encoded message and the signature of it signed by the observer
+type SignedVote struct {
+	Type      uint8
+	Data      []byte
+	Signature common.Signature
+}
+
+// NewRoundVoteAckSignedVote returns a SignedVote of the round vote ack message
+func NewRoundVoteAckSignedVote(msg *RoundVoteAckMessage) (*SignedVote, error) {
+	data, err := encoding.Marshal(msg.RoundVoteAck)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedVote{
+		Type:      RoundVoteAckEvidenceType,
+		Data:      data,
+		Signature: msg.Signature,
+	}, nil
+}
+
+// NewBlockVoteSignedVote returns a SignedVote of the block vote message
+func NewBlockVoteSignedVote(msg *BlockVoteMessage) (*SignedVote, error) {
+	data, err := encoding.Marshal(msg.BlockVote)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedVote{
+		Type:      BlockVoteEvidenceType,
+		Data:      data,
+		Signature: msg.Signature,
+	}, nil
+}
+
+// NewBlockSignSignedVote returns a SignedVote of the block sign that is included in the block vote
+func NewBlockSignSignedVote(vt *BlockVote) (*SignedVote, error) {
+	data, err := encoding.Marshal(vt)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedVote{
+		Type:      BlockSignEvidenceType,
+		Data:      data,
+		Signature: vt.ObserverSignature,
+	}, nil
+}
+
+// Info decodes the signed vote and returns the round, the digest of the voted content and the signed hash
+func (sv *SignedVote) Info() (uint32, uint32, hash.Hash256, hash.Hash256, error) {
+	switch sv.Type {
+	case RoundVoteAckEvidenceType:
+		vt := &RoundVoteAck{}
+		if err := encoding.Unmarshal(sv.Data, &vt); err != nil {
+			return 0, 0, hash.Hash256{}, hash.Hash256{}, err
+		}
+		Digest := encoding.Hash(&RoundVoteAck{
+			ChainID:              vt.ChainID,
+			LastHash:             vt.LastHash,
+			TargetHeight:         vt.TargetHeight,
+			TimeoutCount:         vt.TimeoutCount,
+			Formulator:           vt.Formulator,
+			FormulatorPublicHash: vt.FormulatorPublicHash,
+		})
+		return vt.TargetHeight, vt.TimeoutCount, Digest, hash.Hash(sv.Data), nil
+	case BlockVoteEvidenceType, BlockSignEvidenceType:
+		vt := &BlockVote{}
+		if err := encoding.Unmarshal(sv.Data, &vt); err != nil {
+			return 0, 0, hash.Hash256{}, hash.Hash256{}, err
+		}
+		if vt.Header == nil {
+			return 0, 0, hash.Hash256{}, hash.Hash256{}, ErrInvalidEvidence
+		}
+		TimeoutCount, err := types.DecodeTimeoutCount(vt.Header.ConsensusData)
+		if err != nil {
+			return 0, 0, hash.Hash256{}, hash.Hash256{}, err
+		}
+		Digest := encoding.Hash(vt.Header)
+		if sv.Type == BlockSignEvidenceType {
+			s := &types.BlockSign{
+				HeaderHash:         Digest,
+				GeneratorSignature: vt.GeneratorSignature,
+			}
+			return vt.Header.Height, TimeoutCount, Digest, encoding.Hash(s), nil
+		}
+		return vt.Header.Height, TimeoutCount, Digest, hash.Hash(sv.Data), nil
+	default:
+		return 0, 0, hash.Hash256{}, hash.Hash256{}, ErrInvalidEvidence
+	}
+}
+
+// MarshalJSON is a marshaler function
+func (sv *SignedVote) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(sv.Type); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"data":`)
+	if bs, err := json.Marshal(hex.EncodeToString(sv.Data)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signature":`)
+	if bs, err := sv.Signature.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// ObserverEvidence is the proof that the observer signed two conflicting votes in the same round
+type ObserverEvidence struct {
+	PublicHash   common.PublicHash
+	Height       uint32
+	TimeoutCount uint32
+	VoteA        *SignedVote
+	VoteB        *SignedVote
+}
+
+// Validate checks that two votes are signed by the observer and conflict with each other
+func (ev *ObserverEvidence) Validate() error {
+	if ev.VoteA == nil || ev.VoteB == nil {
+		return ErrInvalidEvidence
+	}
+	if ev.VoteA.Type != ev.VoteB.Type {
+		return ErrInvalidEvidence
+	}
+	HeightA, TimeoutCountA, DigestA, SignedHashA, err := ev.VoteA.Info()
+	if err != nil {
+		return err
+	}
+	HeightB, TimeoutCountB, DigestB, SignedHashB, err := ev.VoteB.Info()
+	if err != nil {
+		return err
+	}
+	if HeightA != ev.Height || HeightB != ev.Height {
+		return ErrInvalidEvidence
+	}
+	if TimeoutCountA != ev.TimeoutCount || TimeoutCountB != ev.TimeoutCount {
+		return ErrInvalidEvidence
+	}
+	if DigestA == DigestB {
+		return ErrInvalidEvidence
+	}
+	if pubkey, err := common.RecoverPubkey(SignedHashA, ev.VoteA.Signature); err != nil {
+		return err
+	} else if common.NewPublicHash(pubkey) != ev.PublicHash {
+		return ErrInvalidEvidence
+	}
+	if pubkey, err := common.RecoverPubkey(SignedHashB, ev.VoteB.Signature); err != nil {
+		return err
+	} else if common.NewPublicHash(pubkey) != ev.PublicHash {
+		return ErrInvalidEvidence
+	}
+	return nil
+}
+
+// MarshalJSON is a marshaler function
+func (ev *ObserverEvidence) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"public_hash":`)
+	if bs, err := ev.PublicHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timeout_count":`)
+	if bs, err := json.Marshal(ev.TimeoutCount); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vote_a":`)
+	if bs, err := ev.VoteA.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vote_b":`)
+	if bs, err := ev.VoteB.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
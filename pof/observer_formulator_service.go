@@ -23,6 +23,8 @@ type FormulatorService struct {
 	key     key.Key
 	ob      *ObserverNode
 	peerMap map[string]peer.Peer
+	lstn    net.Listener
+	isClose bool
 }
 
 // NewFormulatorService returns a FormulatorService
@@ -42,6 +44,25 @@ func (ms *FormulatorService) Run(BindAddress string) {
 	}
 }
 
+// Close terminates the formulator service and disconnects all peers
+func (ms *FormulatorService) Close() {
+	ms.Lock()
+	ms.isClose = true
+	lstn := ms.lstn
+	peers := []peer.Peer{}
+	for _, p := range ms.peerMap {
+		peers = append(peers, p)
+	}
+	ms.Unlock()
+
+	if lstn != nil {
+		lstn.Close()
+	}
+	for _, p := range peers {
+		p.Close()
+	}
+}
+
 // PeerCount returns a number of the peer
 func (ms *FormulatorService) PeerCount() int {
 	ms.Lock()
@@ -50,6 +71,18 @@ func (ms *FormulatorService) PeerCount() int {
 	return len(ms.peerMap)
 }
 
+// Peers returns peers of the formulator service
+func (ms *FormulatorService) Peers() []peer.Peer {
+	ms.Lock()
+	defer ms.Unlock()
+
+	peers := []peer.Peer{}
+	for _, p := range ms.peerMap {
+		peers = append(peers, p)
+	}
+	return peers
+}
+
 // RemovePeer removes peers from the mesh
 func (ms *FormulatorService) RemovePeer(ID string) {
 	ms.Lock()
@@ -133,14 +166,28 @@ func (ms *FormulatorService) server(BindAddress string) error {
 		rlog.Println("FormulatorService", common.NewPublicHash(ms.key.PublicKey()), "Start to Listen", BindAddress)
 	}
 
-	lstn, err := net.Listen("tcp", BindAddress)
+	lstn, err := ms.ob.transport.Listen(BindAddress)
 	if err != nil {
 		return err
 	}
+	ms.Lock()
+	if ms.isClose {
+		ms.Unlock()
+		lstn.Close()
+		return nil
+	}
+	ms.lstn = lstn
+	ms.Unlock()
 	rlog.Println(common.NewPublicHash(ms.key.PublicKey()), "Start to Listen", BindAddress)
 	for {
 		conn, err := lstn.Accept()
 		if err != nil {
+			ms.Lock()
+			isClose := ms.isClose
+			ms.Unlock()
+			if isClose {
+				return nil
+			}
 			return err
 		}
 		go func() {
@@ -162,7 +209,7 @@ func (ms *FormulatorService) server(BindAddress string) error {
 			}
 
 			ID := string(Formulator[:])
-			p := p2p.NewTCPPeer(conn, ID, Formulator.String(), time.Now().UnixNano())
+			p := p2p.NewTCPPeer(conn, ID, Formulator.String(), ms.ob.clock.Now().UnixNano())
 			ms.RemovePeer(ID)
 			ms.Lock()
 			ms.peerMap[ID] = p
@@ -204,7 +251,7 @@ func (ms *FormulatorService) server(BindAddress string) error {
 			}
 
 			ID := string(Formulator[:])
-			p := p2p.NewWebsocketPeer(conn, ID, Formulator.String(), time.Now().UnixNano())
+			p := p2p.NewWebsocketPeer(conn, ID, Formulator.String(), ms.ob.clock.Now().UnixNano())
 			ms.RemovePeer(ID)
 			ms.Lock()
 			ms.peerMap[ID] = p
@@ -258,7 +305,7 @@ func (ms *FormulatorService) recvHandshake(conn net.Conn) (common.Address, error
 	timestamp := binary.LittleEndian.Uint64(req[32:])
 	var Formulator common.Address
 	copy(Formulator[:], req[40:])
-	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	diff := time.Duration(uint64(ms.ob.clock.Now().UnixNano()) - timestamp)
 	if diff < 0 {
 		diff = -diff
 	}
@@ -266,7 +313,7 @@ func (ms *FormulatorService) recvHandshake(conn net.Conn) (common.Address, error
 		return common.Address{}, p2p.ErrInvalidHandshake
 	}
 	//rlog.Println("sendHandshakeAck")
-	if sig, err := ms.key.Sign(hash.Hash(req)); err != nil {
+	if sig, err := signHandshake(ms.key, req); err != nil {
 		return common.Address{}, err
 	} else if _, err := conn.Write(sig[:]); err != nil {
 		return common.Address{}, err
@@ -281,7 +328,7 @@ func (ms *FormulatorService) sendHandshake(conn net.Conn) (common.PublicHash, er
 		return common.PublicHash{}, err
 	}
 	req[0] = ms.ob.cs.cn.Provider().ChainID()
-	binary.LittleEndian.PutUint64(req[32:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(req[32:], uint64(ms.ob.clock.Now().UnixNano()))
 	if _, err := conn.Write(req); err != nil {
 		return common.PublicHash{}, err
 	}
@@ -315,7 +362,7 @@ func (ms *FormulatorService) recvHandshake(conn *websocket.Conn) (common.Address
 	timestamp := binary.LittleEndian.Uint64(req[32:])
 	var Formulator common.Address
 	copy(Formulator[:], req[40:])
-	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	diff := time.Duration(uint64(ms.ob.clock.Now().UnixNano()) - timestamp)
 	if diff < 0 {
 		diff = -diff
 	}
@@ -338,7 +385,7 @@ func (ms *FormulatorService) sendHandshake(conn *websocket.Conn) (common.PublicH
 		return common.PublicHash{}, err
 	}
 	req[0] = ms.ob.cs.cn.Provider().ChainID()
-	binary.LittleEndian.PutUint64(req[32:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(req[32:], uint64(ms.ob.clock.Now().UnixNano()))
 	if err := conn.WriteMessage(websocket.BinaryMessage, req); err != nil {
 		return common.PublicHash{}, err
 	}
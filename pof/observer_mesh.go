@@ -24,6 +24,8 @@ type ObserverNodeMesh struct {
 	netAddressMap map[common.PublicHash]string
 	clientPeerMap map[string]peer.Peer
 	serverPeerMap map[string]peer.Peer
+	lstn          net.Listener
+	isClose       bool
 }
 
 func NewObserverNodeMesh(key key.Key, NetAddressMap map[common.PublicHash]string, ob *ObserverNode) *ObserverNodeMesh {
@@ -43,19 +45,23 @@ func (ms *ObserverNodeMesh) Run(BindAddress string) {
 	for PubHash, v := range ms.netAddressMap {
 		if PubHash != myPublicHash {
 			go func(pubhash common.PublicHash, NetAddr string) {
-				time.Sleep(1 * time.Second)
+				ms.ob.clock.Sleep(1 * time.Second)
 				for {
 					ID := string(pubhash[:])
 					ms.Lock()
+					isClose := ms.isClose
 					_, hasC := ms.clientPeerMap[ID]
 					_, hasS := ms.serverPeerMap[ID]
 					ms.Unlock()
+					if isClose {
+						return
+					}
 					if !hasC && !hasS {
 						if err := ms.client(NetAddr, pubhash); err != nil {
 							rlog.Println("[client]", err, NetAddr)
 						}
 					}
-					time.Sleep(1 * time.Second)
+					ms.ob.clock.Sleep(1 * time.Second)
 				}
 			}(PubHash, v)
 		}
@@ -65,6 +71,28 @@ func (ms *ObserverNodeMesh) Run(BindAddress string) {
 	}
 }
 
+// Close terminates the observer mesh and disconnects all peers
+func (ms *ObserverNodeMesh) Close() {
+	ms.Lock()
+	ms.isClose = true
+	lstn := ms.lstn
+	peers := []peer.Peer{}
+	for _, p := range ms.clientPeerMap {
+		peers = append(peers, p)
+	}
+	for _, p := range ms.serverPeerMap {
+		peers = append(peers, p)
+	}
+	ms.Unlock()
+
+	if lstn != nil {
+		lstn.Close()
+	}
+	for _, p := range peers {
+		p.Close()
+	}
+}
+
 // Peers returns peers of the observer mesh
 func (ms *ObserverNodeMesh) Peers() []peer.Peer {
 	peerMap := map[string]peer.Peer{}
@@ -231,13 +259,13 @@ func (ms *ObserverNodeMesh) BroadcastMessage(m interface{}) error {
 }
 
 func (ms *ObserverNodeMesh) client(Address string, TargetPubHash common.PublicHash) error {
-	conn, err := net.DialTimeout("tcp", Address, 10*time.Second)
+	conn, err := ms.ob.transport.DialTimeout(Address, 10*time.Second)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	start := time.Now()
+	start := ms.ob.clock.Now()
 	if err := ms.recvHandshake(conn); err != nil {
 		rlog.Println("[recvHandshake]", err)
 		return err
@@ -269,20 +297,34 @@ func (ms *ObserverNodeMesh) client(Address string, TargetPubHash common.PublicHa
 }
 
 func (ms *ObserverNodeMesh) server(BindAddress string) error {
-	lstn, err := net.Listen("tcp", BindAddress)
+	lstn, err := ms.ob.transport.Listen(BindAddress)
 	if err != nil {
 		return err
 	}
+	ms.Lock()
+	if ms.isClose {
+		ms.Unlock()
+		lstn.Close()
+		return nil
+	}
+	ms.lstn = lstn
+	ms.Unlock()
 	rlog.Println(common.NewPublicHash(ms.key.PublicKey()), "Start to Listen", BindAddress)
 	for {
 		conn, err := lstn.Accept()
 		if err != nil {
+			ms.Lock()
+			isClose := ms.isClose
+			ms.Unlock()
+			if isClose {
+				return nil
+			}
 			return err
 		}
 		go func() {
 			defer conn.Close()
 
-			start := time.Now()
+			start := ms.ob.clock.Now()
 			pubhash, err := ms.sendHandshake(conn)
 			if err != nil {
 				rlog.Println("[sendHandshake]", err)
@@ -339,7 +381,7 @@ func (ms *ObserverNodeMesh) recvHandshake(conn net.Conn) error {
 		return chain.ErrInvalidChainID
 	}
 	timestamp := binary.LittleEndian.Uint64(req[32:])
-	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	diff := time.Duration(uint64(ms.ob.clock.Now().UnixNano()) - timestamp)
 	if diff < 0 {
 		diff = -diff
 	}
@@ -347,7 +389,7 @@ func (ms *ObserverNodeMesh) recvHandshake(conn net.Conn) error {
 		return p2p.ErrInvalidHandshake
 	}
 	//rlog.Println("sendHandshakeAck")
-	if sig, err := ms.key.Sign(hash.Hash(req)); err != nil {
+	if sig, err := signHandshake(ms.key, req); err != nil {
 		return err
 	} else if _, err := conn.Write(sig[:]); err != nil {
 		return err
@@ -362,7 +404,7 @@ func (ms *ObserverNodeMesh) sendHandshake(conn net.Conn) (common.PublicHash, err
 		return common.PublicHash{}, err
 	}
 	req[0] = ms.ob.cs.cn.Provider().ChainID()
-	binary.LittleEndian.PutUint64(req[32:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(req[32:], uint64(ms.ob.clock.Now().UnixNano()))
 	if _, err := conn.Write(req); err != nil {
 		return common.PublicHash{}, err
 	}
@@ -9,10 +9,12 @@ import (
 	"github.com/bluele/gcache"
 
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
 	"github.com/fletaio/fleta_testnet/common/debug"
 	"github.com/fletaio/fleta_testnet/common/key"
 	"github.com/fletaio/fleta_testnet/common/queue"
 	"github.com/fletaio/fleta_testnet/common/rlog"
+	"github.com/fletaio/fleta_testnet/core/backend"
 	"github.com/fletaio/fleta_testnet/core/chain"
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/encoding"
@@ -31,6 +33,7 @@ type messageItem struct {
 type ObserverNode struct {
 	sync.Mutex
 	key              key.Key
+	blsKey           *bls.PrivateKey
 	ms               *ObserverNodeMesh
 	fs               *FormulatorService
 	cs               *Consensus
@@ -48,6 +51,10 @@ type ObserverNode struct {
 	closeLock        sync.RWMutex
 	isClose          bool
 	cache            gcache.Cache
+	vr               *VoteRecorder
+	rs               *ObserverRoundStore
+	clock            Clock
+	transport        p2p.Transport
 
 	prevRoundEndTime int64 // FOR DEBUG
 }
@@ -70,7 +77,11 @@ func NewObserverNode(key key.Key, NetAddressMap map[common.PublicHash]string, cs
 			queue.NewQueue(),
 			queue.NewQueue(),
 		},
-		cache: gcache.New(500).LRU().Build(),
+		cache:     gcache.New(500).LRU().Build(),
+		vr:        NewVoteRecorder(nil),
+		rs:        newObserverRoundStore(nil),
+		clock:     &systemClock{},
+		transport: p2p.NewTCPTransport(),
 	}
 	ob.ms = NewObserverNodeMesh(key, NetAddressMap, ob)
 	ob.fs = NewFormulatorService(ob)
@@ -96,6 +107,12 @@ func (ob *ObserverNode) Init() error {
 	fc.Register(types.DefineHashedType("p2p.BlockMessage"), &p2p.BlockMessage{})
 	fc.Register(types.DefineHashedType("p2p.RequestMessage"), &p2p.RequestMessage{})
 
+	if gen, has := ob.rs.BlockGen(ob.round.TargetHeight); has {
+		if br, has := ob.round.BlockRoundMap[ob.round.TargetHeight]; has {
+			br.BlockGenMessageWait = gen
+		}
+	}
+
 	if s, err := ob.cs.cn.ServiceByName("fleta.apiserver"); err != nil {
 	} else if as, is := s.(*apiserver.APIServer); !is {
 	} else {
@@ -119,10 +136,72 @@ func (ob *ObserverNode) Init() error {
 			}
 			return nm, nil
 		})
+		js.Set("evidences", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			return ob.vr.Evidences()
+		})
 	}
 	return nil
 }
 
+// SetEvidenceStore sets the store that persists evidences of conflicting observer votes
+func (ob *ObserverNode) SetEvidenceStore(back backend.StoreBackend) {
+	ob.Lock()
+	defer ob.Unlock()
+
+	ob.vr = NewVoteRecorder(back)
+}
+
+// SetRoundStore sets the store that persists round vote acks and block votes signed by the observer (it should be called before the init)
+func (ob *ObserverNode) SetRoundStore(back backend.StoreBackend) error {
+	ob.Lock()
+	defer ob.Unlock()
+
+	rs, err := NewObserverRoundStore(back)
+	if err != nil {
+		return err
+	}
+	ob.rs = rs
+	return nil
+}
+
+// RoundState returns the state of the current vote round
+func (ob *ObserverNode) RoundState() int {
+	ob.Lock()
+	defer ob.Unlock()
+
+	return ob.round.RoundState
+}
+
+// Evidences returns all evidences of conflicting observer votes that are detected
+func (ob *ObserverNode) Evidences() ([]*ObserverEvidence, error) {
+	return ob.vr.Evidences()
+}
+
+// SetBLSKey sets the BLS key that signs blocks of the BLS signature version (it should be called before running)
+func (ob *ObserverNode) SetBLSKey(Key *bls.PrivateKey) {
+	ob.Lock()
+	defer ob.Unlock()
+
+	ob.blsKey = Key
+}
+
+// SetClock sets the clock of the observer (it should be called before running)
+func (ob *ObserverNode) SetClock(clock Clock) {
+	ob.Lock()
+	defer ob.Unlock()
+
+	ob.clock = clock
+	ob.requestTimer.SetClock(clock)
+}
+
+// SetTransport sets the transport of the observer mesh and the formulator service (it should be called before running)
+func (ob *ObserverNode) SetTransport(tr p2p.Transport) {
+	ob.Lock()
+	defer ob.Unlock()
+
+	ob.transport = tr
+}
+
 // Close terminates the observer
 func (ob *ObserverNode) Close() {
 	ob.closeLock.Lock()
@@ -132,6 +211,8 @@ func (ob *ObserverNode) Close() {
 	defer ob.Unlock()
 
 	ob.isClose = true
+	ob.ms.Close()
+	ob.fs.Close()
 	ob.cs.cn.Close()
 }
 
@@ -159,8 +240,8 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 
 	go func() {
 		for !ob.isClose {
-			hasMessage := false
 			for !ob.isClose {
+				hasMessage := false
 				for _, rq := range ob.recvQueues {
 					if v := rq.Pop(); v != nil {
 						hasMessage = true
@@ -177,13 +258,13 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 					break
 				}
 			}
-			time.Sleep(10 * time.Millisecond)
+			ob.clock.Sleep(10 * time.Millisecond)
 		}
 	}()
 	go func() {
 		for !ob.isClose {
-			hasMessage := false
 			for !ob.isClose {
+				hasMessage := false
 				for _, sq := range ob.sendQueues {
 					if v := sq.Pop(); v != nil {
 						hasMessage = true
@@ -203,18 +284,22 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 					break
 				}
 			}
-			time.Sleep(10 * time.Millisecond)
+			ob.clock.Sleep(10 * time.Millisecond)
 		}
 	}()
 
-	blockTimer := time.NewTimer(time.Millisecond)
-	queueTimer := time.NewTimer(time.Millisecond)
-	voteTimer := time.NewTimer(time.Millisecond)
+	blockTimer := ob.clock.NewTimer(time.Millisecond)
+	queueTimer := ob.clock.NewTimer(time.Millisecond)
+	voteTimer := ob.clock.NewTimer(time.Millisecond)
 	for !ob.isClose {
 		select {
-		case <-blockTimer.C:
+		case <-blockTimer.C():
 			cp := ob.cs.cn.Provider()
 			ob.Lock()
+			if ob.isClose {
+				ob.Unlock()
+				break
+			}
 			hasItem := false
 			TargetHeight := uint64(cp.Height() + 1)
 			Count := 0
@@ -227,7 +312,7 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 					break
 				}
 				if debug.DEBUG {
-					rlog.Println(cp.Height(), "BlockConnectedQ", b.Header.Generator.String(), ob.round.RoundState, b.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+					rlog.Println(cp.Height(), "BlockConnectedQ", b.Header.Generator.String(), ob.round.RoundState, b.Header.Height, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 				}
 				TargetHeight++
 				Count++
@@ -243,31 +328,39 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 				ob.broadcastStatus()
 			}
 			blockTimer.Reset(50 * time.Millisecond)
-		case <-queueTimer.C:
+		case <-queueTimer.C():
 			v := ob.messageQueue.Pop()
 			i := 0
 			for v != nil {
 				i++
 				item := v.(*messageItem)
 				ob.Lock()
+				if ob.isClose {
+					ob.Unlock()
+					break
+				}
 				ob.handleObserverMessage(item.PublicHash, item.Message, item.Raw)
 				ob.Unlock()
 				v = ob.messageQueue.Pop()
 			}
 			queueTimer.Reset(10 * time.Millisecond)
-		case <-voteTimer.C:
+		case <-voteTimer.C():
 			ob.Lock()
+			if ob.isClose {
+				ob.Unlock()
+				break
+			}
 			cp := ob.cs.cn.Provider()
 			ob.syncVoteRound()
 			IsFailable := true
 			if len(ob.adjustFormulatorMap()) > 0 {
 				if ob.round.MinRoundVoteAck != nil {
 					if debug.DEBUG {
-						rlog.Println(cp.Height(), "Current State", ob.round.MinRoundVoteAck.Formulator.String(), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+						rlog.Println(cp.Height(), "Current State", ob.round.MinRoundVoteAck.Formulator.String(), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 					}
 				} else {
 					if debug.DEBUG {
-						rlog.Println(cp.Height(), "Current State", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+						rlog.Println(cp.Height(), "Current State", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 					}
 				}
 				if IsFailable {
@@ -277,16 +370,16 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 							addr := ob.round.MinRoundVoteAck.Formulator
 							if _, has := ob.ignoreMap[addr]; has {
 								ob.fs.RemovePeer(string(addr[:]))
-								ob.ignoreMap[addr] = time.Now().UnixNano() + int64(120*time.Second)
+								ob.ignoreMap[addr] = ob.clock.Now().UnixNano() + int64(120*time.Second)
 							} else {
-								ob.ignoreMap[addr] = time.Now().UnixNano() + int64(30*time.Second)
+								ob.ignoreMap[addr] = ob.clock.Now().UnixNano() + int64(30*time.Second)
 							}
 							if debug.DEBUG {
-								rlog.Println(cp.Height(), "Failure", ob.round.MinRoundVoteAck.Formulator.String(), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+								rlog.Println(cp.Height(), "Failure", ob.round.MinRoundVoteAck.Formulator.String(), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 							}
 						} else {
 							if debug.DEBUG {
-								rlog.Println(cp.Height(), "Failure", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+								rlog.Println(cp.Height(), "Failure", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 							}
 						}
 						ob.resetVoteRound(true)
@@ -300,14 +393,14 @@ func (ob *ObserverNode) Run(BindObserver string, BindFormulator string) {
 					if has {
 						ob.sendBlockVote(br.BlockGenMessage)
 						if debug.DEBUG {
-							rlog.Println(cp.Height(), "sendBlockVote", ob.round.MinRoundVoteAck.Formulator.String(), encoding.Hash(br.BlockGenMessage.Block.Header), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+							rlog.Println(cp.Height(), "sendBlockVote", ob.round.MinRoundVoteAck.Formulator.String(), encoding.Hash(br.BlockGenMessage.Block.Header), ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 						}
 						IsFailable = false
 					}
 				}
 			} else {
 				if debug.DEBUG {
-					rlog.Println(cp.Height(), "No Formulator", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+					rlog.Println(cp.Height(), "No Formulator", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 				}
 			}
 			ob.Unlock()
@@ -357,7 +450,7 @@ func (ob *ObserverNode) syncVoteRound() {
 		}
 		if !IsContinue {
 			if debug.DEBUG {
-				rlog.Println(ob.cs.cn.Provider().Height(), "Turn Over", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+				rlog.Println(ob.cs.cn.Provider().Height(), "Turn Over", ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 			}
 			ob.resetVoteRound(false)
 		}
@@ -366,7 +459,13 @@ func (ob *ObserverNode) syncVoteRound() {
 
 func (ob *ObserverNode) resetVoteRound(resetStat bool) {
 	ob.round = NewVoteRound(ob.cs.cn.Provider().Height()+1, ob.cs.maxBlocksPerFormulator)
-	ob.prevRoundEndTime = time.Now().UnixNano()
+	ob.prevRoundEndTime = ob.clock.Now().UnixNano()
+	if err := ob.vr.Prune(ob.cs.cn.Provider().Height(), ob.cs.evidenceValidBlocks()); err != nil {
+		rlog.Println("VoteRecorder.Prune", err)
+	}
+	if err := ob.rs.Prune(ob.cs.cn.Provider().Height()); err != nil {
+		rlog.Println("RoundStore.Prune", err)
+	}
 	if resetStat {
 		ob.roundFirstTime = 0
 		ob.roundFirstHeight = 0
@@ -567,7 +666,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		if !msg.RoundVote.IsReply && SenderPublicHash != ob.myPublicHash {
 			ob.sendRoundVoteTo(SenderPublicHash)
 		}
-		if len(ob.round.RoundVoteMessageMap) >= ob.cs.observerKeyMap.Len()/2+2 {
+		if len(ob.round.RoundVoteMessageMap) >= ob.cs.roundVoteQuorum() {
 			votes := []*voteSortItem{}
 			for pubhash, v := range ob.round.RoundVoteMessageMap {
 				votes = append(votes, &voteSortItem{
@@ -581,7 +680,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 			ob.round.MinVotePublicHash = votes[0].PublicHash
 
 			if ob.roundFirstTime == 0 {
-				ob.roundFirstTime = uint64(time.Now().UnixNano())
+				ob.roundFirstTime = uint64(ob.clock.Now().UnixNano())
 				ob.roundFirstHeight = uint32(cp.Height())
 			}
 
@@ -595,7 +694,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 			}
 		}
 	case *RoundVoteAckMessage:
-		//rlog.Println(cp.Height(), "RoundVoteAckMessage", ob.round.RoundState, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+		//rlog.Println(cp.Height(), "RoundVoteAckMessage", ob.round.RoundState, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 		msgh := encoding.Hash(msg.RoundVoteAck)
 		if pubkey, err := common.RecoverPubkey(msgh, msg.Signature); err != nil {
 			return err
@@ -604,6 +703,9 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		} else if !ob.cs.observerKeyMap.Has(obkey) {
 			return ErrInvalidObserverKey
 		}
+		if sv, err := NewRoundVoteAckSignedVote(msg); err == nil {
+			ob.recordVote(SenderPublicHash, sv)
+		}
 
 		//[check round]
 		if msg.RoundVoteAck.TargetHeight != ob.round.TargetHeight {
@@ -664,13 +766,13 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		}
 		ob.round.RoundVoteAckMessageMap[SenderPublicHash] = msg
 
-		rlog.Println(cp.Height(), "RoundVoteAckMessage", ob.round.RoundState, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+		rlog.Println(cp.Height(), "RoundVoteAckMessage", ob.round.RoundState, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 
 		if !msg.RoundVoteAck.IsReply && SenderPublicHash != ob.myPublicHash {
 			ob.sendRoundVoteAckTo(SenderPublicHash)
 		}
 
-		if len(ob.round.RoundVoteAckMessageMap) >= ob.cs.observerKeyMap.Len()/2+1 {
+		if len(ob.round.RoundVoteAckMessageMap) >= ob.cs.ObserverQuorum() {
 			var MinRoundVoteAck *RoundVoteAck
 			PublicHashCountMap := map[common.PublicHash]int{}
 			TimeoutCountMap := map[uint32]int{}
@@ -682,7 +784,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 				PublicHashCount := PublicHashCountMap[vt.PublicHash]
 				PublicHashCount++
 				PublicHashCountMap[vt.PublicHash] = PublicHashCount
-				if TimeoutCount >= ob.cs.observerKeyMap.Len()/2+1 && PublicHashCount >= ob.cs.observerKeyMap.Len()/2+1 {
+				if TimeoutCount >= ob.cs.ObserverQuorum() && PublicHashCount >= ob.cs.ObserverQuorum() {
 					MinRoundVoteAck = vt
 					break
 				}
@@ -787,7 +889,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 			ob.sendBlockVoteTo(br.BlockGenMessage, SenderPublicHash)
 		}
 	case *BlockGenMessage:
-		rlog.Println(cp.Height(), "BlockGenMessage", ob.round.RoundState, msg.Block.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+		rlog.Println(cp.Height(), "BlockGenMessage", ob.round.RoundState, msg.Block.Header.Height, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 
 		//[check round]
 		br, has := ob.round.BlockRoundMap[msg.Block.Header.Height]
@@ -805,7 +907,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 				if len(raw) > 0 {
 					ob.ms.BroadcastRaw(raw)
 					if debug.DEBUG {
-						rlog.Println(cp.Height(), "BroadcastRaw", msg.Block.Header.Height, ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+						rlog.Println(cp.Height(), "BroadcastRaw", msg.Block.Header.Height, ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 					}
 
 					/*
@@ -822,7 +924,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 						if NextTop != nil {
 							ob.sendMessagePacket(1, NextTop.Address, raw, msg.Block.Header.Height)
 							if debug.DEBUG {
-								rlog.Println(cp.Height(), "BroadcastNextTop", msg.Block.Header.Height, ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+								rlog.Println(cp.Height(), "BroadcastNextTop", msg.Block.Header.Height, ob.round.RoundState, len(ob.adjustFormulatorMap()), ob.fs.PeerCount(), (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 							}
 						}
 					*/
@@ -876,6 +978,9 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		} else if Signer != ob.round.MinRoundVoteAck.FormulatorPublicHash {
 			rlog.Println(msg.Block.Header.Generator.String(), "if Signer != ob.round.MinRoundVoteAck.FormulatorPublicHash {")
 			return ErrInvalidVote
+		} else if err := validateVRF(&msg.Block.Header, pubkey, ob.cs.Seed()); err != nil {
+			rlog.Println(msg.Block.Header.Generator.String(), "if err := validateVRF(&msg.Block.Header, pubkey, ob.cs.Seed()); err != nil {")
+			return err
 		}
 		if err := ob.cs.ct.ValidateHeader(&msg.Block.Header); err != nil {
 			rlog.Println(msg.Block.Header.Generator.String(), "if err := ob.cs.ct.ValidateHeader(&msg.Block.Header); err != nil {")
@@ -883,7 +988,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		}
 
 		//[if valid block]
-		Now := uint64(time.Now().UnixNano())
+		Now := uint64(ob.clock.Now().UnixNano())
 		if msg.Block.Header.Timestamp > Now+uint64(10*time.Second) {
 			rlog.Println(msg.Block.Header.Generator.String(), "if msg.Block.Header.Timestamp > Now+uint64(10*time.Second) {")
 			return ErrInvalidVote
@@ -965,7 +1070,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 			ob.sendBlockVoteTo(br.BlockGenMessage, SenderPublicHash)
 		}
 	case *BlockVoteMessage:
-		//rlog.Println(cp.Height(), encoding.Hash(msg.BlockVote.Header), "BlockVoteMessage", ob.round.RoundState, msg.BlockVote.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+		//rlog.Println(cp.Height(), encoding.Hash(msg.BlockVote.Header), "BlockVoteMessage", ob.round.RoundState, msg.BlockVote.Header.Height, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 		msgh := encoding.Hash(msg.BlockVote)
 		if pubkey, err := common.RecoverPubkey(msgh, msg.Signature); err != nil {
 			return err
@@ -974,6 +1079,12 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		} else if !ob.cs.observerKeyMap.Has(obkey) {
 			return ErrInvalidObserverKey
 		}
+		if sv, err := NewBlockVoteSignedVote(msg); err == nil {
+			ob.recordVote(SenderPublicHash, sv)
+		}
+		if sv, err := NewBlockSignSignedVote(msg.BlockVote); err == nil {
+			ob.recordVote(SenderPublicHash, sv)
+		}
 
 		//[check round]
 		br, has := ob.round.BlockRoundMap[msg.BlockVote.Header.Height]
@@ -1056,6 +1167,13 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		} else if SenderPublicHash != common.NewPublicHash(pubkey) {
 			return ErrInvalidVote
 		}
+		if IsBLSVersion(msg.BlockVote.Header.Version) {
+			if pubkey, err := ob.cs.ObserverBLSKey(SenderPublicHash); err != nil {
+				return err
+			} else if !bls.Verify(pubkey, encoding.Hash(s), msg.BlockVote.ObserverBLSSignature) {
+				return ErrInvalidBLSSignature
+			}
+		}
 
 		if _, has := br.BlockVoteMap[SenderPublicHash]; has {
 			if !msg.BlockVote.IsReply {
@@ -1065,7 +1183,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		}
 		br.BlockVoteMap[SenderPublicHash] = msg.BlockVote
 
-		rlog.Println(cp.Height(), encoding.Hash(msg.BlockVote.Header), "BlockVoteMessage", ob.round.RoundState, msg.BlockVote.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+		rlog.Println(cp.Height(), encoding.Hash(msg.BlockVote.Header), "BlockVoteMessage", ob.round.RoundState, msg.BlockVote.Header.Height, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 
 		//[check state]
 		if !msg.BlockVote.IsReply && SenderPublicHash != ob.myPublicHash {
@@ -1073,10 +1191,22 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 		}
 
 		//[apply vote]
-		if len(br.BlockVoteMap) >= ob.cs.observerKeyMap.Len()/2+1 {
+		if len(br.BlockVoteMap) >= ob.cs.ObserverQuorum() {
 			sigs := []common.Signature{}
-			for _, vt := range br.BlockVoteMap {
-				sigs = append(sigs, vt.ObserverSignature)
+			if IsBLSVersion(br.BlockGenMessage.Block.Header.Version) {
+				SigMap := map[common.PublicHash]bls.Signature{}
+				for pubhash, vt := range br.BlockVoteMap {
+					SigMap[pubhash] = vt.ObserverBLSSignature
+				}
+				packed, err := ob.cs.PackBLSSignatures(SigMap)
+				if err != nil {
+					return err
+				}
+				sigs = packed
+			} else {
+				for _, vt := range br.BlockVoteMap {
+					sigs = append(sigs, vt.ObserverSignature)
+				}
 			}
 
 			b := &types.Block{
@@ -1146,7 +1276,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 				}
 			}
 			if debug.DEBUG {
-				rlog.Println(cp.Height(), "BlockConnected", b.Header.Generator.String(), ob.round.RoundState, msg.BlockVote.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
+				rlog.Println(cp.Height(), "BlockConnected", b.Header.Generator.String(), ob.round.RoundState, msg.BlockVote.Header.Height, (ob.clock.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 			}
 
 			NextHeight := ob.round.TargetHeight + 1
@@ -1156,7 +1286,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 			}
 			brNext, has := ob.round.BlockRoundMap[NextHeight]
 			if has && Top.Address == ob.round.MinRoundVoteAck.Formulator {
-				PastTime := uint64(time.Now().UnixNano()) - ob.roundFirstTime
+				PastTime := uint64(ob.clock.Now().UnixNano()) - ob.roundFirstTime
 				ExpectedTime := uint64(msg.BlockVote.Header.Height-ob.roundFirstHeight) * uint64(500*time.Millisecond)
 
 				if PastTime < ExpectedTime {
@@ -1164,7 +1294,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 					if diff > 500*time.Millisecond {
 						diff = 500 * time.Millisecond
 					}
-					time.Sleep(diff)
+					ob.clock.Sleep(diff)
 				}
 
 				ob.round.RoundState = BlockWaitState
@@ -1250,6 +1380,19 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicHash common.PublicHash
 	return nil
 }
 
+func (ob *ObserverNode) recordVote(PublicHash common.PublicHash, sv *SignedVote) {
+	ev, err := ob.vr.Record(PublicHash, sv)
+	if err != nil {
+		rlog.Println("RecordVote", PublicHash.String(), err)
+		return
+	}
+	if ev != nil {
+		if bs, err := ev.MarshalJSON(); err == nil {
+			rlog.Println("ObserverEquivocation", string(bs))
+		}
+	}
+}
+
 func (ob *ObserverNode) addBlock(b *types.Block) error {
 	cp := ob.cs.cn.Provider()
 	if b.Header.Height <= cp.Height() {
@@ -1275,7 +1418,7 @@ func (ob *ObserverNode) addBlock(b *types.Block) error {
 
 func (ob *ObserverNode) adjustFormulatorMap() map[common.Address]bool {
 	FormulatorMap := ob.fs.FormulatorMap()
-	now := time.Now().UnixNano()
+	now := ob.clock.Now().UnixNano()
 	for addr := range FormulatorMap {
 		if now < ob.ignoreMap[addr] {
 			delete(FormulatorMap, addr)
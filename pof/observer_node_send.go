@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/rlog"
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/encoding"
 	"github.com/fletaio/fleta_testnet/service/p2p"
@@ -25,7 +26,7 @@ func (ob *ObserverNode) sendRoundVote() error {
 			TimeoutCount:         uint32(TimeoutCount),
 			Formulator:           Top.Address,
 			FormulatorPublicHash: Top.PublicHash,
-			Timestamp:            uint64(time.Now().UnixNano()),
+			Timestamp:            uint64(ob.clock.Now().UnixNano()),
 			IsReply:              false,
 		},
 	}
@@ -44,7 +45,7 @@ func (ob *ObserverNode) sendRoundVote() error {
 	}
 	ob.round.VoteFailCount = 0
 
-	if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVote)); err != nil {
+	if sig, err := signRoundVote(ob.key, nm.RoundVote); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -89,10 +90,10 @@ func (ob *ObserverNode) sendRoundVoteTo(TargetPubHash common.PublicHash) error {
 			nm.RoundVote.TimeoutCount = 0
 			nm.RoundVote.TargetHeight = TargetHeight
 			nm.RoundVote.LastHash = lastHash
-			nm.RoundVote.Timestamp = uint64(time.Now().UnixNano())
+			nm.RoundVote.Timestamp = uint64(ob.clock.Now().UnixNano())
 		}
 
-		if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVote)); err != nil {
+		if sig, err := signRoundVote(ob.key, nm.RoundVote); err != nil {
 			return err
 		} else {
 			nm.Signature = sig
@@ -113,12 +114,12 @@ func (ob *ObserverNode) sendRoundVoteTo(TargetPubHash common.PublicHash) error {
 				TimeoutCount:         uint32(TimeoutCount),
 				Formulator:           Top.Address,
 				FormulatorPublicHash: Top.PublicHash,
-				Timestamp:            uint64(time.Now().UnixNano()),
+				Timestamp:            uint64(ob.clock.Now().UnixNano()),
 				IsReply:              true,
 			},
 		}
 
-		if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVote)); err != nil {
+		if sig, err := signRoundVote(ob.key, nm.RoundVote); err != nil {
 			return err
 		} else {
 			nm.Signature = sig
@@ -140,11 +141,14 @@ func (ob *ObserverNode) sendRoundVoteAck() error {
 			Formulator:           MinRoundVote.Formulator,
 			FormulatorPublicHash: MinRoundVote.FormulatorPublicHash,
 			PublicHash:           ob.round.MinVotePublicHash,
-			Timestamp:            uint64(time.Now().UnixNano()),
+			Timestamp:            uint64(ob.clock.Now().UnixNano()),
 			IsReply:              false,
 		},
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVoteAck)); err != nil {
+	if err := ob.recordRoundVoteAck(nm.RoundVoteAck); err != nil {
+		return err
+	}
+	if sig, err := signRoundVoteAck(ob.key, nm.RoundVoteAck); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -189,10 +193,13 @@ func (ob *ObserverNode) sendRoundVoteAckTo(TargetPubHash common.PublicHash) erro
 			nm.RoundVoteAck.TimeoutCount = 0
 			nm.RoundVoteAck.TargetHeight = TargetHeight
 			nm.RoundVoteAck.LastHash = lastHash
-			nm.RoundVoteAck.Timestamp = uint64(time.Now().UnixNano())
+			nm.RoundVoteAck.Timestamp = uint64(ob.clock.Now().UnixNano())
 		}
 
-		if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVoteAck)); err != nil {
+		if err := ob.recordRoundVoteAck(nm.RoundVoteAck); err != nil {
+			return err
+		}
+		if sig, err := signRoundVoteAck(ob.key, nm.RoundVoteAck); err != nil {
 			return err
 		} else {
 			nm.Signature = sig
@@ -218,7 +225,10 @@ func (ob *ObserverNode) sendRoundVoteAckTo(TargetPubHash common.PublicHash) erro
 			},
 		}
 
-		if sig, err := ob.key.Sign(encoding.Hash(nm.RoundVoteAck)); err != nil {
+		if err := ob.recordRoundVoteAck(nm.RoundVoteAck); err != nil {
+			return err
+		}
+		if sig, err := signRoundVoteAck(ob.key, nm.RoundVoteAck); err != nil {
 			return err
 		} else {
 			nm.Signature = sig
@@ -239,11 +249,11 @@ func (ob *ObserverNode) sendRoundSetup() error {
 			Formulator:           ob.round.MinRoundVoteAck.Formulator,
 			FormulatorPublicHash: ob.round.MinRoundVoteAck.FormulatorPublicHash,
 			PublicHash:           ob.round.MinRoundVoteAck.PublicHash,
-			Timestamp:            uint64(time.Now().UnixNano()),
+			Timestamp:            uint64(ob.clock.Now().UnixNano()),
 			IsReply:              false,
 		},
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(nm.MinRoundVoteAck)); err != nil {
+	if sig, err := signRoundVoteAck(ob.key, nm.MinRoundVoteAck); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -267,12 +277,21 @@ func (ob *ObserverNode) sendBlockVote(gen *BlockGenMessage) error {
 		HeaderHash:         encoding.Hash(gen.Block.Header),
 		GeneratorSignature: gen.GeneratorSignature,
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(s)); err != nil {
+	if err := ob.recordBlockVote(gen); err != nil {
+		return err
+	}
+	if sig, err := signBlockSign(ob.key, &gen.Block.Header, gen.GeneratorSignature); err != nil {
 		return err
 	} else {
 		nm.BlockVote.ObserverSignature = sig
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(nm.BlockVote)); err != nil {
+	if IsBLSVersion(gen.Block.Header.Version) {
+		if ob.blsKey == nil {
+			return ErrNotExistObserverBLSKey
+		}
+		nm.BlockVote.ObserverBLSSignature = ob.blsKey.Sign(encoding.Hash(s))
+	}
+	if sig, err := signBlockVote(ob.key, nm.BlockVote); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -287,6 +306,24 @@ func (ob *ObserverNode) sendBlockVote(gen *BlockGenMessage) error {
 	return nil
 }
 
+// recordRoundVoteAck should be called before signing the round vote ack so that the conflicting ack is not signed
+func (ob *ObserverNode) recordRoundVoteAck(vt *RoundVoteAck) error {
+	if err := ob.rs.RecordRoundVoteAck(vt); err != nil {
+		rlog.Println("Observer", "RecordRoundVoteAck", vt.TargetHeight, vt.TimeoutCount, err)
+		return err
+	}
+	return nil
+}
+
+// recordBlockVote should be called before signing the block vote so that the conflicting block is not signed
+func (ob *ObserverNode) recordBlockVote(gen *BlockGenMessage) error {
+	if err := ob.rs.RecordBlockVote(gen); err != nil {
+		rlog.Println("Observer", "RecordBlockVote", gen.Block.Header.Height, err)
+		return err
+	}
+	return nil
+}
+
 func (ob *ObserverNode) sendBlockGenTo(gen *BlockGenMessage, TargetPubHash common.PublicHash) error {
 	if TargetPubHash == ob.myPublicHash {
 		return nil
@@ -313,12 +350,21 @@ func (ob *ObserverNode) sendBlockVoteTo(gen *BlockGenMessage, TargetPubHash comm
 		HeaderHash:         encoding.Hash(gen.Block.Header),
 		GeneratorSignature: gen.GeneratorSignature,
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(s)); err != nil {
+	if err := ob.recordBlockVote(gen); err != nil {
+		return err
+	}
+	if sig, err := signBlockSign(ob.key, &gen.Block.Header, gen.GeneratorSignature); err != nil {
 		return err
 	} else {
 		nm.BlockVote.ObserverSignature = sig
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(nm.BlockVote)); err != nil {
+	if IsBLSVersion(gen.Block.Header.Version) {
+		if ob.blsKey == nil {
+			return ErrNotExistObserverBLSKey
+		}
+		nm.BlockVote.ObserverBLSSignature = ob.blsKey.Sign(encoding.Hash(s))
+	}
+	if sig, err := signBlockVote(ob.key, nm.BlockVote); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -329,7 +375,7 @@ func (ob *ObserverNode) sendBlockVoteTo(gen *BlockGenMessage, TargetPubHash comm
 }
 
 func (ob *ObserverNode) sendBlockGenRequest(br *BlockRound) error {
-	now := uint64(time.Now().UnixNano())
+	now := uint64(ob.clock.Now().UnixNano())
 	if br.LastBlockGenRequestTime+uint64(1*time.Second) > now {
 		return nil
 	}
@@ -358,10 +404,10 @@ func (ob *ObserverNode) sendBlockGenRequest(br *BlockRound) error {
 			Formulator:           ob.round.MinRoundVoteAck.Formulator,
 			FormulatorPublicHash: ob.round.MinRoundVoteAck.FormulatorPublicHash,
 			PublicHash:           ob.round.MinRoundVoteAck.PublicHash,
-			Timestamp:            uint64(time.Now().UnixNano()),
+			Timestamp:            uint64(ob.clock.Now().UnixNano()),
 		},
 	}
-	if sig, err := ob.key.Sign(encoding.Hash(nm.BlockGenRequest)); err != nil {
+	if sig, err := signBlockGenRequest(ob.key, nm.BlockGenRequest); err != nil {
 		return err
 	} else {
 		nm.Signature = sig
@@ -0,0 +1,198 @@
+package pof
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/fletaio/fleta_testnet/core/backend"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// ObserverRoundStore keeps round vote acks and block votes that are signed by the observer itself
+// It is checked before every signature of them, so the observer restarted in the middle of the round
+// resumes by the recorded block or abstains instead of signing a conflicting message
+// Round votes are not recorded because they are replaced by the timestamp and not binding
+type ObserverRoundStore struct {
+	sync.Mutex
+	back        backend.StoreBackend
+	ackMap      map[roundKey]*RoundVoteAck
+	genMap      map[roundKey]*BlockGenMessage
+	keepHeights uint32
+}
+
+type roundKey struct {
+	Height       uint32
+	TimeoutCount uint32
+}
+
+// NewObserverRoundStore returns a ObserverRoundStore that loads records of the backend (records are kept in memory only when back is nil)
+func NewObserverRoundStore(back backend.StoreBackend) (*ObserverRoundStore, error) {
+	rs := newObserverRoundStore(back)
+	if back != nil {
+		if err := back.View(func(txn backend.StoreReader) error {
+			if err := txn.Iterate(tagRoundVoteAck, func(key []byte, value []byte) error {
+				vt := &RoundVoteAck{}
+				if err := encoding.Unmarshal(value, &vt); err != nil {
+					return err
+				}
+				rs.ackMap[roundKey{Height: vt.TargetHeight, TimeoutCount: vt.TimeoutCount}] = vt
+				return nil
+			}); err != nil {
+				return err
+			}
+			if err := txn.Iterate(tagBlockVoteGen, func(key []byte, value []byte) error {
+				gen := &BlockGenMessage{}
+				if err := encoding.Unmarshal(value, &gen); err != nil {
+					return err
+				}
+				TimeoutCount, err := types.DecodeTimeoutCount(gen.Block.Header.ConsensusData)
+				if err != nil {
+					return err
+				}
+				rs.genMap[roundKey{Height: gen.Block.Header.Height, TimeoutCount: TimeoutCount}] = gen
+				return nil
+			}); err != nil {
+				return err
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+func newObserverRoundStore(back backend.StoreBackend) *ObserverRoundStore {
+	return &ObserverRoundStore{
+		back:        back,
+		ackMap:      map[roundKey]*RoundVoteAck{},
+		genMap:      map[roundKey]*BlockGenMessage{},
+		keepHeights: 10,
+	}
+}
+
+// RecordRoundVoteAck records the round vote ack before it is signed
+// It fails when the different ack is recorded at the same height and the same timeout count
+func (rs *ObserverRoundStore) RecordRoundVoteAck(vt *RoundVoteAck) error {
+	rs.Lock()
+	defer rs.Unlock()
+
+	key := roundKey{Height: vt.TargetHeight, TimeoutCount: vt.TimeoutCount}
+	if old, has := rs.ackMap[key]; has {
+		if vt.ChainID != old.ChainID ||
+			vt.LastHash != old.LastHash ||
+			vt.Formulator != old.Formulator ||
+			vt.FormulatorPublicHash != old.FormulatorPublicHash {
+			return ErrAlreadySignedRound
+		}
+		return nil
+	}
+	if rs.back != nil {
+		data, err := encoding.Marshal(vt)
+		if err != nil {
+			return err
+		}
+		if err := rs.back.Update(func(txn backend.StoreWriter) error {
+			return txn.Set(toRoundStoreKey(tagRoundVoteAck, key), data)
+		}); err != nil {
+			return err
+		}
+	}
+	rs.ackMap[key] = vt
+	return nil
+}
+
+// RecordBlockVote records the block before the block vote of it is signed
+// It fails when the different block is recorded at the same height and the same timeout count
+func (rs *ObserverRoundStore) RecordBlockVote(gen *BlockGenMessage) error {
+	TimeoutCount, err := types.DecodeTimeoutCount(gen.Block.Header.ConsensusData)
+	if err != nil {
+		return err
+	}
+
+	rs.Lock()
+	defer rs.Unlock()
+
+	key := roundKey{Height: gen.Block.Header.Height, TimeoutCount: TimeoutCount}
+	if old, has := rs.genMap[key]; has {
+		if encoding.Hash(gen.Block.Header) != encoding.Hash(old.Block.Header) {
+			return ErrAlreadySignedRound
+		}
+		return nil
+	}
+	if rs.back != nil {
+		data, err := encoding.Marshal(gen)
+		if err != nil {
+			return err
+		}
+		if err := rs.back.Update(func(txn backend.StoreWriter) error {
+			return txn.Set(toRoundStoreKey(tagBlockVoteGen, key), data)
+		}); err != nil {
+			return err
+		}
+	}
+	rs.genMap[key] = gen
+	return nil
+}
+
+// BlockGen returns the block of the highest timeout count that the block vote is signed for at the height
+func (rs *ObserverRoundStore) BlockGen(Height uint32) (*BlockGenMessage, bool) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	var gen *BlockGenMessage
+	var TimeoutCount uint32
+	for key, v := range rs.genMap {
+		if key.Height == Height && (gen == nil || key.TimeoutCount > TimeoutCount) {
+			gen = v
+			TimeoutCount = key.TimeoutCount
+		}
+	}
+	return gen, gen != nil
+}
+
+// Prune removes records that are older than the keeping range from the given height
+func (rs *ObserverRoundStore) Prune(Height uint32) error {
+	rs.Lock()
+	defer rs.Unlock()
+
+	if Height <= rs.keepHeights {
+		return nil
+	}
+	LowestHeight := Height - rs.keepHeights
+	keys := [][]byte{}
+	for key := range rs.ackMap {
+		if key.Height < LowestHeight {
+			keys = append(keys, toRoundStoreKey(tagRoundVoteAck, key))
+			delete(rs.ackMap, key)
+		}
+	}
+	for key := range rs.genMap {
+		if key.Height < LowestHeight {
+			keys = append(keys, toRoundStoreKey(tagBlockVoteGen, key))
+			delete(rs.genMap, key)
+		}
+	}
+	if rs.back != nil && len(keys) > 0 {
+		if err := rs.back.Update(func(txn backend.StoreWriter) error {
+			for _, key := range keys {
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toRoundStoreKey(tag []byte, key roundKey) []byte {
+	bs := make([]byte, len(tag)+8)
+	copy(bs, tag)
+	binary.BigEndian.PutUint32(bs[len(tag):], key.Height)
+	binary.BigEndian.PutUint32(bs[len(tag)+4:], key.TimeoutCount)
+	return bs
+}
@@ -0,0 +1,135 @@
+package pof
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// RankTracker follows the rank table by headers and account changes of blocks without the chain data
+// It starts from the saved data of the consensus at the trusted height and it is used to check generators of next headers
+// The consensus policy of the saved data is used, so it should be started again from the saved data after the policy is updated
+type RankTracker struct {
+	rt                     *RankTable
+	maxBlocksPerFormulator uint32
+	blocksBySameFormulator uint32
+	seed                   hash.Hash256
+	observerQuorum         int
+	maxPhaseDiffPolicy     uint32
+	hasPolicy              bool
+	maxPhaseDiff           func(Height uint32) uint32
+}
+
+// NewRankTracker returns a RankTracker from the saved data of the consensus
+func NewRankTracker(SaveData []byte, ObserverKeys []common.PublicHash) (*RankTracker, error) {
+	sd, err := decodeSaveData(SaveData)
+	if err != nil {
+		return nil, err
+	}
+	KeyMap := types.NewPublicHashBoolMap()
+	for _, pubhash := range ObserverKeys {
+		KeyMap.Put(pubhash, true)
+	}
+	if !isSameObserverKeyMap(sd.ObserverKeyMap, KeyMap) {
+		return nil, ErrInvalidObserverKey
+	}
+	tr := &RankTracker{
+		rt:                     sd.rt,
+		maxBlocksPerFormulator: sd.MaxBlocksPerFormulator,
+		blocksBySameFormulator: sd.BlocksBySameFormulator,
+		seed:                   sd.Seed,
+		observerQuorum:         observerQuorum(KeyMap.Len(), sd.ObserverQuorum),
+		maxPhaseDiffPolicy:     sd.MaxPhaseDiff,
+		hasPolicy:              sd.HasPolicy,
+	}
+	return tr, nil
+}
+
+// SetMaxPhaseDiff sets the max phase difference function that is same with the one of the consensus
+// It is only used until the consensus policy is set on chain as like the consensus
+func (tr *RankTracker) SetMaxPhaseDiff(fn func(Height uint32) uint32) {
+	tr.maxPhaseDiff = fn
+}
+
+// Clone returns the clonend value of it
+func (tr *RankTracker) Clone() *RankTracker {
+	data, err := encoding.Marshal(tr.rt)
+	if err != nil {
+		panic(err)
+	}
+	rt := NewRankTable()
+	if err := encoding.Unmarshal(data, &rt); err != nil {
+		panic(err)
+	}
+	return &RankTracker{
+		rt:                     rt,
+		maxBlocksPerFormulator: tr.maxBlocksPerFormulator,
+		blocksBySameFormulator: tr.blocksBySameFormulator,
+		seed:                   tr.seed,
+		observerQuorum:         tr.observerQuorum,
+		maxPhaseDiffPolicy:     tr.maxPhaseDiffPolicy,
+		hasPolicy:              tr.hasPolicy,
+		maxPhaseDiff:           tr.maxPhaseDiff,
+	}
+}
+
+// ObserverQuorum returns the number of observer signatures that is required for a block
+func (tr *RankTracker) ObserverQuorum() int {
+	return tr.observerQuorum
+}
+
+// Candidates returns a candidates
+func (tr *RankTracker) Candidates() []*Rank {
+	return tr.rt.Candidates()
+}
+
+// ValidateGenerator checks that the header is generated by the top rank of the tracked rank table
+// The VRF output of the header is also checked when the header version includes it
+func (tr *RankTracker) ValidateGenerator(bh *types.Header, GeneratorSignature common.Signature) error {
+	TimeoutCount, err := types.DecodeTimeoutCount(bh.ConsensusData)
+	if err != nil {
+		return err
+	}
+	Top, err := tr.rt.TopRank(int(TimeoutCount))
+	if err != nil {
+		return err
+	}
+	if Top.Address != bh.Generator {
+		return ErrInvalidTopAddress
+	}
+	pubkey, err := common.RecoverPubkey(encoding.Hash(bh), GeneratorSignature)
+	if err != nil {
+		return err
+	}
+	if Top.PublicHash != common.NewPublicHash(pubkey) {
+		return ErrInvalidTopSignature
+	}
+	if err := validateVRF(bh, pubkey, tr.seed); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apply updates the rank table by the header and account changes of the block
+// It follows the same steps with OnSaveData of the consensus
+func (tr *RankTracker) Apply(bh *types.Header, AccountMap *types.AddressAccountMap, DeletedAccounts []common.Address) error {
+	TimeoutCount, err := types.DecodeTimeoutCount(bh.ConsensusData)
+	if err != nil {
+		return err
+	}
+	Seed, err := nextSeed(bh)
+	if err != nil {
+		return err
+	}
+	if v, err := forwardRankTable(tr.rt, TimeoutCount, Seed, tr.blocksBySameFormulator, tr.maxBlocksPerFormulator); err != nil {
+		return err
+	} else {
+		tr.blocksBySameFormulator = v
+	}
+	tr.seed = Seed
+	if err := updateRankTable(tr.rt, phaseDiffFunc(tr.maxPhaseDiff, tr.maxPhaseDiffPolicy, tr.hasPolicy), bh.Height, AccountMap, DeletedAccounts); err != nil {
+		return err
+	}
+	return nil
+}
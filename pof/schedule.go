@@ -0,0 +1,182 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// ScheduleItem is a projected run of blocks of a formulator
+type ScheduleItem struct {
+	Formulator  common.Address
+	FromHeight  uint32
+	ToHeight    uint32
+	IsEstimated bool
+}
+
+// MarshalJSON is a marshaler function
+func (item *ScheduleItem) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"formulator":`)
+	if bs, err := item.Formulator.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from_height":`)
+	if bs, err := json.Marshal(item.FromHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to_height":`)
+	if bs, err := json.Marshal(item.ToHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"is_estimated":`)
+	if bs, err := json.Marshal(item.IsEstimated); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// Schedule is the projection of next generators from the current rank table
+// It assumes that no timeout occurs, so it is broken by the first timeout
+type Schedule struct {
+	Height          uint32
+	AssumeNoTimeout bool
+	Items           []*ScheduleItem
+	Position        int
+	NextHeight      uint32
+}
+
+// MarshalJSON is a marshaler function
+func (sc *Schedule) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(sc.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"assume_no_timeout":`)
+	if bs, err := json.Marshal(sc.AssumeNoTimeout); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"items":`)
+	buffer.WriteString(`[`)
+	for i, item := range sc.Items {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := item.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"position":`)
+	if bs, err := json.Marshal(sc.Position); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"next_height":`)
+	if bs, err := json.Marshal(sc.NextHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// Schedule projects the next Count generators and the heights that they will generate
+// The position of the formulator is its index in the rank table (-1 means not a candidate)
+// A forwarded top gets the hash space from the header that is not generated yet,
+// so items that depend on the order of it in the same phase are marked as estimated
+func (cs *Consensus) Schedule(Count int, Formulator common.Address) *Schedule {
+	cs.Lock()
+	defer cs.Unlock()
+
+	sc := &Schedule{
+		Height:          cs.cn.Provider().Height(),
+		AssumeNoTimeout: true,
+		Items:           []*ScheduleItem{},
+		Position:        -1,
+	}
+	candidates := cs.rt.Candidates()
+	for i, r := range candidates {
+		if r.Address == Formulator {
+			sc.Position = i
+			break
+		}
+	}
+	if len(candidates) == 0 || cs.maxBlocksPerFormulator == 0 {
+		return sc
+	}
+
+	var maxHashSpace hash.Hash256
+	for i := range maxHashSpace {
+		maxHashSpace[i] = 0xFF
+	}
+	unknownMap := map[common.Address]bool{}
+	IsEstimated := false
+	Height := sc.Height + 1
+	Remained := cs.maxBlocksPerFormulator - cs.blocksBySameFormulator
+	for len(sc.Items) < Count {
+		top := candidates[0]
+		if !IsEstimated {
+			if unknownMap[top.Address] {
+				IsEstimated = true
+			} else {
+				for _, r := range candidates[1:] {
+					if r.Phase() != top.Phase() {
+						break
+					}
+					if unknownMap[r.Address] {
+						IsEstimated = true
+						break
+					}
+				}
+			}
+		}
+		item := &ScheduleItem{
+			Formulator:  top.Address,
+			FromHeight:  Height,
+			ToHeight:    Height + Remained - 1,
+			IsEstimated: IsEstimated,
+		}
+		sc.Items = append(sc.Items, item)
+		if sc.NextHeight == 0 && top.Address == Formulator {
+			sc.NextHeight = item.FromHeight
+		}
+		Height = item.ToHeight + 1
+		Remained = cs.maxBlocksPerFormulator
+
+		// the hash space of the forwarded top is unknown, so it is placed at the end of its phase
+		top.Set(top.Phase()+1, maxHashSpace)
+		unknownMap[top.Address] = true
+		candidates = InsertRankToList(candidates[1:], top)
+	}
+	return sc
+}
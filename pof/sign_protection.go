@@ -0,0 +1,192 @@
+package pof
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// SignRecord is the record of the header that is signed by the generator key
+// It is keyed by the round of the header (the height and the timeout count) as like the evidence of the double sign
+type SignRecord struct {
+	Height       uint32
+	TimeoutCount uint32
+	HeaderHash   hash.Hash256
+}
+
+// NewSignRecord returns the SignRecord of the header
+func NewSignRecord(bh *types.Header) (*SignRecord, error) {
+	TimeoutCount, err := types.DecodeTimeoutCount(bh.ConsensusData)
+	if err != nil {
+		return nil, err
+	}
+	return &SignRecord{
+		Height:       bh.Height,
+		TimeoutCount: TimeoutCount,
+		HeaderHash:   encoding.Hash(bh),
+	}, nil
+}
+
+// MarshalJSON is a marshaler function
+func (rec *SignRecord) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(rec.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timeout_count":`)
+	if bs, err := json.Marshal(rec.TimeoutCount); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"header_hash":`)
+	if bs, err := rec.HeaderHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (rec *SignRecord) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Height       uint32       `json:"height"`
+		TimeoutCount uint32       `json:"timeout_count"`
+		HeaderHash   hash.Hash256 `json:"header_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	rec.Height = v.Height
+	rec.TimeoutCount = v.TimeoutCount
+	rec.HeaderHash = v.HeaderHash
+	return nil
+}
+
+// SignProtection is the durable record of headers that are signed by the generator key
+// It is checked before every signature so that the key never signs two different headers in the same round,
+// even after the restart or when the record is moved with the key to another host
+type SignProtection struct {
+	sync.Mutex
+	back backend.StoreBackend
+}
+
+// NewSignProtection returns a SignProtection that keeps records in the backend
+func NewSignProtection(back backend.StoreBackend) *SignProtection {
+	return &SignProtection{
+		back: back,
+	}
+}
+
+// RecordSign records the header before it is signed
+// It fails when another header is recorded at the same height and the same timeout count
+func (sp *SignProtection) RecordSign(bh *types.Header) error {
+	rec, err := NewSignRecord(bh)
+	if err != nil {
+		return err
+	}
+	return sp.Record(rec)
+}
+
+// Record records the sign record before the header of it is signed
+func (sp *SignProtection) Record(rec *SignRecord) error {
+	sp.Lock()
+	defer sp.Unlock()
+
+	return sp.back.Update(func(txn backend.StoreWriter) error {
+		return putSignRecord(txn, rec)
+	})
+}
+
+// Records returns all records in the order of the height and the timeout count to export them
+func (sp *SignProtection) Records() ([]*SignRecord, error) {
+	sp.Lock()
+	defer sp.Unlock()
+
+	list := []*SignRecord{}
+	if err := sp.back.View(func(txn backend.StoreReader) error {
+		return txn.Iterate(tagSignRecord, func(key []byte, value []byte) error {
+			rec, err := fromSignRecordKey(key)
+			if err != nil {
+				return err
+			}
+			copy(rec.HeaderHash[:], value)
+			list = append(list, rec)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Height == list[j].Height {
+			return list[i].TimeoutCount < list[j].TimeoutCount
+		}
+		return list[i].Height < list[j].Height
+	})
+	return list, nil
+}
+
+// Import merges exported records, nothing is imported when any of them conflicts with the recorded one
+func (sp *SignProtection) Import(list []*SignRecord) error {
+	sp.Lock()
+	defer sp.Unlock()
+
+	return sp.back.Update(func(txn backend.StoreWriter) error {
+		for _, rec := range list {
+			if err := putSignRecord(txn, rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func putSignRecord(txn backend.StoreWriter, rec *SignRecord) error {
+	key := toSignRecordKey(rec.Height, rec.TimeoutCount)
+	if value, err := txn.Get(key); err != nil {
+		if err != backend.ErrNotExistKey {
+			return err
+		}
+	} else {
+		var h hash.Hash256
+		copy(h[:], value)
+		if h != rec.HeaderHash {
+			return ErrAlreadySignedHeight
+		}
+		return nil
+	}
+	return txn.Set(key, rec.HeaderHash[:])
+}
+
+func toSignRecordKey(Height uint32, TimeoutCount uint32) []byte {
+	bs := make([]byte, len(tagSignRecord)+8)
+	copy(bs, tagSignRecord)
+	binary.BigEndian.PutUint32(bs[len(tagSignRecord):], Height)
+	binary.BigEndian.PutUint32(bs[len(tagSignRecord)+4:], TimeoutCount)
+	return bs
+}
+
+func fromSignRecordKey(key []byte) (*SignRecord, error) {
+	if len(key) != len(tagSignRecord)+8 {
+		return nil, ErrInvalidSignRecord
+	}
+	return &SignRecord{
+		Height:       binary.BigEndian.Uint32(key[len(tagSignRecord):]),
+		TimeoutCount: binary.BigEndian.Uint32(key[len(tagSignRecord)+4:]),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHarness_AdminPause(t *testing.T) {
+	cfg := testConfig()
+	cfg.FormulatorCount = 3
+	h, closer := newTestHarness(t, cfg)
+	defer closer()
+
+	checkHeight(t, h, 3, 60*time.Second)
+
+	fr := h.Formulators()[0]
+	fr.fr.Pause()
+	// the formulator can be generating blocks of the current turn
+	if err := h.WaitProgress(5, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	Paused := fr.fr.Status().LastGenHeight
+	if err := h.WaitProgress(10, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if st := fr.fr.Status(); !st.IsPaused || st.LastGenHeight != Paused {
+		t.Fatal("paused formulator generates blocks", Paused, st.LastGenHeight)
+	}
+
+	fr.fr.Resume()
+	// observers can ignore the formulator for a while when it is paused in its turn, so check only the reconnection
+	if !h.WaitUntil(func() bool {
+		return len(fr.fr.ObserverPeers()) == len(h.Observers()) && fr.Height() >= h.Observers()[0].Height()
+	}, 60*time.Second) {
+		t.Fatal("resumed formulator does not sync", len(fr.fr.ObserverPeers()), fr.Height())
+	}
+
+	st := h.Observers()[0].ob.Status()
+	if st.Height == 0 || st.TargetHeight <= st.Height-1 {
+		t.Fatal("invalid observer status", st.Height, st.TargetHeight)
+	}
+	if len(h.Observers()[0].ob.ObserverPeers()) != 4 {
+		t.Fatal("invalid observer peer count", len(h.Observers()[0].ob.ObserverPeers()))
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+}
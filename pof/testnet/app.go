@@ -0,0 +1,134 @@
+package testnet
+
+import (
+	"strconv"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/formulator"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+type genesisFormulator struct {
+	Address common.Address
+	KeyHash common.PublicHash
+	GenHash common.PublicHash
+}
+
+// testApp creates the minimal genesis that has only formulators of the test network
+type testApp struct {
+	*types.ApplicationBase
+	pm          types.ProcessManager
+	cn          types.Provider
+	formulators []*genesisFormulator
+}
+
+func (app *testApp) Name() string {
+	return "TestnetApp"
+}
+
+func (app *testApp) Version() string {
+	return "v1.0.0"
+}
+
+func (app *testApp) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
+	app.pm = pm
+	app.cn = cn
+	return nil
+}
+
+func (app *testApp) InitGenesis(ctw *types.ContextWrapper) error {
+	alphaPolicy := &formulator.AlphaPolicy{
+		AlphaCreationLimitHeight:  5184000,
+		AlphaCreationAmount:       amount.NewCoinAmount(200000, 0),
+		AlphaUnlockRequiredBlocks: 2592000,
+	}
+
+	if p, err := app.pm.ProcessByName("fleta.admin"); err != nil {
+		return err
+	} else if ap, is := p.(*admin.Admin); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := ap.InitAdmin(ctw, map[string]common.Address{
+			"fleta.formulator": common.NewAddress(0, 0, 0),
+			"fleta.vault":      common.NewAddress(0, 0, 0),
+		}); err != nil {
+			return err
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.vault"); err != nil {
+		return err
+	} else if sp, is := p.(*vault.Vault); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := sp.InitPolicy(ctw, &vault.Policy{
+			AccountCreationAmount: amount.NewCoinAmount(10, 0),
+		}); err != nil {
+			return err
+		}
+		if err := sp.InitUpgrades(ctw); err != nil {
+			return err
+		}
+		for i, v := range app.formulators {
+			acc := &formulator.FormulatorAccount{
+				Address_:       v.Address,
+				Name_:          "formulator" + strconv.Itoa(i),
+				FormulatorType: formulator.AlphaFormulatorType,
+				KeyHash:        v.KeyHash,
+				GenHash:        v.GenHash,
+				Amount:         alphaPolicy.AlphaCreationAmount,
+			}
+			if err := ctw.CreateAccount(acc); err != nil {
+				return err
+			}
+			if err := sp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	if p, err := app.pm.ProcessByName("fleta.formulator"); err != nil {
+		return err
+	} else if fp, is := p.(*formulator.Formulator); !is {
+		return types.ErrNotExistProcess
+	} else {
+		if err := fp.InitPolicy(ctw,
+			&formulator.RewardPolicy{
+				RewardPerBlock:        amount.NewCoinAmount(0, 0),
+				PayRewardEveryBlocks:  172800,
+				AlphaEfficiency1000:   1000,
+				SigmaEfficiency1000:   1150,
+				OmegaEfficiency1000:   1300,
+				HyperEfficiency1000:   1300,
+				StakingEfficiency1000: 700,
+			},
+			alphaPolicy,
+			&formulator.SigmaPolicy{
+				SigmaRequiredAlphaBlocks:  5184000,
+				SigmaRequiredAlphaCount:   4,
+				SigmaUnlockRequiredBlocks: 2592000,
+			},
+			&formulator.OmegaPolicy{
+				OmegaRequiredSigmaBlocks:  5184000,
+				OmegaRequiredSigmaCount:   2,
+				OmegaUnlockRequiredBlocks: 2592000,
+			},
+			&formulator.HyperPolicy{
+				HyperCreationAmount:         amount.NewCoinAmount(5000000, 0),
+				HyperUnlockRequiredBlocks:   2592000,
+				StakingUnlockRequiredBlocks: 2592000,
+			},
+		); err != nil {
+			return err
+		}
+		if err := fp.InitStakingMap(ctw, []common.Address{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (app *testApp) OnLoadChain(loader types.LoaderWrapper) error {
+	return nil
+}
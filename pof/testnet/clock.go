@@ -0,0 +1,287 @@
+package testnet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// Scheduler is the virtual time of the test network that is shared by clocks of nodes and links of the network
+// Timers and sleeps wait the virtual time, so the virtual time moves only when the harness advances it
+type Scheduler struct {
+	sync.Mutex
+	now      time.Time
+	timers   []*timer
+	seq      uint64
+	activity uint64
+	isClose  bool
+}
+
+// NewScheduler returns a Scheduler that starts from the time
+func NewScheduler(Start time.Time) *Scheduler {
+	return &Scheduler{
+		now: Start,
+	}
+}
+
+// Now returns the current virtual time
+func (s *Scheduler) Now() time.Time {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.now
+}
+
+// NewTimer returns a timer that is expired when the virtual time passes the duration
+func (s *Scheduler) NewTimer(d time.Duration) pof.Timer {
+	t := &timer{
+		s: s,
+		c: make(chan time.Time, 1),
+	}
+	s.Lock()
+	s.schedule(t, d)
+	s.Unlock()
+	return t
+}
+
+// AfterFunc calls the function in its own goroutine when the virtual time passes the duration
+func (s *Scheduler) AfterFunc(d time.Duration, fn func()) {
+	t := &timer{
+		s:  s,
+		fn: fn,
+	}
+	s.Lock()
+	s.schedule(t, d)
+	s.Unlock()
+}
+
+// Sleep pauses the current goroutine until the virtual time passes the duration
+func (s *Scheduler) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-s.NewTimer(d).C()
+}
+
+// Touch records the activity of goroutines that is not related to timers such as the delivery of a packet
+func (s *Scheduler) Touch() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.activity++
+}
+
+// Activity returns the number of changes of timers and touches, the harness waits until it stops changing before advancing
+func (s *Scheduler) Activity() uint64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.activity
+}
+
+// Advance moves the virtual time forward by the duration and fires expired timers in the order of expiry times
+func (s *Scheduler) Advance(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	Target := s.now.Add(d)
+	for {
+		t := s.nextTimer()
+		if t == nil || t.at.After(Target) {
+			break
+		}
+		s.removeTimer(t)
+		if t.at.After(s.now) {
+			s.now = t.at
+		}
+		s.fire(t)
+	}
+	s.now = Target
+}
+
+// Step advances the virtual time to the expiry time of the next timer, but not more than the max duration
+func (s *Scheduler) Step(Max time.Duration) {
+	s.Lock()
+	d := Max
+	if t := s.nextTimer(); t != nil && t.at.Sub(s.now) < d {
+		d = t.at.Sub(s.now)
+	}
+	s.Unlock()
+
+	s.Advance(d)
+}
+
+// Close stops the scheduler, all pending timers are expired immediately and timers that are set later wait the wall time
+// Goroutines that are not stopped with nodes keep waiting the wall time as they did without the scheduler
+func (s *Scheduler) Close() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.isClose = true
+	for _, t := range s.timers {
+		s.fire(t)
+	}
+	s.timers = nil
+}
+
+// schedule should be called with the lock
+func (s *Scheduler) schedule(t *timer, d time.Duration) {
+	s.activity++
+	if d <= 0 {
+		s.fire(t)
+		return
+	}
+	s.seq++
+	t.at = s.now.Add(d)
+	t.seq = s.seq
+	t.isPending = true
+	if s.isClose {
+		seq := t.seq
+		t.wall = time.AfterFunc(d, func() {
+			s.Lock()
+			defer s.Unlock()
+
+			if t.isPending && t.seq == seq {
+				t.wall = nil
+				s.fire(t)
+			}
+		})
+		return
+	}
+	s.timers = append(s.timers, t)
+}
+
+// nextTimer returns the timer that is expired first, timers of the same expiry time are ordered by the scheduled order
+// It should be called with the lock
+func (s *Scheduler) nextTimer() *timer {
+	var next *timer
+	for _, t := range s.timers {
+		if next == nil || t.at.Before(next.at) || (t.at.Equal(next.at) && t.seq < next.seq) {
+			next = t
+		}
+	}
+	return next
+}
+
+// removeTimer should be called with the lock
+func (s *Scheduler) removeTimer(t *timer) bool {
+	if !t.isPending {
+		return false
+	}
+	s.activity++
+	t.isPending = false
+	if t.wall != nil {
+		t.wall.Stop()
+		t.wall = nil
+		return true
+	}
+	for i, v := range s.timers {
+		if v == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// fire should be called with the lock
+func (s *Scheduler) fire(t *timer) {
+	s.activity++
+	t.isPending = false
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+	select {
+	case t.c <- s.now:
+	default:
+	}
+}
+
+type timer struct {
+	s         *Scheduler
+	at        time.Time
+	seq       uint64
+	c         chan time.Time
+	fn        func()
+	wall      *time.Timer
+	isPending bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.s.Lock()
+	defer t.s.Unlock()
+
+	isPending := t.s.removeTimer(t)
+	t.s.schedule(t, d)
+	return isPending
+}
+
+func (t *timer) Stop() bool {
+	t.s.Lock()
+	defer t.s.Unlock()
+
+	return t.s.removeTimer(t)
+}
+
+// Clock is the clock of a node that is shifted from the virtual time of the scheduler by the offset
+// The offset changes the current time only, timers wait durations of the virtual time
+type Clock struct {
+	sync.Mutex
+	s      *Scheduler
+	offset time.Duration
+}
+
+// NewClock returns a Clock of the scheduler
+func NewClock(s *Scheduler) *Clock {
+	return &Clock{
+		s: s,
+	}
+}
+
+// Now returns the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.s.Now().Add(c.offset)
+}
+
+// NewTimer returns a timer of the scheduler
+func (c *Clock) NewTimer(d time.Duration) pof.Timer {
+	return c.s.NewTimer(d)
+}
+
+// Sleep pauses the current goroutine until the virtual time passes the duration
+func (c *Clock) Sleep(d time.Duration) {
+	c.s.Sleep(d)
+}
+
+// Advance moves the clock forward (a negative duration moves it backward)
+func (c *Clock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.offset += d
+}
+
+// SetOffset updates the offset from the virtual time
+func (c *Clock) SetOffset(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.offset = d
+}
+
+// Offset returns the offset from the virtual time
+func (c *Clock) Offset() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.offset
+}
@@ -0,0 +1,46 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHarness_ClockSkew(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 3, 60*time.Second)
+
+	h.Formulators()[0].Clock.Advance(15 * time.Second)
+	if err := h.WaitProgress(6, 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduler_Step(t *testing.T) {
+	Start := time.Unix(1577836800, 0)
+	s := NewScheduler(Start)
+	timer := s.NewTimer(3 * time.Millisecond)
+
+	s.Step(10 * time.Millisecond)
+	if d := s.Now().Sub(Start); d != 3*time.Millisecond {
+		t.Fatal("step does not stop at the next timer", d)
+	}
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("timer is not expired")
+	}
+
+	Activity := s.Activity()
+	s.Step(10 * time.Millisecond)
+	if d := s.Now().Sub(Start); d != 13*time.Millisecond {
+		t.Fatal("step without timers does not advance the max duration", d)
+	}
+	if s.Activity() != Activity {
+		t.Fatal("step without timers changes the activity")
+	}
+}
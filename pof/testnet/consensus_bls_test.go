@@ -0,0 +1,65 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+func TestHarness_BLS(t *testing.T) {
+	cfg := testConfig()
+	cfg.UseBLS = true
+	h, closer := newTestHarness(t, cfg)
+	defer closer()
+
+	checkHeight(t, h, 10, 60*time.Second)
+
+	bh, _, err := h.Observers()[0].Header(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pof.IsBLSVersion(bh.Version) {
+		t.Fatal("header is not the BLS signature version")
+	}
+}
+
+func TestConsensus_BLSKeyProof(t *testing.T) {
+	ObserverKeys := []common.PublicHash{{1}, {2}, {3}}
+	KeyMap := map[common.PublicHash]bls.PublicKey{}
+	ProofMap := map[common.PublicHash]bls.Signature{}
+	for _, pubhash := range ObserverKeys {
+		key, err := bls.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		KeyMap[pubhash] = key.PublicKey()
+		ProofMap[pubhash] = key.ProvePossession()
+	}
+	other, err := bls.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := pof.NewConsensus(10, ObserverKeys)
+	OtherProofMap := map[common.PublicHash]bls.Signature{}
+	for k, v := range ProofMap {
+		OtherProofMap[k] = v
+	}
+	OtherProofMap[ObserverKeys[0]] = other.ProvePossession()
+	if err := cs.SetObserverBLSKeys(KeyMap, OtherProofMap); err != pof.ErrInvalidObserverBLSProof {
+		t.Fatalf("expected %v but %v", pof.ErrInvalidObserverBLSProof, err)
+	}
+	delete(OtherProofMap, ObserverKeys[0])
+	if err := cs.SetObserverBLSKeys(KeyMap, OtherProofMap); err != pof.ErrInvalidObserverBLSProof {
+		t.Fatalf("expected %v but %v", pof.ErrInvalidObserverBLSProof, err)
+	}
+	if _, err := cs.ObserverBLSKey(ObserverKeys[0]); err != pof.ErrNotExistObserverBLSKey {
+		t.Fatalf("key is set without the proof %v", err)
+	}
+	if err := cs.SetObserverBLSKeys(KeyMap, ProofMap); err != nil {
+		t.Fatal(err)
+	}
+}
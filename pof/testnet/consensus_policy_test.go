@@ -0,0 +1,48 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHarness_ConsensusParams(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 5, 60*time.Second)
+
+	for _, nd := range h.Nodes() {
+		cp, err := nd.ConsensusParams()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cp.MaxBlocksPerFormulator != h.config.MaxBlocksPerFormulator {
+			t.Fatal(nd.Name, "invalid max blocks per formulator", cp.MaxBlocksPerFormulator)
+		}
+		if cp.ObserverQuorum != 3 {
+			t.Fatal(nd.Name, "invalid observer quorum", cp.ObserverQuorum)
+		}
+		if cp.Pending != nil {
+			t.Fatal(nd.Name, "pending policy exists")
+		}
+	}
+
+	// the saved policy is loaded when the node restarts
+	nd := h.Observers()[0]
+	if err := h.Crash(nd); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Restart(nd); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := nd.ConsensusParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.MaxBlocksPerFormulator != h.config.MaxBlocksPerFormulator || cp.ObserverQuorum != 3 {
+		t.Fatal("invalid policy after restart", cp.MaxBlocksPerFormulator, cp.ObserverQuorum)
+	}
+	if err := h.WaitProgress(5, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
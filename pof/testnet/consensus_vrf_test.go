@@ -0,0 +1,133 @@
+package testnet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+	"github.com/fletaio/fleta_testnet/pof/light"
+)
+
+func TestHarness_VRF(t *testing.T) {
+	cfg := testConfig()
+	cfg.UseBLS = true
+	cfg.UseVRF = true
+	h, closer := newTestHarness(t, cfg)
+	defer closer()
+
+	checkHeight(t, h, 10, 60*time.Second)
+
+	ob := h.Observers()[0]
+	ob.Lock()
+	blocks := []*types.Block{}
+	for i := uint32(1); i <= 10; i++ {
+		b, err := ob.cn.Provider().Block(i)
+		if err != nil {
+			ob.Unlock()
+			t.Fatal(err)
+		}
+		if !pof.IsVRFVersion(b.Header.Version) {
+			ob.Unlock()
+			t.Fatal("header is not the VRF version")
+		}
+		blocks = append(blocks, b)
+	}
+	ob.Unlock()
+
+	// the light client follows the rank table reshuffled by VRF outputs
+	cs := pof.NewConsensus(h.config.MaxBlocksPerFormulator, h.observerKeys)
+	if err := cs.SetObserverBLSKeys(h.observerBLSKeys, h.observerBLSProofs); err != nil {
+		t.Fatal(err)
+	}
+	ls := light.NewServer(cs, 100)
+	cn, err := h.openChain(&Node{Name: "light"}, cs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cn.Close()
+
+	_, SaveData, err := ls.RankState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := pof.NewRankTracker(SaveData, h.observerKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range blocks {
+		if err := cn.ConnectBlock(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	GenesisHash, err := cn.Provider().Hash(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc := light.NewClient(ChainID, cn.Provider().Name(), h.observerKeys, 0, GenesisHash, 0)
+	if err := lc.SetObserverBLSKeys(h.observerBLSKeys, h.observerBLSProofs); err != nil {
+		t.Fatal(err)
+	}
+	lc.SetRankTracker(tr.Clone())
+	items, err := ls.Headers(1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items {
+		pf, err := ls.AccountProof(item.Header.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := lc.VerifyHeader(item.Header, item.Signatures, pf); err != nil {
+			t.Fatal(item.Header.Height, err)
+		}
+	}
+
+	// the generator cannot replace the VRF output even if it signs the header again
+	bh := blocks[0].Header
+	var generator *Node
+	for _, nd := range h.Formulators() {
+		if nd.address == bh.Generator {
+			generator = nd
+		}
+	}
+	if generator == nil {
+		t.Fatal("generator is not found")
+	}
+	dec := encoding.NewDecoder(bytes.NewReader(bh.ConsensusData))
+	TimeoutCount, err := dec.DecodeUint32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var Output hash.Hash256
+	if err := dec.Decode(&Output); err != nil {
+		t.Fatal(err)
+	}
+	Proof, err := dec.DecodeBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Output[0]++
+	var buffer bytes.Buffer
+	enc := encoding.NewEncoder(&buffer)
+	if err := enc.EncodeUint32(TimeoutCount); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(Output); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EncodeBytes(Proof); err != nil {
+		t.Fatal(err)
+	}
+	bh.ConsensusData = buffer.Bytes()
+	sig, err := generator.key.Sign(encoding.Hash(bh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.ValidateGenerator(&bh, sig); err != pof.ErrInvalidVRFOutput {
+		t.Fatal("replaced vrf output is accepted", err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package testnet
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the cpu time that is used by all goroutines of the process
+func processCPUTime() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
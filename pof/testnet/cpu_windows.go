@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package testnet
+
+import (
+	"time"
+)
+
+// processCPUTime is not supported, so the harness detects the idle state by the activity of the scheduler only when the runtime does not provide counts of goroutines
+func processCPUTime() time.Duration {
+	return 0
+}
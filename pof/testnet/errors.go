@@ -0,0 +1,17 @@
+package testnet
+
+import "errors"
+
+// errors
+var (
+	ErrConnectionRefused = errors.New("connection refused")
+	ErrAlreadyListening  = errors.New("already listening")
+	ErrClosedConnection  = errors.New("closed connection")
+	ErrClosedListener    = errors.New("closed listener")
+	ErrNotExistNode      = errors.New("not exist node")
+	ErrNodeNotRunning    = errors.New("node not running")
+	ErrNodeRunning       = errors.New("node running")
+	ErrHeightTimeout     = errors.New("height timeout")
+	ErrNoProgress        = errors.New("no progress")
+	ErrInconsistentChain = errors.New("inconsistent chain")
+)
@@ -0,0 +1,44 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHarness_Failover(t *testing.T) {
+	cfg := testConfig()
+	cfg.UseStandby = true
+	h, closer := newTestHarness(t, cfg)
+	defer closer()
+
+	checkHeight(t, h, 5, 60*time.Second)
+
+	var active *Node
+	var standby *Node
+	ActiveCount := 0
+	for _, nd := range h.Formulators() {
+		if nd.address != h.Formulators()[0].address {
+			continue
+		}
+		if nd.IsActive() {
+			active = nd
+			ActiveCount++
+		} else {
+			standby = nd
+		}
+	}
+	if ActiveCount != 1 || standby == nil {
+		t.Fatal("invalid active count", ActiveCount)
+	}
+
+	h.Crash(active)
+	if err := h.WaitProgress(10, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !standby.IsActive() {
+		t.Fatal("standby is not activated")
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,650 @@
+package testnet
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/bls"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	_ "github.com/fletaio/fleta_testnet/core/backend/leveldb_driver"
+	"github.com/fletaio/fleta_testnet/core/chain"
+	"github.com/fletaio/fleta_testnet/core/pile"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/formulator"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+// ChainID is the chain id of the test network
+const ChainID = uint8(0x01)
+
+// LeaseTTL is the TTL of leases of formulators that have standby instances
+const LeaseTTL = 2 * time.Second
+
+// the harness advances the virtual time to the next timer (at most SchedulerStep) whenever goroutines of nodes are idle
+// goroutines are idle when the scheduler has no activity for IdleChecks checks and no goroutine is running or runnable,
+// the process that uses less than IdleCPURatio of IdleWindow is idle when the runtime does not provide counts of goroutines
+const (
+	SchedulerStep = 10 * time.Millisecond
+	IdleChecks    = 3
+	IdleWindow    = 200 * time.Microsecond
+	IdleCPURatio  = 0.2
+)
+
+// Backend is the store backend of nodes, it closes without waiting the wall time
+const Backend = "leveldb"
+
+// node types
+const (
+	ObserverNodeType   = NodeType(1)
+	FormulatorNodeType = NodeType(2)
+)
+
+// NodeType is the type of the node in the test network
+type NodeType uint8
+
+// Config defines configuration of the test network
+type Config struct {
+	ObserverCount          int
+	FormulatorCount        int
+	MaxBlocksPerFormulator uint32
+	Seed                   int64
+	Path                   string
+	UseBLS                 bool // observers sign blocks by aggregated BLS signatures
+	UseStandby             bool // each formulator has a standby instance that shares the lease
+	UseVRF                 bool // generators include VRF outputs, it requires UseBLS because VRFVersion is the later version
+}
+
+// Node is an observer or a formulator of the test network
+type Node struct {
+	sync.Mutex
+	Name      string
+	Type      NodeType
+	Clock     *Clock
+	key       key.Key
+	blsKey    *bls.PrivateKey
+	ndkey     key.Key
+	address   common.Address
+	leasePath string
+	signDB    backend.StoreBackend
+	roundDB   backend.StoreBackend
+	sp        *pof.SignProtection
+	cn        *chain.Chain
+	cs        *pof.Consensus
+	ob        *pof.ObserverNode
+	fr        *pof.FormulatorNode
+	isRunning bool
+}
+
+// IsRunning returns true when the node is running
+func (nd *Node) IsRunning() bool {
+	nd.Lock()
+	defer nd.Unlock()
+
+	return nd.isRunning
+}
+
+// Height returns the height of the chain of the node (it returns zero when the node is not running)
+func (nd *Node) Height() uint32 {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning {
+		return 0
+	}
+	return nd.cn.Provider().Height()
+}
+
+// IsActive returns true when the formulator node generates blocks by holding the lease
+func (nd *Node) IsActive() bool {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning || nd.fr == nil {
+		return false
+	}
+	return nd.fr.IsActive()
+}
+
+// RoundState returns the state of the current vote round of the observer node
+func (nd *Node) RoundState() int {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning || nd.ob == nil {
+		return pof.EmptyState
+	}
+	return nd.ob.RoundState()
+}
+
+// Evidences returns evidences of conflicting votes that are detected by the observer node
+func (nd *Node) Evidences() ([]*pof.ObserverEvidence, error) {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning || nd.ob == nil {
+		return nil, ErrNodeNotRunning
+	}
+	return nd.ob.Evidences()
+}
+
+// ConsensusParams returns the consensus policy of the node
+func (nd *Node) ConsensusParams() (*pof.ConsensusParams, error) {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning {
+		return nil, ErrNodeNotRunning
+	}
+	return nd.cs.Params()
+}
+
+// SignProtection returns the record of headers signed by the formulator node
+func (nd *Node) SignProtection() *pof.SignProtection {
+	nd.Lock()
+	defer nd.Unlock()
+
+	return nd.sp
+}
+
+// Header returns the header of the height and the hash of it
+func (nd *Node) Header(Height uint32) (*types.Header, hash.Hash256, error) {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning {
+		return nil, hash.Hash256{}, ErrNodeNotRunning
+	}
+	bh, err := nd.cn.Provider().Header(Height)
+	if err != nil {
+		return nil, hash.Hash256{}, err
+	}
+	return bh, encoding.Hash(bh), nil
+}
+
+// Harness runs observers and formulators in the process over the in-memory network
+// Nodes and the network wait the virtual time of the scheduler, so timeouts are independent of the load of the host
+type Harness struct {
+	sync.Mutex
+	Scheduler         *Scheduler
+	Network           *Network
+	config            *Config
+	observers         []*Node
+	formulators       []*Node
+	nodeMap           map[string]*Node
+	observerKeys      []common.PublicHash
+	observerBLSKeys   map[common.PublicHash]bls.PublicKey
+	observerBLSProofs map[common.PublicHash]bls.Signature
+	obNetAddressMap   map[common.PublicHash]string
+	frNetAddressMap   map[common.PublicHash]string
+	genFormulators    []*genesisFormulator
+	closeCh           chan struct{}
+	stepCh            chan struct{}
+}
+
+// NewHarness returns a Harness that keeps data of nodes under the path of the config
+func NewHarness(Config *Config) (*Harness, error) {
+	if Config.MaxBlocksPerFormulator == 0 {
+		Config.MaxBlocksPerFormulator = 10
+	}
+	// the virtual time starts from the fixed time to make timestamps of blocks same for the seed
+	scheduler := NewScheduler(time.Unix(1577836800, 0))
+	h := &Harness{
+		Scheduler:         scheduler,
+		Network:           NewNetwork(Config.Seed, scheduler),
+		config:            Config,
+		nodeMap:           map[string]*Node{},
+		observerBLSKeys:   map[common.PublicHash]bls.PublicKey{},
+		observerBLSProofs: map[common.PublicHash]bls.Signature{},
+		obNetAddressMap:   map[common.PublicHash]string{},
+		frNetAddressMap:   map[common.PublicHash]string{},
+		stepCh:            make(chan struct{}),
+	}
+	for i := 0; i < Config.ObserverCount; i++ {
+		Key, err := h.newKey("observer", i)
+		if err != nil {
+			return nil, err
+		}
+		nd := &Node{
+			Name:  "ob" + strconv.Itoa(i),
+			Type:  ObserverNodeType,
+			Clock: NewClock(scheduler),
+			key:   Key,
+		}
+		pubhash := common.NewPublicHash(Key.PublicKey())
+		h.observerKeys = append(h.observerKeys, pubhash)
+		if Config.UseBLS {
+			BLSKey, err := h.newBLSKey(i)
+			if err != nil {
+				return nil, err
+			}
+			nd.blsKey = BLSKey
+			h.observerBLSKeys[pubhash] = BLSKey.PublicKey()
+			h.observerBLSProofs[pubhash] = BLSKey.ProvePossession()
+		}
+		h.obNetAddressMap[pubhash] = nd.Name + ":observer"
+		h.frNetAddressMap[pubhash] = nd.Name + ":formulator"
+		h.observers = append(h.observers, nd)
+		h.nodeMap[nd.Name] = nd
+	}
+	for i := 0; i < Config.FormulatorCount; i++ {
+		Key, err := h.newKey("formulator", i)
+		if err != nil {
+			return nil, err
+		}
+		NdKey, err := h.newKey("node", i)
+		if err != nil {
+			return nil, err
+		}
+		nd := &Node{
+			Name:    "fr" + strconv.Itoa(i),
+			Type:    FormulatorNodeType,
+			Clock:   NewClock(scheduler),
+			key:     Key,
+			ndkey:   NdKey,
+			address: common.NewAddress(0, uint16(i+1), 0),
+		}
+		pubhash := common.NewPublicHash(Key.PublicKey())
+		h.genFormulators = append(h.genFormulators, &genesisFormulator{
+			Address: nd.address,
+			KeyHash: pubhash,
+			GenHash: pubhash,
+		})
+		h.formulators = append(h.formulators, nd)
+		h.nodeMap[nd.Name] = nd
+
+		if Config.UseStandby {
+			nd.leasePath = filepath.Join(Config.Path, nd.Name+".lease")
+			StandbyNdKey, err := h.newKey("standby", i)
+			if err != nil {
+				return nil, err
+			}
+			sb := &Node{
+				Name:      nd.Name + "sb",
+				Type:      FormulatorNodeType,
+				Clock:     NewClock(scheduler),
+				key:       Key,
+				ndkey:     StandbyNdKey,
+				address:   nd.address,
+				leasePath: nd.leasePath,
+			}
+			h.formulators = append(h.formulators, sb)
+			h.nodeMap[sb.Name] = sb
+		}
+	}
+	return h, nil
+}
+
+func (h *Harness) newKey(prefix string, index int) (key.Key, error) {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, uint64(h.config.Seed))
+	sum := hash.DoubleHash(append(bs, []byte(prefix+strconv.Itoa(index))...))
+	return key.NewMemoryKeyFromBytes(sum[:])
+}
+
+func (h *Harness) newBLSKey(index int) (*bls.PrivateKey, error) {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, uint64(h.config.Seed))
+	sum := hash.DoubleHash(append(bs, []byte("bls"+strconv.Itoa(index))...))
+	return bls.NewPrivateKeyFromBytes(sum[:16])
+}
+
+// Observers returns observers of the test network
+func (h *Harness) Observers() []*Node {
+	return h.observers
+}
+
+// Formulators returns formulators of the test network
+func (h *Harness) Formulators() []*Node {
+	return h.formulators
+}
+
+// Nodes returns all nodes of the test network
+func (h *Harness) Nodes() []*Node {
+	list := []*Node{}
+	list = append(list, h.observers...)
+	list = append(list, h.formulators...)
+	return list
+}
+
+// Node returns the node of the name
+func (h *Harness) Node(Name string) (*Node, error) {
+	nd, has := h.nodeMap[Name]
+	if !has {
+		return nil, ErrNotExistNode
+	}
+	return nd, nil
+}
+
+// Start runs all nodes and advances the virtual time
+func (h *Harness) Start() error {
+	for _, nd := range h.Nodes() {
+		if err := h.Restart(nd); err != nil {
+			return err
+		}
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if h.closeCh == nil {
+		h.closeCh = make(chan struct{})
+		go h.runScheduler(h.closeCh)
+	}
+	return nil
+}
+
+func (h *Harness) runScheduler(closeCh chan struct{}) {
+	for h.waitIdle(closeCh) {
+		h.Scheduler.Step(SchedulerStep)
+		h.notifyStep()
+	}
+	// waiters check the condition for the last time
+	h.notifyStep()
+}
+
+func (h *Harness) notifyStep() {
+	h.Lock()
+	defer h.Unlock()
+
+	close(h.stepCh)
+	h.stepCh = make(chan struct{})
+}
+
+// waitIdle waits until goroutines of the process are blocked, it yields the cpu to them and does not wait the wall time for timeouts
+// It returns false when the harness is closed
+func (h *Harness) waitIdle(closeCh chan struct{}) bool {
+	Count := 0
+	Activity := h.Scheduler.Activity()
+	for {
+		select {
+		case <-closeCh:
+			return false
+		default:
+		}
+		isIdle, ok := isSchedulerIdle()
+		if !ok {
+			isIdle = isCPUIdle()
+		}
+		if a := h.Scheduler.Activity(); !isIdle || a != Activity {
+			Activity = a
+			Count = 0
+		} else if Count++; Count >= IdleChecks {
+			return true
+		}
+		runtime.Gosched()
+	}
+}
+
+// isCPUIdle returns true when the process uses less than IdleCPURatio of IdleWindow of the wall time
+func isCPUIdle() bool {
+	Begin := time.Now()
+	Used := processCPUTime()
+	time.Sleep(IdleWindow)
+	return float64(processCPUTime()-Used) < float64(time.Since(Begin))*IdleCPURatio
+}
+
+// Close terminates all running nodes and stops the virtual time
+func (h *Harness) Close() {
+	// nodes are closed at once while the virtual time keeps moving, because goroutines of nodes can sleep with their locks
+	var wg sync.WaitGroup
+	for _, nd := range h.Nodes() {
+		wg.Add(1)
+		go func(nd *Node) {
+			defer wg.Done()
+
+			if nd.IsRunning() {
+				h.Crash(nd)
+			}
+		}(nd)
+	}
+	wg.Wait()
+
+	h.Lock()
+	if h.closeCh != nil {
+		close(h.closeCh)
+		h.closeCh = nil
+	}
+	h.Unlock()
+
+	// goroutines of terminated nodes that wait timers are released
+	h.Scheduler.Close()
+}
+
+// Crash terminates the node without any notice to others
+func (h *Harness) Crash(nd *Node) error {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if !nd.isRunning {
+		return ErrNodeNotRunning
+	}
+	switch nd.Type {
+	case ObserverNodeType:
+		nd.ob.Close()
+		nd.roundDB.Close()
+	case FormulatorNodeType:
+		nd.fr.Close()
+		nd.signDB.Close()
+	}
+	h.Network.Disconnect(nd.Name)
+	nd.isRunning = false
+	return nil
+}
+
+// Restart runs the node from the data of the previous run
+func (h *Harness) Restart(nd *Node) error {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if nd.isRunning {
+		return ErrNodeRunning
+	}
+	cs := pof.NewConsensus(h.config.MaxBlocksPerFormulator, h.observerKeys)
+	if h.config.UseBLS {
+		if err := cs.SetObserverBLSKeys(h.observerBLSKeys, h.observerBLSProofs); err != nil {
+			return err
+		}
+	}
+	cn, err := h.openChain(nd, cs)
+	if err != nil {
+		return err
+	}
+	h.Network.Reconnect(nd.Name)
+
+	switch nd.Type {
+	case ObserverNodeType:
+		ob := pof.NewObserverNode(nd.key, h.obNetAddressMap, cs)
+		ob.SetClock(nd.Clock)
+		if nd.blsKey != nil {
+			ob.SetBLSKey(nd.blsKey)
+		}
+		ob.SetTransport(h.Network.Transport(nd.Name))
+		roundDB, err := backend.Create(Backend, filepath.Join(h.config.Path, nd.Name, "round"))
+		if err != nil {
+			cn.Close()
+			return err
+		}
+		if err := ob.SetRoundStore(roundDB); err != nil {
+			roundDB.Close()
+			cn.Close()
+			return err
+		}
+		if err := ob.Init(); err != nil {
+			roundDB.Close()
+			cn.Close()
+			return err
+		}
+		nd.ob = ob
+		nd.roundDB = roundDB
+		go ob.Run(nd.Name+":observer", nd.Name+":formulator")
+	case FormulatorNodeType:
+		fr := pof.NewFormulatorNode(&pof.FormulatorConfig{
+			Formulator:              nd.address,
+			MaxTransactionsPerBlock: 10000,
+		}, nd.key, nd.ndkey, h.frNetAddressMap, map[common.PublicHash]string{}, cs, filepath.Join(h.config.Path, nd.Name, "peer"))
+		fr.SetClock(nd.Clock)
+		fr.SetTransport(h.Network.Transport(nd.Name))
+		if len(nd.leasePath) > 0 {
+			lease := pof.NewFileLease(nd.leasePath, nd.Name, LeaseTTL)
+			lease.SetClock(nd.Clock)
+			fr.SetLease(lease, LeaseTTL/4)
+		}
+		signDB, err := backend.Create(Backend, filepath.Join(h.config.Path, nd.Name, "signprotect"))
+		if err != nil {
+			cn.Close()
+			return err
+		}
+		sp := pof.NewSignProtection(signDB)
+		fr.SetSignProtection(sp)
+		if err := fr.Init(); err != nil {
+			signDB.Close()
+			cn.Close()
+			return err
+		}
+		nd.fr = fr
+		nd.signDB = signDB
+		nd.sp = sp
+		go fr.Run(nd.Name + ":node")
+	}
+	nd.cn = cn
+	nd.cs = cs
+	nd.isRunning = true
+	return nil
+}
+
+func (h *Harness) openChain(nd *Node, cs *pof.Consensus, services ...types.Service) (*chain.Chain, error) {
+	back, err := backend.Create(Backend, filepath.Join(h.config.Path, nd.Name, "context"))
+	if err != nil {
+		return nil, err
+	}
+	cdb, err := pile.Open(filepath.Join(h.config.Path, nd.Name, "chain"))
+	if err != nil {
+		back.Close()
+		return nil, err
+	}
+	cdb.SetSyncMode(true)
+	Version := uint16(0x0001)
+	if h.config.UseBLS {
+		Version = pof.BLSSignatureVersion
+	}
+	if h.config.UseVRF {
+		Version = pof.VRFVersion
+	}
+	st, err := chain.NewStore(back, cdb, ChainID, "FLETA Testnet Harness", Version)
+	if err != nil {
+		back.Close()
+		cdb.Close()
+		return nil, err
+	}
+
+	cn := chain.NewChain(cs, &testApp{formulators: h.genFormulators}, st)
+	cn.MustAddProcess(admin.NewAdmin(1))
+	cn.MustAddProcess(vault.NewVault(2))
+	cn.MustAddProcess(formulator.NewFormulator(3))
+	for _, s := range services {
+		cn.MustAddService(s)
+	}
+	if err := cn.Init(); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// WaitUntil waits until the condition is satisfied, the condition is checked for each step of the virtual time
+// The timeout is the duration of the virtual time, so it is independent of the load of the host
+func (h *Harness) WaitUntil(cond func() bool, timeout time.Duration) bool {
+	deadline := h.Scheduler.Now().Add(timeout)
+	for {
+		h.Lock()
+		stepCh := h.stepCh
+		isRunning := h.closeCh != nil
+		h.Unlock()
+
+		if cond() {
+			return true
+		}
+		if !isRunning || !h.Scheduler.Now().Before(deadline) {
+			return false
+		}
+		<-stepCh
+	}
+}
+
+// WaitHeight waits until all running observers reach the height
+func (h *Harness) WaitHeight(Height uint32, timeout time.Duration) error {
+	if !h.WaitUntil(func() bool {
+		for _, nd := range h.observers {
+			if nd.IsRunning() && nd.Height() < Height {
+				return false
+			}
+		}
+		return true
+	}, timeout) {
+		return ErrHeightTimeout
+	}
+	return nil
+}
+
+// WaitProgress waits until the highest running observer connects the number of blocks
+func (h *Harness) WaitProgress(Blocks uint32, timeout time.Duration) error {
+	Target := h.highestHeight() + Blocks
+	if !h.WaitUntil(func() bool {
+		return h.highestHeight() >= Target
+	}, timeout) {
+		return ErrNoProgress
+	}
+	return nil
+}
+
+func (h *Harness) highestHeight() uint32 {
+	var Highest uint32
+	for _, nd := range h.observers {
+		if Height := nd.Height(); Height > Highest {
+			Highest = Height
+		}
+	}
+	return Highest
+}
+
+// CheckConsistency checks that running nodes have the same block hash and the same context hash at every common height
+func (h *Harness) CheckConsistency() error {
+	nodes := []*Node{}
+	var Lowest uint32
+	for _, nd := range h.Nodes() {
+		if nd.IsRunning() {
+			Height := nd.Height()
+			if len(nodes) == 0 || Height < Lowest {
+				Lowest = Height
+			}
+			nodes = append(nodes, nd)
+		}
+	}
+	for Height := uint32(1); Height <= Lowest; Height++ {
+		var base *types.Header
+		var baseHash hash.Hash256
+		for _, nd := range nodes {
+			bh, HeaderHash, err := nd.Header(Height)
+			if err != nil {
+				return err
+			}
+			if base == nil {
+				base = bh
+				baseHash = HeaderHash
+			} else if baseHash != HeaderHash || base.ContextHash != bh.ContextHash {
+				return ErrInconsistentChain
+			}
+		}
+	}
+	return nil
+}
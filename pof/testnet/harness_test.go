@@ -0,0 +1,81 @@
+package testnet
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// testConfig returns the network config of 5 observers and 2 formulators, tests change fields for their features
+func testConfig() *Config {
+	return &Config{
+		ObserverCount:          5,
+		FormulatorCount:        2,
+		MaxBlocksPerFormulator: 3,
+		Seed:                   1,
+	}
+}
+
+func newTestHarness(t *testing.T, cfg *Config) (*Harness, func()) {
+	if testing.Short() {
+		t.Skip("skipping the consensus network in short mode")
+	}
+	path, err := ioutil.TempDir("", "testnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Path = path
+	h, err := NewHarness(cfg)
+	if err != nil {
+		os.RemoveAll(path)
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		h.Close()
+		os.RemoveAll(path)
+		t.Fatal(err)
+	}
+	return h, func() {
+		h.Close()
+		os.RemoveAll(path)
+	}
+}
+
+func checkHeight(t *testing.T, h *Harness, Height uint32, timeout time.Duration) {
+	if err := h.WaitHeight(Height, timeout); err != nil {
+		for _, nd := range h.Nodes() {
+			t.Log(nd.Name, nd.IsRunning(), nd.Height())
+		}
+		t.Fatal(err)
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHarness_Basic(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 10, 60*time.Second)
+}
+
+func TestHarness_CrashRestart(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 3, 60*time.Second)
+
+	ob := h.Observers()[4]
+	if err := h.Crash(ob); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.WaitProgress(3, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Restart(ob); err != nil {
+		t.Fatal(err)
+	}
+	checkHeight(t, h, h.highestHeight()+1, 60*time.Second)
+}
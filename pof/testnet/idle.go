@@ -0,0 +1,25 @@
+//go:build go1.16
+// +build go1.16
+
+package testnet
+
+import (
+	"runtime/metrics"
+)
+
+// isSchedulerIdle returns true when no goroutine is running or runnable except for the caller
+// It returns false as the second value when the runtime does not provide counts of goroutines by states
+func isSchedulerIdle() (bool, bool) {
+	samples := []metrics.Sample{
+		{Name: "/sched/goroutines/running:goroutines"},
+		{Name: "/sched/goroutines/runnable:goroutines"},
+		{Name: "/sched/goroutines/not-in-go:goroutines"},
+	}
+	metrics.Read(samples)
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			return false, false
+		}
+	}
+	return samples[0].Value.Uint64() <= 1 && samples[1].Value.Uint64() == 0 && samples[2].Value.Uint64() == 0, true
+}
@@ -0,0 +1,9 @@
+//go:build !go1.16
+// +build !go1.16
+
+package testnet
+
+// isSchedulerIdle is not supported, so the harness detects the idle state by the cpu time of the process
+func isSchedulerIdle() (bool, bool) {
+	return false, false
+}
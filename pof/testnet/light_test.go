@@ -0,0 +1,86 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/pof"
+	"github.com/fletaio/fleta_testnet/pof/light"
+)
+
+func TestHarness_LightClient(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 10, 60*time.Second)
+
+	ob := h.Observers()[0]
+	ob.Lock()
+	blocks := []*types.Block{}
+	for i := uint32(1); i <= 10; i++ {
+		b, err := ob.cn.Provider().Block(i)
+		if err != nil {
+			ob.Unlock()
+			t.Fatal(err)
+		}
+		blocks = append(blocks, b)
+	}
+	ob.Unlock()
+
+	// replays blocks on the new chain to collect account proofs from the genesis
+	cs := pof.NewConsensus(h.config.MaxBlocksPerFormulator, h.observerKeys)
+	ls := light.NewServer(cs, 100)
+	cn, err := h.openChain(&Node{Name: "light"}, cs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cn.Close()
+
+	GenesisHeight, SaveData, err := ls.RankState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GenesisHeight != 0 {
+		t.Fatal("rank state is not the genesis state")
+	}
+	tr, err := pof.NewRankTracker(SaveData, h.observerKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range blocks {
+		if err := cn.ConnectBlock(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	GenesisHash, err := cn.Provider().Hash(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc := light.NewClient(ChainID, cn.Provider().Name(), h.observerKeys, 0, GenesisHash, 0)
+	lc.SetRankTracker(tr)
+	items, err := ls.Headers(1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items {
+		pf, err := ls.AccountProof(item.Header.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := lc.VerifyHeader(item.Header, item.Signatures, pf); err != nil {
+			t.Fatal(item.Header.Height, err)
+		}
+	}
+	if lc.LastHash() != cn.Provider().LastHash() {
+		t.Fatal("last hash mismatch")
+	}
+
+	bad := *blocks[0]
+	lc = light.NewClient(ChainID, cn.Provider().Name(), h.observerKeys, 0, GenesisHash, 0)
+	bad.Header.Timestamp++
+	if err := lc.VerifyHeader(&bad.Header, bad.Signatures, nil); err == nil {
+		t.Fatal("modified header is verified")
+	}
+}
@@ -0,0 +1,505 @@
+package testnet
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/service/p2p"
+)
+
+// Fault defines faults that are injected to packets of a link
+type Fault struct {
+	DropRate     float64       // probability of dropping a packet
+	MinDelay     time.Duration // minimum delivery delay of a packet
+	MaxDelay     time.Duration // maximum delivery delay of a packet
+	ReorderRate  float64       // probability of holding a packet so that following packets overtake it
+	ReorderDelay time.Duration // additional delay of the held packet
+}
+
+type linkKey struct {
+	From string
+	To   string
+}
+
+// Network is an in-memory network that connects nodes without sockets
+// Faults are applied to protocol packets only, handshakes and pings are always delivered
+type Network struct {
+	sync.Mutex
+	rand        *rand.Rand
+	scheduler   *Scheduler
+	listenerMap map[string]*listener
+	connMap     map[*conn]bool
+	groupMap    map[string]int
+	downMap     map[string]bool
+	fault       Fault
+	linkFaults  map[linkKey]Fault
+}
+
+// NewNetwork returns a Network that draws fault decisions from the seed and delays packets by the virtual time of the scheduler
+func NewNetwork(Seed int64, scheduler *Scheduler) *Network {
+	return &Network{
+		rand:        rand.New(rand.NewSource(Seed)),
+		scheduler:   scheduler,
+		listenerMap: map[string]*listener{},
+		connMap:     map[*conn]bool{},
+		groupMap:    map[string]int{},
+		downMap:     map[string]bool{},
+		linkFaults:  map[linkKey]Fault{},
+	}
+}
+
+// Transport returns the transport of the node
+func (n *Network) Transport(Name string) p2p.Transport {
+	return &transport{
+		n:    n,
+		name: Name,
+	}
+}
+
+// SetFault sets the fault of all links
+func (n *Network) SetFault(f Fault) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.fault = f
+}
+
+// SetLinkFault sets the fault of the link that overrides the fault of all links
+func (n *Network) SetLinkFault(From string, To string, f Fault) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.linkFaults[linkKey{From: From, To: To}] = f
+}
+
+// ClearFaults removes all faults
+func (n *Network) ClearFaults() {
+	n.Lock()
+	defer n.Unlock()
+
+	n.fault = Fault{}
+	n.linkFaults = map[linkKey]Fault{}
+}
+
+// Partition splits nodes into the groups, nodes in different groups cannot communicate
+// Nodes that are not in the groups are gathered into one more group
+func (n *Network) Partition(groups ...[]string) {
+	n.Lock()
+	n.groupMap = map[string]int{}
+	for i, group := range groups {
+		for _, Name := range group {
+			n.groupMap[Name] = i + 1
+		}
+	}
+	n.Unlock()
+
+	n.closeConns(func(c *conn) bool {
+		return !n.reachable(c.local, c.remote)
+	})
+}
+
+// Heal removes the partition
+func (n *Network) Heal() {
+	n.Lock()
+	defer n.Unlock()
+
+	n.groupMap = map[string]int{}
+}
+
+// Disconnect closes all connections and listeners of the node and refuses new ones
+func (n *Network) Disconnect(Name string) {
+	n.Lock()
+	n.downMap[Name] = true
+	lstns := []*listener{}
+	for _, l := range n.listenerMap {
+		if l.owner == Name {
+			lstns = append(lstns, l)
+		}
+	}
+	n.Unlock()
+
+	for _, l := range lstns {
+		l.Close()
+	}
+	n.closeConns(func(c *conn) bool {
+		return c.local == Name || c.remote == Name
+	})
+}
+
+// Reconnect allows connections of the node again
+func (n *Network) Reconnect(Name string) {
+	n.Lock()
+	defer n.Unlock()
+
+	delete(n.downMap, Name)
+}
+
+func (n *Network) isReachable(From string, To string) bool {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.reachable(From, To)
+}
+
+// reachable should be called with the lock
+func (n *Network) reachable(From string, To string) bool {
+	if n.downMap[From] || n.downMap[To] {
+		return false
+	}
+	return n.groupMap[From] == n.groupMap[To]
+}
+
+// closeConns closes connections that are selected by fn (fn is called with the lock)
+func (n *Network) closeConns(fn func(c *conn) bool) {
+	n.Lock()
+	conns := []*conn{}
+	for c := range n.connMap {
+		if fn(c) {
+			conns = append(conns, c)
+		}
+	}
+	n.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+// route decides whether the packet is dropped and how long it is delayed
+func (n *Network) route(From string, To string) (bool, time.Duration, bool) {
+	n.Lock()
+	defer n.Unlock()
+
+	f, has := n.linkFaults[linkKey{From: From, To: To}]
+	if !has {
+		f = n.fault
+	}
+	if f.DropRate > 0 && n.rand.Float64() < f.DropRate {
+		return true, 0, false
+	}
+	delay := f.MinDelay
+	if f.MaxDelay > f.MinDelay {
+		delay += time.Duration(n.rand.Int63n(int64(f.MaxDelay - f.MinDelay)))
+	}
+	if f.ReorderRate > 0 && n.rand.Float64() < f.ReorderRate {
+		return false, delay + f.ReorderDelay, true
+	}
+	return false, delay, false
+}
+
+func (n *Network) listen(Owner string, Address string) (net.Listener, error) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.downMap[Owner] {
+		return nil, ErrConnectionRefused
+	}
+	if _, has := n.listenerMap[Address]; has {
+		return nil, ErrAlreadyListening
+	}
+	l := &listener{
+		n:       n,
+		owner:   Owner,
+		address: Address,
+		connCh:  make(chan net.Conn, 64),
+		closeCh: make(chan struct{}),
+	}
+	n.listenerMap[Address] = l
+	return l, nil
+}
+
+func (n *Network) dial(From string, Address string, timeout time.Duration) (net.Conn, error) {
+	n.Lock()
+	l, has := n.listenerMap[Address]
+	n.Unlock()
+	if !has {
+		return nil, ErrConnectionRefused
+	}
+	if !n.isReachable(From, l.owner) {
+		return nil, ErrConnectionRefused
+	}
+
+	up := newPipe(n.scheduler)
+	down := newPipe(n.scheduler)
+	client := &conn{
+		n:      n,
+		local:  From,
+		remote: l.owner,
+		laddr:  addr(From),
+		raddr:  addr(Address),
+		in:     down,
+		out:    up,
+	}
+	server := &conn{
+		n:      n,
+		local:  l.owner,
+		remote: From,
+		laddr:  addr(Address),
+		raddr:  addr(From),
+		in:     up,
+		out:    down,
+	}
+	n.Lock()
+	n.connMap[client] = true
+	n.connMap[server] = true
+	n.Unlock()
+
+	timer := n.scheduler.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case l.connCh <- server:
+		n.scheduler.Touch()
+		return client, nil
+	case <-l.closeCh:
+	case <-timer.C():
+	}
+	client.Close()
+	server.Close()
+	return nil, ErrConnectionRefused
+}
+
+type transport struct {
+	n    *Network
+	name string
+}
+
+func (tr *transport) Listen(BindAddress string) (net.Listener, error) {
+	return tr.n.listen(tr.name, BindAddress)
+}
+
+func (tr *transport) DialTimeout(Address string, timeout time.Duration) (net.Conn, error) {
+	return tr.n.dial(tr.name, Address, timeout)
+}
+
+type addr string
+
+func (a addr) Network() string {
+	return "memory"
+}
+
+func (a addr) String() string {
+	return string(a)
+}
+
+type listener struct {
+	n         *Network
+	owner     string
+	address   string
+	connCh    chan net.Conn
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.connCh:
+		return c, nil
+	case <-l.closeCh:
+		return nil, ErrClosedListener
+	}
+}
+
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() {
+		l.n.Lock()
+		if l.n.listenerMap[l.address] == l {
+			delete(l.n.listenerMap, l.address)
+		}
+		l.n.Unlock()
+		close(l.closeCh)
+	})
+	return nil
+}
+
+func (l *listener) Addr() net.Addr {
+	return addr(l.address)
+}
+
+type conn struct {
+	n      *Network
+	local  string
+	remote string
+	laddr  net.Addr
+	raddr  net.Addr
+	in     *pipe
+	out    *pipe
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	return c.in.read(b)
+}
+
+func (c *conn) Write(b []byte) (int, error) {
+	if c.out.isClosed() {
+		return 0, ErrClosedConnection
+	}
+	data := make([]byte, len(b))
+	copy(data, b)
+	if !isPacket(data) {
+		c.out.push(data, 0, false)
+		return len(b), nil
+	}
+	drop, delay, reorder := c.n.route(c.local, c.remote)
+	if !drop {
+		c.out.push(data, delay, reorder)
+	}
+	return len(b), nil
+}
+
+func (c *conn) Close() error {
+	c.n.Lock()
+	delete(c.n.connMap, c)
+	c.n.Unlock()
+
+	c.in.close()
+	c.out.close()
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.raddr
+}
+
+func (c *conn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// isPacket returns true when the data is a whole packet that is made by p2p.BytesToPacket
+func isPacket(bs []byte) bool {
+	if len(bs) < 7 {
+		return false
+	}
+	if bs[2] != 0 && bs[2] != 1 {
+		return false
+	}
+	return binary.LittleEndian.Uint32(bs[3:]) == uint32(len(bs)-7)
+}
+
+type frame struct {
+	at   time.Time
+	data []byte
+}
+
+// pipe delivers frames of one direction in order except for reordered ones
+type pipe struct {
+	sync.Mutex
+	scheduler *Scheduler
+	cond      *sync.Cond
+	buf       []byte
+	queue     []*frame
+	isRunning bool
+	closed    bool
+}
+
+func newPipe(scheduler *Scheduler) *pipe {
+	p := &pipe{
+		scheduler: scheduler,
+	}
+	p.cond = sync.NewCond(&p.Mutex)
+	return p
+}
+
+func (p *pipe) push(data []byte, delay time.Duration, reorder bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed {
+		return
+	}
+	if reorder {
+		p.scheduler.AfterFunc(delay, func() {
+			p.Lock()
+			defer p.Unlock()
+
+			p.deliver(data)
+		})
+		return
+	}
+	if delay == 0 && len(p.queue) == 0 {
+		p.deliver(data)
+		return
+	}
+	p.queue = append(p.queue, &frame{
+		at:   p.scheduler.Now().Add(delay),
+		data: data,
+	})
+	if !p.isRunning {
+		p.isRunning = true
+		go p.flush()
+	}
+}
+
+func (p *pipe) flush() {
+	p.Lock()
+	defer p.Unlock()
+
+	for len(p.queue) > 0 && !p.closed {
+		f := p.queue[0]
+		if wait := f.at.Sub(p.scheduler.Now()); wait > 0 {
+			p.Unlock()
+			p.scheduler.Sleep(wait)
+			p.Lock()
+			continue
+		}
+		p.queue = p.queue[1:]
+		p.deliver(f.data)
+	}
+	p.isRunning = false
+}
+
+// deliver should be called with the lock
+func (p *pipe) deliver(data []byte) {
+	if p.closed {
+		return
+	}
+	p.buf = append(p.buf, data...)
+	p.scheduler.Touch()
+	p.cond.Broadcast()
+}
+
+func (p *pipe) read(b []byte) (int, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	for len(p.buf) == 0 && !p.closed {
+		p.cond.Wait()
+	}
+	if len(p.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+func (p *pipe) close() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.closed = true
+	p.queue = nil
+	p.cond.Broadcast()
+}
+
+func (p *pipe) isClosed() bool {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.closed
+}
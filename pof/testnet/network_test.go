@@ -0,0 +1,41 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHarness_Partition(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 3, 60*time.Second)
+
+	h.Network.Partition([]string{"ob0"}, []string{"ob1", "ob2", "ob3", "ob4", "fr0", "fr1"})
+	if err := h.WaitProgress(3, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+	h.Network.Heal()
+	checkHeight(t, h, h.highestHeight()+1, 60*time.Second)
+}
+
+func TestHarness_Faults(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	h.Network.SetFault(Fault{
+		DropRate:     0.01,
+		MaxDelay:     20 * time.Millisecond,
+		ReorderRate:  0.05,
+		ReorderDelay: 50 * time.Millisecond,
+	})
+	checkHeight(t, h, 3, 60*time.Second)
+	if err := h.WaitProgress(3, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	h.Network.ClearFaults()
+	checkHeight(t, h, h.highestHeight()+1, 60*time.Second)
+}
@@ -0,0 +1,45 @@
+package testnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+func TestHarness_ObserverRoundRestart(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 3, 60*time.Second)
+
+	ob := h.Observers()[0]
+	for _, state := range []int{pof.RoundVoteState, pof.RoundVoteAckState, pof.BlockWaitState, pof.BlockVoteState} {
+		if !h.WaitUntil(func() bool {
+			return ob.RoundState() == state
+		}, 60*time.Second) {
+			t.Fatal("not reached round state", state)
+		}
+		if err := h.Crash(ob); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Restart(ob); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.WaitProgress(3, 60*time.Second); err != nil {
+			t.Fatal("not progressed after the restart at round state", state, err)
+		}
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+	for _, nd := range h.Observers() {
+		evs, err := nd.Evidences()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(evs) > 0 {
+			t.Fatal("conflicting votes are signed", nd.Name, len(evs))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package testnet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+func TestHarness_SignProtection(t *testing.T) {
+	h, closer := newTestHarness(t, testConfig())
+	defer closer()
+
+	checkHeight(t, h, 6, 60*time.Second)
+
+	fr := h.Formulators()[0]
+	if err := h.Crash(fr); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Restart(fr); err != nil {
+		t.Fatal(err)
+	}
+	list, err := fr.SignProtection().Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordMap := map[hash.Hash256]bool{}
+	for _, rec := range list {
+		recordMap[rec.HeaderHash] = true
+	}
+
+	var signed *types.Header
+	for i := uint32(1); i <= 6; i++ {
+		bh, HeaderHash, err := h.Observers()[0].Header(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bh.Generator != fr.address {
+			continue
+		}
+		if !recordMap[HeaderHash] {
+			t.Fatal("not recorded header", i)
+		}
+		signed = bh
+	}
+	if signed == nil {
+		t.Fatal("no header is generated by the formulator")
+	}
+	conflict := *signed
+	conflict.Timestamp++
+	if err := fr.SignProtection().RecordSign(&conflict); err != pof.ErrAlreadySignedHeight {
+		t.Fatal("conflicting header is not refused", err)
+	}
+
+	path, err := ioutil.TempDir("", "signprotect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	back, err := backend.Create(Backend, filepath.Join(path, "signprotect"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer back.Close()
+	sp := pof.NewSignProtection(back)
+	if err := sp.Import(list); err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Import(list); err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.RecordSign(&conflict); err != pof.ErrAlreadySignedHeight {
+		t.Fatal("conflicting header is not refused after the import", err)
+	}
+	if err := sp.RecordSign(signed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.WaitProgress(3, 60*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.CheckConsistency(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,20 @@
 package pof
 
+import "github.com/fletaio/fleta_testnet/common"
+
 // tags
 var (
-	tagState = []byte{1}
+	tagState           = []byte{1}
+	tagEvidence        = []byte{2}
+	tagFormulatorStats = []byte{3}
+	tagSignRecord      = []byte{4}
+	tagRoundVoteAck    = []byte{5}
+	tagBlockVoteGen    = []byte{6}
 )
+
+func toFormulatorStatsKey(addr common.Address) []byte {
+	bs := make([]byte, len(tagFormulatorStats)+common.AddressSize)
+	copy(bs, tagFormulatorStats)
+	copy(bs[len(tagFormulatorStats):], addr[:])
+	return bs
+}
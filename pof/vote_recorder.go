@@ -0,0 +1,207 @@
+package pof
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+type voteKey struct {
+	Type         uint8
+	PublicHash   common.PublicHash
+	Height       uint32
+	TimeoutCount uint32
+}
+
+type recordedVote struct {
+	Digest hash.Hash256
+	Vote   *SignedVote
+}
+
+// VoteRecorder records signed votes of observers and detects conflicting votes in the same round
+type VoteRecorder struct {
+	sync.Mutex
+	back         backend.StoreBackend
+	voteMap      map[voteKey]*recordedVote
+	evidenceMap  map[voteKey]*ObserverEvidence
+	keepHeights  uint32
+	lowestHeight uint32
+}
+
+// NewVoteRecorder returns a VoteRecorder (evidences are kept in memory only when back is nil)
+func NewVoteRecorder(back backend.StoreBackend) *VoteRecorder {
+	vr := &VoteRecorder{
+		back:        back,
+		voteMap:     map[voteKey]*recordedVote{},
+		evidenceMap: map[voteKey]*ObserverEvidence{},
+		keepHeights: 100,
+	}
+	return vr
+}
+
+// Record stores the signed vote and returns the evidence when it conflicts with the recorded one
+func (vr *VoteRecorder) Record(PublicHash common.PublicHash, sv *SignedVote) (*ObserverEvidence, error) {
+	Height, TimeoutCount, Digest, SignedHash, err := sv.Info()
+	if err != nil {
+		return nil, err
+	}
+	if pubkey, err := common.RecoverPubkey(SignedHash, sv.Signature); err != nil {
+		return nil, err
+	} else if common.NewPublicHash(pubkey) != PublicHash {
+		return nil, common.ErrInvalidPublicHash
+	}
+
+	vr.Lock()
+	defer vr.Unlock()
+
+	if Height < vr.lowestHeight {
+		return nil, nil
+	}
+	key := voteKey{
+		Type:         sv.Type,
+		PublicHash:   PublicHash,
+		Height:       Height,
+		TimeoutCount: TimeoutCount,
+	}
+	old, has := vr.voteMap[key]
+	if !has {
+		vr.voteMap[key] = &recordedVote{
+			Digest: Digest,
+			Vote:   sv,
+		}
+		return nil, nil
+	}
+	if old.Digest == Digest {
+		return nil, nil
+	}
+	if _, has := vr.evidenceMap[key]; has {
+		return nil, nil
+	}
+	ev := &ObserverEvidence{
+		PublicHash:   PublicHash,
+		Height:       Height,
+		TimeoutCount: TimeoutCount,
+		VoteA:        old.Vote,
+		VoteB:        sv,
+	}
+	if err := ev.Validate(); err != nil {
+		return nil, err
+	}
+	if vr.back != nil {
+		data, err := encoding.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+		if err := vr.back.Update(func(txn backend.StoreWriter) error {
+			return txn.Set(toEvidenceKey(key), data)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	vr.evidenceMap[key] = ev
+	return ev, nil
+}
+
+// Prune removes recorded votes that are older than the keeping range from the given height
+// and evidences that are older than the evidence valid blocks because they cannot be reported after it (0 means evidences are kept)
+func (vr *VoteRecorder) Prune(Height uint32, EvidenceValidBlocks uint32) error {
+	vr.Lock()
+	defer vr.Unlock()
+
+	if Height > vr.keepHeights {
+		vr.lowestHeight = Height - vr.keepHeights
+		for key := range vr.voteMap {
+			if key.Height < vr.lowestHeight {
+				delete(vr.voteMap, key)
+			}
+		}
+	}
+
+	if EvidenceValidBlocks == 0 || Height <= EvidenceValidBlocks {
+		return nil
+	}
+	LowestHeight := Height - EvidenceValidBlocks
+	for key := range vr.evidenceMap {
+		if key.Height < LowestHeight {
+			delete(vr.evidenceMap, key)
+		}
+	}
+	if vr.back != nil {
+		keys := [][]byte{}
+		if err := vr.back.View(func(txn backend.StoreReader) error {
+			return txn.Iterate(tagEvidence, func(key []byte, value []byte) error {
+				if len(key) < len(tagEvidence)+4 {
+					return ErrInvalidEvidence
+				}
+				if binary.BigEndian.Uint32(key[len(tagEvidence):]) < LowestHeight {
+					keys = append(keys, append([]byte{}, key...))
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := vr.back.Update(func(txn backend.StoreWriter) error {
+				for _, key := range keys {
+					if err := txn.Delete(key); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Evidences returns all evidences that are detected
+func (vr *VoteRecorder) Evidences() ([]*ObserverEvidence, error) {
+	vr.Lock()
+	defer vr.Unlock()
+
+	list := []*ObserverEvidence{}
+	if vr.back != nil {
+		if err := vr.back.View(func(txn backend.StoreReader) error {
+			return txn.Iterate(tagEvidence, func(key []byte, value []byte) error {
+				ev := &ObserverEvidence{}
+				if err := encoding.Unmarshal(value, &ev); err != nil {
+					return err
+				}
+				list = append(list, ev)
+				return nil
+			})
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, ev := range vr.evidenceMap {
+			list = append(list, ev)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Height == list[j].Height {
+			return list[i].TimeoutCount < list[j].TimeoutCount
+		}
+		return list[i].Height < list[j].Height
+	})
+	return list, nil
+}
+
+func toEvidenceKey(key voteKey) []byte {
+	bs := make([]byte, len(tagEvidence)+9+common.PublicHashSize)
+	copy(bs, tagEvidence)
+	idx := len(tagEvidence)
+	binary.BigEndian.PutUint32(bs[idx:], key.Height)
+	binary.BigEndian.PutUint32(bs[idx+4:], key.TimeoutCount)
+	bs[idx+8] = key.Type
+	copy(bs[idx+9:], key.PublicHash[:])
+	return bs
+}
@@ -50,6 +50,7 @@ func (p *Admin) InitAdmin(ctw *types.ContextWrapper, addrMap map[string]common.A
 func (p *Admin) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
 	p.pm = pm
 	p.cn = cn
+	reg.RegisterTransaction(1, &UpdateConsensusPolicy{})
 	return nil
 }
 
@@ -3,6 +3,7 @@ package admin
 import (
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
 )
 
 // AdminAddress returns the admin address
@@ -17,3 +18,43 @@ func (p *Admin) AdminAddress(loader types.Loader, name string) common.Address {
 		return addr
 	}
 }
+
+// consensusAdminAddress returns the admin address of the consensus
+// Chains that don't have it in the genesis use the admin address of the formulator process
+func (p *Admin) consensusAdminAddress(loader types.Loader) common.Address {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toAdminAddressKey(ConsensusAdminName)); len(bs) > 0 {
+		var addr common.Address
+		copy(addr[:], bs)
+		return addr
+	}
+	return p.AdminAddress(loader, "fleta.formulator")
+}
+
+// PendingConsensusPolicy returns the consensus policy that is not activated yet
+// The consensus applies it after the block of the previous height of the activation height and removes it
+func (p *Admin) PendingConsensusPolicy(loader types.Loader) (*PendingConsensusPolicy, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	bs := lw.ProcessData(tagPendingConsensusPolicy)
+	if len(bs) == 0 {
+		return nil, ErrNotExistPendingConsensusPolicy
+	}
+
+	pc := &PendingConsensusPolicy{}
+	if err := encoding.Unmarshal(bs, &pc); err != nil {
+		return nil, err
+	}
+	if pc.ActivationHeight <= loader.TargetHeight() {
+		return nil, ErrNotExistPendingConsensusPolicy
+	}
+	return pc, nil
+}
+
+// RemovePendingConsensusPolicy removes the pending consensus policy when the consensus applies it
+func (p *Admin) RemovePendingConsensusPolicy(ctw *types.ContextWrapper) {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	ctw.SetProcessData(tagPendingConsensusPolicy, nil)
+}
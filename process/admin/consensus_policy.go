@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ConsensusAdminName is the name of the admin address that updates the consensus policy
+// The admin address of fleta.formulator is used when it is not set in the genesis
+const ConsensusAdminName = "fleta.consensus"
+
+// MinActivationDelay is the minimum number of blocks from the update of the consensus policy to the activation of it
+// Nodes and light clients see the pending policy in the delay, so the policy is not changed at the next block without notice
+const MinActivationDelay = 100
+
+// ConsensusPolicy defines parameters of the consensus that can be updated by the admin
+type ConsensusPolicy struct {
+	MaxBlocksPerFormulator uint32
+	ObserverQuorum         uint32 // 0 means the majority of observers
+	MaxPhaseDiff           uint32 // 0 means no limit
+}
+
+// MarshalJSON is a marshaler function
+func (pc *ConsensusPolicy) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"max_blocks_per_formulator":`)
+	if bs, err := json.Marshal(pc.MaxBlocksPerFormulator); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"observer_quorum":`)
+	if bs, err := json.Marshal(pc.ObserverQuorum); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"max_phase_diff":`)
+	if bs, err := json.Marshal(pc.MaxPhaseDiff); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// PendingConsensusPolicy is the consensus policy that takes effect from the activation height
+type PendingConsensusPolicy struct {
+	ActivationHeight uint32
+	Policy           *ConsensusPolicy
+}
+
+// MarshalJSON is a marshaler function
+func (pc *PendingConsensusPolicy) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"activation_height":`)
+	if bs, err := json.Marshal(pc.ActivationHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"policy":`)
+	if bs, err := pc.Policy.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
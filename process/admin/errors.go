@@ -4,7 +4,10 @@ import "errors"
 
 // errors
 var (
-	ErrInvalidAdminAddress     = errors.New("invalid admin address")
-	ErrUnauthorizedTransaction = errors.New("unauthorized transaction")
-	ErrNotExistAdminAddress    = errors.New("not exist admin address")
+	ErrInvalidAdminAddress            = errors.New("invalid admin address")
+	ErrUnauthorizedTransaction        = errors.New("unauthorized transaction")
+	ErrNotExistAdminAddress           = errors.New("not exist admin address")
+	ErrInvalidConsensusPolicy         = errors.New("invalid consensus policy")
+	ErrInvalidActivationHeight        = errors.New("invalid activation height")
+	ErrNotExistPendingConsensusPolicy = errors.New("not exist pending consensus policy")
 )
@@ -2,7 +2,8 @@ package admin
 
 // tags
 var (
-	tagAdminAddress = []byte{1, 1}
+	tagAdminAddress           = []byte{1, 1}
+	tagPendingConsensusPolicy = []byte{1, 2}
 )
 
 func toAdminAddressKey(Name string) []byte {
@@ -0,0 +1,144 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// MaxEscrowPeriod is the maximum number of blocks from the funding to the deadline of the escrow
+const MaxEscrowPeriod = 172800 * 90 // 90 days
+
+// contract statuses
+const (
+	StatusFunded    = uint8(1)
+	StatusReleased  = uint8(2)
+	StatusRefunded  = uint8(3)
+	StatusSplit     = uint8(4)
+	StatusReclaimed = uint8(5)
+)
+
+// Contract is an escrow that the buyer funded for the seller
+// The buyer releases the amount to the seller, the seller refunds it to the buyer
+// and the arbiter splits it between them. The buyer reclaims it from the deadline
+// The zero arbiter address means no arbiter and the zero deadline means no deadline
+type Contract struct {
+	ID           string
+	Buyer        common.Address
+	Seller       common.Address
+	Arbiter      common.Address
+	Amount       *amount.Amount
+	Deadline     uint32
+	Status       uint8
+	BuyerAmount  *amount.Amount
+	SellerAmount *amount.Amount
+}
+
+// HasArbiter returns the contract has the arbiter or not
+func (ct *Contract) HasArbiter() bool {
+	return ct.Arbiter != common.Address{}
+}
+
+// HasDeadline returns the contract has the deadline or not
+func (ct *Contract) HasDeadline() bool {
+	return ct.Deadline != 0
+}
+
+// StatusName returns the name of the status of the contract
+func (ct *Contract) StatusName() string {
+	return StatusName(ct.Status)
+}
+
+// StatusName returns the name of the contract status
+func StatusName(Status uint8) string {
+	switch Status {
+	case StatusFunded:
+		return "funded"
+	case StatusReleased:
+		return "released"
+	case StatusRefunded:
+		return "refunded"
+	case StatusSplit:
+		return "split"
+	case StatusReclaimed:
+		return "reclaimed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON is a marshaler function
+func (ct *Contract) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"id":`)
+	if bs, err := json.Marshal(ct.ID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"buyer":`)
+	if bs, err := ct.Buyer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller":`)
+	if bs, err := ct.Seller.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"arbiter":`)
+	if ct.HasArbiter() {
+		if bs, err := ct.Arbiter.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	} else {
+		buffer.WriteString(`null`)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := ct.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"deadline":`)
+	if bs, err := json.Marshal(ct.Deadline); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"status":`)
+	if bs, err := json.Marshal(ct.StatusName()); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"buyer_amount":`)
+	if bs, err := ct.BuyerAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller_amount":`)
+	if bs, err := ct.SellerAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,16 @@
+package escrow
+
+import "errors"
+
+// errors
+var (
+	ErrInvalidDeadline    = errors.New("invalid deadline")
+	ErrInvalidParty       = errors.New("invalid party")
+	ErrInvalidSplitAmount = errors.New("invalid split amount")
+	ErrNotExistContract   = errors.New("not exist contract")
+	ErrExistContract      = errors.New("exist contract")
+	ErrNotFundedContract  = errors.New("not funded contract")
+	ErrNotAllowedParty    = errors.New("not allowed party")
+	ErrNotExistArbiter    = errors.New("not exist arbiter")
+	ErrNotReachedDeadline = errors.New("not reached deadline")
+)
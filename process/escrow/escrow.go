@@ -0,0 +1,131 @@
+package escrow
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/vault"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// Escrow manages escrows between buyers and sellers whose amounts are held by the vault
+type Escrow struct {
+	*types.ProcessBase
+	pid   uint8
+	pm    types.ProcessManager
+	cn    types.Provider
+	vault *vault.Vault
+}
+
+// NewEscrow returns a Escrow
+func NewEscrow(pid uint8) *Escrow {
+	p := &Escrow{
+		pid: pid,
+	}
+	return p
+}
+
+// ID returns the id of the process
+func (p *Escrow) ID() uint8 {
+	return p.pid
+}
+
+// Name returns the name of the process
+func (p *Escrow) Name() string {
+	return "fleta.escrow"
+}
+
+// Version returns the version of the process
+func (p *Escrow) Version() string {
+	return "0.0.1"
+}
+
+// Init initializes the process
+func (p *Escrow) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
+	p.pm = pm
+	p.cn = cn
+
+	if vp, err := pm.ProcessByName("fleta.vault"); err != nil {
+		return err
+	} else if v, is := vp.(*vault.Vault); !is {
+		return types.ErrInvalidProcess
+	} else {
+		p.vault = v
+	}
+
+	reg.RegisterTransaction(1, &Fund{})
+	reg.RegisterTransaction(2, &Release{})
+	reg.RegisterTransaction(3, &Refund{})
+	reg.RegisterTransaction(4, &Split{})
+	reg.RegisterTransaction(5, &Reclaim{})
+	reg.RegisterEvent(1, &FundedEvent{})
+	reg.RegisterEvent(2, &SettledEvent{})
+
+	if vs, err := pm.ServiceByName("fleta.apiserver"); err != nil {
+		//ignore when not loaded
+	} else if v, is := vs.(*apiserver.APIServer); !is {
+		//ignore when not loaded
+	} else {
+		s, err := v.JRPC("escrow")
+		if err != nil {
+			return err
+		}
+		s.Set("get", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			EscrowID, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			if _, _, err := types.ParseTransactionID(EscrowID); err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.Contract(ctw, EscrowID)
+		})
+		s.Set("byParty", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			arg0, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			addr, err := common.ParseAddress(arg0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			list := []*Contract{}
+			for _, EscrowID := range p.ContractIDsByParty(ctw, addr) {
+				ct, err := p.Contract(ctw, EscrowID)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, ct)
+			}
+			return list, nil
+		})
+	}
+	return nil
+}
+
+// OnLoadChain called when the chain loaded
+func (p *Escrow) OnLoadChain(loader types.LoaderWrapper) error {
+	return nil
+}
+
+// BeforeExecuteTransactions called before processes transactions of the block
+func (p *Escrow) BeforeExecuteTransactions(ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// AfterExecuteTransactions called after processes transactions of the block
+func (p *Escrow) AfterExecuteTransactions(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// OnSaveData called when the context of the block saved
+func (p *Escrow) OnSaveData(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package escrow
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/util"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// Contract returns the escrow contract of the id
+func (p *Escrow) Contract(loader types.Loader, ID string) (*Contract, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toContractKey(ID)); len(bs) > 0 {
+		ct := &Contract{}
+		if err := encoding.Unmarshal(bs, &ct); err != nil {
+			return nil, err
+		}
+		return ct, nil
+	} else {
+		return nil, ErrNotExistContract
+	}
+}
+
+func (p *Escrow) setContract(ctw *types.ContextWrapper, ct *Contract) error {
+	bs, err := encoding.Marshal(ct)
+	if err != nil {
+		return err
+	}
+	ctw.SetProcessData(toContractKey(ct.ID), bs)
+	return nil
+}
+
+// ContractIDsByParty returns ids of escrow contracts that the address is the buyer, the seller or the arbiter of in the funded order
+func (p *Escrow) ContractIDsByParty(loader types.Loader, addr common.Address) []string {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	var Count uint32
+	if bs := lw.AccountData(addr, tagPartyContractCount); len(bs) > 0 {
+		Count = util.BytesToUint32(bs)
+	}
+	IDs := make([]string, 0, Count)
+	for i := uint32(0); i < Count; i++ {
+		IDs = append(IDs, string(lw.AccountData(addr, toPartyContractKey(i))))
+	}
+	return IDs
+}
+
+func (p *Escrow) addPartyContract(ctw *types.ContextWrapper, addr common.Address, ID string) {
+	var Count uint32
+	if bs := ctw.AccountData(addr, tagPartyContractCount); len(bs) > 0 {
+		Count = util.BytesToUint32(bs)
+	}
+	ctw.SetAccountData(addr, toPartyContractKey(Count), []byte(ID))
+	Count++
+	ctw.SetAccountData(addr, tagPartyContractCount, util.Uint32ToBytes(Count))
+}
+
+// settle releases the held amount of the funded contract to the buyer and the seller and emits the settled event
+func (p *Escrow) settle(ctw *types.ContextWrapper, index uint16, ct *Contract, Status uint8, BuyerAmount *amount.Amount) error {
+	if ct.Status != StatusFunded {
+		return ErrNotFundedContract
+	}
+	if ct.Amount.Less(BuyerAmount) {
+		return ErrInvalidSplitAmount
+	}
+	SellerAmount := ct.Amount.Sub(BuyerAmount)
+	if !BuyerAmount.IsZero() {
+		if err := p.vault.Release(ctw, ct.Buyer, BuyerAmount); err != nil {
+			return err
+		}
+	}
+	if !SellerAmount.IsZero() {
+		if err := p.vault.Release(ctw, ct.Seller, SellerAmount); err != nil {
+			return err
+		}
+	}
+	ct.Status = Status
+	ct.BuyerAmount = BuyerAmount
+	ct.SellerAmount = SellerAmount
+	if err := p.setContract(ctw, ct); err != nil {
+		return err
+	}
+
+	ev := &SettledEvent{
+		Height_:      ctw.TargetHeight(),
+		Index_:       index,
+		ContractID:   ct.ID,
+		Status:       Status,
+		Buyer:        ct.Buyer,
+		Seller:       ct.Seller,
+		BuyerAmount:  BuyerAmount,
+		SellerAmount: SellerAmount,
+	}
+	if err := ctw.EmitEvent(ev); err != nil {
+		return err
+	}
+	return nil
+}
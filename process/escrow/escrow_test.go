@@ -0,0 +1,153 @@
+package escrow
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+type escrowFixture struct {
+	p     *Escrow
+	vp    *vault.Vault
+	ctx   *types.Context
+	Addrs []common.Address
+	Keys  []common.PublicHash
+}
+
+// newEscrowFixture creates the buyer, the seller and the arbiter that have 100 coins each at the height 10
+func newEscrowFixture(t *testing.T) *escrowFixture {
+	p := NewEscrow(8)
+	vp := vault.NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), vp, p); err != nil {
+		t.Fatal(err)
+	}
+	fx := &escrowFixture{
+		p:   p,
+		vp:  vp,
+		ctx: types.NewEmptyContext(),
+	}
+	ctw := types.NewContextWrapper(p.ID(), fx.ctx)
+	for i, Name := range []string{"buyer", "seller", "arbiter"} {
+		acc := &vault.SingleAccount{
+			Address_: common.NewAddress(0, uint16(i+1), 0),
+			Name_:    Name,
+			KeyHash:  common.PublicHash{byte(i + 1)},
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := vp.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(100, 0)); err != nil {
+			t.Fatal(err)
+		}
+		fx.Addrs = append(fx.Addrs, acc.Address())
+		fx.Keys = append(fx.Keys, acc.KeyHash)
+	}
+	fx.ctx = processtest.Advance(fx.ctx, 10)
+	return fx
+}
+
+// fund funds 10 coins from the buyer to the seller with or without the arbiter
+func (fx *escrowFixture) fund(HasArbiter bool) (string, error) {
+	ID := types.TransactionID(fx.ctx.TargetHeight(), 0)
+	tx := &Fund{
+		Seq_:   processtest.NextSeq(fx.ctx, fx.Addrs[0]),
+		From_:  fx.Addrs[0],
+		Seller: fx.Addrs[1],
+		Amount: amount.NewCoinAmount(10, 0),
+	}
+	if HasArbiter {
+		tx.Arbiter = fx.Addrs[2]
+	}
+	return ID, processtest.Execute(fx.ctx, fx.p, tx, fx.Keys[0])
+}
+
+func (fx *escrowFixture) split(Signer int, ID string, BuyerAmount *amount.Amount) error {
+	return processtest.Execute(fx.ctx, fx.p, &Split{
+		Seq_:        processtest.NextSeq(fx.ctx, fx.Addrs[Signer]),
+		From_:       fx.Addrs[Signer],
+		ContractID:  ID,
+		BuyerAmount: BuyerAmount,
+	}, fx.Keys[Signer])
+}
+
+func TestEscrow_Split(t *testing.T) {
+	tests := []struct {
+		name        string
+		arbiter     bool
+		signer      int
+		buyerAmount string
+		err         error
+		balances    []string
+	}{
+		{"split between the buyer and the seller", true, 2, "4", nil, []string{"93.9", "106", "99.9"}},
+		{"whole amount to the buyer", true, 2, "10", nil, []string{"99.9", "100", "99.9"}},
+		{"whole amount to the seller", true, 2, "0", nil, []string{"89.9", "110", "99.9"}},
+		{"over the contract amount", true, 2, "10.1", ErrInvalidSplitAmount, []string{"89.9", "100", "100"}},
+		{"missing amount", true, 2, "", ErrInvalidSplitAmount, []string{"89.9", "100", "100"}},
+		{"split by the buyer", true, 0, "10", ErrNotAllowedParty, []string{"89.9", "100", "100"}},
+		{"split by the seller", true, 1, "0", ErrNotAllowedParty, []string{"89.9", "100", "100"}},
+		{"split without the arbiter", false, 2, "4", ErrNotExistArbiter, []string{"89.9", "100", "100"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newEscrowFixture(t)
+			ID, err := fx.fund(tt.arbiter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var BuyerAmount *amount.Amount
+			if len(tt.buyerAmount) > 0 {
+				BuyerAmount = amount.MustParseAmount(tt.buyerAmount)
+			}
+			if err := fx.split(tt.signer, ID, BuyerAmount); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			for i, v := range tt.balances {
+				if am := fx.vp.Balance(fx.ctx, fx.Addrs[i]); !am.Equal(amount.MustParseAmount(v)) {
+					t.Fatalf("invalid balance of %v: %v, expected %v", i, am, v)
+				}
+			}
+			ct, err := fx.p.Contract(fx.ctx, ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != nil {
+				if ct.Status != StatusFunded {
+					t.Fatalf("invalid status %v", ct.StatusName())
+				}
+				if am := fx.vp.HeldBalance(fx.ctx); !am.Equal(ct.Amount) {
+					t.Fatalf("invalid held balance %v", am)
+				}
+				return
+			}
+			if ct.Status != StatusSplit {
+				t.Fatalf("invalid status %v", ct.StatusName())
+			}
+			if !ct.BuyerAmount.Equal(BuyerAmount) || !ct.SellerAmount.Equal(ct.Amount.Sub(BuyerAmount)) {
+				t.Fatalf("invalid split amounts %v, %v", ct.BuyerAmount, ct.SellerAmount)
+			}
+			if am := fx.vp.HeldBalance(fx.ctx); !am.IsZero() {
+				t.Fatalf("invalid held balance %v", am)
+			}
+			Events := fx.ctx.Top().Events
+			ev, is := Events[len(Events)-1].(*SettledEvent)
+			if !is {
+				t.Fatalf("invalid event %T", Events[len(Events)-1])
+			}
+			if ev.ContractID != ID || ev.Status != StatusSplit || !ev.BuyerAmount.Equal(ct.BuyerAmount) || !ev.SellerAmount.Equal(ct.SellerAmount) {
+				t.Fatalf("invalid settled event %v %v %v %v", ev.ContractID, ev.Status, ev.BuyerAmount, ev.SellerAmount)
+			}
+
+			// the settled contract cannot be split again
+			if err := fx.split(2, ID, amount.NewCoinAmount(0, 0)); err != ErrNotFundedContract {
+				t.Fatalf("expected %v but %v", ErrNotFundedContract, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// FundedEvent is emitted when the buyer funds the escrow contract
+type FundedEvent struct {
+	Height_    uint32
+	Index_     uint16
+	N_         uint16
+	ContractID string
+	Buyer      common.Address
+	Seller     common.Address
+	Arbiter    common.Address
+	Amount     *amount.Amount
+	Deadline   uint32
+}
+
+// Height returns the height of the event
+func (ev *FundedEvent) Height() uint32 {
+	return ev.Height_
+}
+
+// Index returns the index of the event
+func (ev *FundedEvent) Index() uint16 {
+	return ev.Index_
+}
+
+// N returns the n of the event
+func (ev *FundedEvent) N() uint16 {
+	return ev.N_
+}
+
+// SetN updates the n of the event
+func (ev *FundedEvent) SetN(n uint16) {
+	ev.N_ = n
+}
+
+// MarshalJSON is a marshaler function
+func (ev *FundedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ev.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"n":`)
+	if bs, err := json.Marshal(ev.N_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(ev.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"buyer":`)
+	if bs, err := ev.Buyer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller":`)
+	if bs, err := ev.Seller.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"arbiter":`)
+	if bs, err := ev.Arbiter.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := ev.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"deadline":`)
+	if bs, err := json.Marshal(ev.Deadline); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// SettledEvent is emitted when the escrow contract is released, refunded, split or reclaimed
+type SettledEvent struct {
+	Height_      uint32
+	Index_       uint16
+	N_           uint16
+	ContractID   string
+	Status       uint8
+	Buyer        common.Address
+	Seller       common.Address
+	BuyerAmount  *amount.Amount
+	SellerAmount *amount.Amount
+}
+
+// Height returns the height of the event
+func (ev *SettledEvent) Height() uint32 {
+	return ev.Height_
+}
+
+// Index returns the index of the event
+func (ev *SettledEvent) Index() uint16 {
+	return ev.Index_
+}
+
+// N returns the n of the event
+func (ev *SettledEvent) N() uint16 {
+	return ev.N_
+}
+
+// SetN updates the n of the event
+func (ev *SettledEvent) SetN(n uint16) {
+	ev.N_ = n
+}
+
+// MarshalJSON is a marshaler function
+func (ev *SettledEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ev.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"n":`)
+	if bs, err := json.Marshal(ev.N_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(ev.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"status":`)
+	if bs, err := json.Marshal(StatusName(ev.Status)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"buyer":`)
+	if bs, err := ev.Buyer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller":`)
+	if bs, err := ev.Seller.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"buyer_amount":`)
+	if bs, err := ev.BuyerAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller_amount":`)
+	if bs, err := ev.SellerAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
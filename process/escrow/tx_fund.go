@@ -0,0 +1,193 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Fund funds the escrow contract for the seller by the buyer with the optional arbiter and deadline
+// The id of the contract is the transaction id of it
+type Fund struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	Seller     common.Address
+	Arbiter    common.Address
+	Amount     *amount.Amount
+	Deadline   uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Fund) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Fund) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Fund) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Fund) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Fund) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Escrow)
+
+	if tx.Amount.Less(amount.COIN.DivC(10)) {
+		return types.ErrDustAmount
+	}
+	if tx.Seller == tx.From() || tx.Arbiter == tx.From() || tx.Arbiter == tx.Seller {
+		return ErrInvalidParty
+	}
+	if tx.Deadline != 0 {
+		if tx.Deadline <= loader.TargetHeight() || tx.Deadline > loader.TargetHeight()+MaxEscrowPeriod {
+			return ErrInvalidDeadline
+		}
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if has, err := loader.HasAccount(tx.Seller); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+	if tx.Arbiter != (common.Address{}) {
+		if has, err := loader.HasAccount(tx.Arbiter); err != nil {
+			return err
+		} else if !has {
+			return types.ErrNotExistAccount
+		}
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayableWith(loader, tx, tx.Amount); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Fund) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Escrow)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ID := types.TransactionID(ctw.TargetHeight(), index)
+		if _, err := sp.Contract(ctw, ID); err == nil {
+			return ErrExistContract
+		}
+		if err := sp.vault.Hold(ctw, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+		ct := &Contract{
+			ID:           ID,
+			Buyer:        tx.From(),
+			Seller:       tx.Seller,
+			Arbiter:      tx.Arbiter,
+			Amount:       tx.Amount,
+			Deadline:     tx.Deadline,
+			Status:       StatusFunded,
+			BuyerAmount:  amount.NewCoinAmount(0, 0),
+			SellerAmount: amount.NewCoinAmount(0, 0),
+		}
+		if err := sp.setContract(ctw, ct); err != nil {
+			return err
+		}
+		sp.addPartyContract(ctw, ct.Buyer, ID)
+		sp.addPartyContract(ctw, ct.Seller, ID)
+		if ct.HasArbiter() {
+			sp.addPartyContract(ctw, ct.Arbiter, ID)
+		}
+
+		ev := &FundedEvent{
+			Height_:    ctw.TargetHeight(),
+			Index_:     index,
+			ContractID: ID,
+			Buyer:      ct.Buyer,
+			Seller:     ct.Seller,
+			Arbiter:    ct.Arbiter,
+			Amount:     ct.Amount,
+			Deadline:   ct.Deadline,
+		}
+		if err := ctw.EmitEvent(ev); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Fund) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seller":`)
+	if bs, err := tx.Seller.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"arbiter":`)
+	if bs, err := tx.Arbiter.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"deadline":`)
+	if bs, err := json.Marshal(tx.Deadline); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
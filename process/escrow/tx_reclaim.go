@@ -0,0 +1,122 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Reclaim reclaims the whole amount of the escrow contract to the buyer from the deadline
+type Reclaim struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	ContractID string
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Reclaim) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Reclaim) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Reclaim) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Reclaim) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Reclaim) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Escrow)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	ct, err := sp.Contract(loader, tx.ContractID)
+	if err != nil {
+		return err
+	}
+	if ct.Status != StatusFunded {
+		return ErrNotFundedContract
+	}
+	if ct.Buyer != tx.From() {
+		return ErrNotAllowedParty
+	}
+	if !ct.HasDeadline() || loader.TargetHeight() < ct.Deadline {
+		return ErrNotReachedDeadline
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Reclaim) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Escrow)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ct, err := sp.Contract(ctw, tx.ContractID)
+		if err != nil {
+			return err
+		}
+		return sp.settle(ctw, index, ct, StatusReclaimed, ct.Amount)
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Reclaim) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(tx.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+package escrow
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Release releases the whole amount of the escrow contract to the seller by the buyer
+type Release struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	ContractID string
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Release) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Release) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Release) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Release) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Release) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Escrow)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	ct, err := sp.Contract(loader, tx.ContractID)
+	if err != nil {
+		return err
+	}
+	if ct.Status != StatusFunded {
+		return ErrNotFundedContract
+	}
+	if ct.Buyer != tx.From() {
+		return ErrNotAllowedParty
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Release) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Escrow)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ct, err := sp.Contract(ctw, tx.ContractID)
+		if err != nil {
+			return err
+		}
+		return sp.settle(ctw, index, ct, StatusReleased, amount.NewCoinAmount(0, 0))
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Release) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(tx.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
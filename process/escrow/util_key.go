@@ -0,0 +1,26 @@
+package escrow
+
+import (
+	"github.com/fletaio/fleta_testnet/common/util"
+)
+
+// tags
+var (
+	tagContract           = []byte{1, 0}
+	tagPartyContractCount = []byte{2, 0}
+	tagPartyContract      = []byte{2, 1}
+)
+
+func toContractKey(ID string) []byte {
+	bs := make([]byte, 2+len(ID))
+	copy(bs, tagContract)
+	copy(bs[2:], []byte(ID))
+	return bs
+}
+
+func toPartyContractKey(index uint32) []byte {
+	bs := make([]byte, 6)
+	copy(bs, tagPartyContract)
+	copy(bs[2:], util.Uint32ToBytes(index))
+	return bs
+}
@@ -33,4 +33,11 @@ var (
 	ErrInvalidTransmuteAmount                  = errors.New("invalid transmute amount")
 	ErrInvalidTransmuteHeight                  = errors.New("invalid transmute height")
 	ErrNotExistTransmutePolicy                 = errors.New("not exist transmute policy")
+	ErrNotExistSlashPolicy                     = errors.New("not exist slash policy")
+	ErrInvalidSlashPolicy                      = errors.New("invalid slash policy")
+	ErrInvalidDoubleSignEvidence               = errors.New("invalid double sign evidence")
+	ErrExpiredDoubleSignEvidence               = errors.New("expired double sign evidence")
+	ErrSlashedFormulator                       = errors.New("slashed formulator")
+	ErrSameHyperFormulator                     = errors.New("same hyper formulator")
+	ErrRedelegateCooldown                      = errors.New("redelegate cooldown")
 )
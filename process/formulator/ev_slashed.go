@@ -0,0 +1,112 @@
+package formulator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// SlashedEvent is emitted when the formulator is slashed by the double sign evidence
+type SlashedEvent struct {
+	Height_              uint32
+	Index_               uint16
+	N_                   uint16
+	Formulator           common.Address
+	Reporter             common.Address
+	EvidenceHeight       uint32
+	SlashedAmount        *amount.Amount
+	SlashedStakingAmount *amount.Amount
+	ReporterReward       *amount.Amount
+}
+
+// Height returns the height of the event
+func (ev *SlashedEvent) Height() uint32 {
+	return ev.Height_
+}
+
+// Index returns the index of the event
+func (ev *SlashedEvent) Index() uint16 {
+	return ev.Index_
+}
+
+// N returns the n of the event
+func (ev *SlashedEvent) N() uint16 {
+	return ev.N_
+}
+
+// SetN updates the n of the event
+func (ev *SlashedEvent) SetN(n uint16) {
+	ev.N_ = n
+}
+
+// MarshalJSON is a marshaler function
+func (ev *SlashedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ev.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"n":`)
+	if bs, err := json.Marshal(ev.N_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"formulator":`)
+	if bs, err := ev.Formulator.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"reporter":`)
+	if bs, err := ev.Reporter.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"evidence_height":`)
+	if bs, err := json.Marshal(ev.EvidenceHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"slashed_amount":`)
+	if bs, err := ev.SlashedAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"slashed_staking_amount":`)
+	if bs, err := ev.SlashedStakingAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"reporter_reward":`)
+	if bs, err := ev.ReporterReward.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
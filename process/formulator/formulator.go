@@ -78,9 +78,13 @@ func (p *Formulator) Init(reg *types.Register, pm types.ProcessManager, cn types
 	reg.RegisterTransaction(13, &Transmute{})
 	reg.RegisterTransaction(14, &UpdateRewardPolicy{})
 	reg.RegisterTransaction(15, &UpdateTransmutePolicy{})
+	reg.RegisterTransaction(16, &ReportDoubleSign{})
+	reg.RegisterTransaction(17, &UpdateSlashPolicy{})
+	reg.RegisterTransaction(18, &Redelegate{})
 	reg.RegisterEvent(1, &RewardEvent{})
 	reg.RegisterEvent(2, &RevokedEvent{})
 	reg.RegisterEvent(3, &UnstakedEvent{})
+	reg.RegisterEvent(4, &SlashedEvent{})
 	return nil
 }
 
@@ -161,6 +165,11 @@ func (p *Formulator) OnLoadChain(loader types.LoaderWrapper) error {
 
 // BeforeExecuteTransactions called before processes transactions of the block
 func (p *Formulator) BeforeExecuteTransactions(ctw *types.ContextWrapper) error {
+	if p.vault.IsSeedHeight(ctw, vault.SupplyUpgrade) {
+		if err := p.seedSupply(ctw); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -440,7 +449,10 @@ func (p *Formulator) AfterExecuteTransactions(b *types.Block, ctw *types.Context
 							}
 							if !RewardAmount.IsZero() {
 								if p.GetUserAutoStaking(ctw, frAcc.Address(), StakingAddress) {
-									p.AddStakingAmount(ctw, frAcc.Address(), StakingAddress, RewardAmount)
+									if err := p.AddStakingAmount(ctw, frAcc.Address(), StakingAddress, RewardAmount); err != nil {
+										inErr = err
+										return false
+									}
 									ev.AddStaked(frAcc.Address(), StakingAddress, RewardAmount)
 								} else {
 									if err := p.vault.AddBalance(ctw, StakingAddress, RewardAmount); err != nil {
@@ -500,13 +512,25 @@ func (p *Formulator) AfterExecuteTransactions(b *types.Block, ctw *types.Context
 			inErr = types.ErrInvalidAccountType
 			return false
 		}
+		if err := p.vault.SubFormulatorAmount(ctw, frAcc.Amount); err != nil {
+			inErr = err
+			return false
+		}
 		if has, err := ctw.HasAccount(Heritor); err != nil {
 			if err == types.ErrDeletedAccount {
+				if err := p.burnRevokedFormulator(ctw, frAcc); err != nil {
+					inErr = err
+					return false
+				}
 			} else {
 				inErr = err
 				return false
 			}
 		} else if !has {
+			if err := p.burnRevokedFormulator(ctw, frAcc); err != nil {
+				inErr = err
+				return false
+			}
 		} else {
 			if err := p.vault.AddBalance(ctw, Heritor, frAcc.Amount); err != nil {
 				inErr = err
@@ -537,6 +561,10 @@ func (p *Formulator) AfterExecuteTransactions(b *types.Block, ctw *types.Context
 					return false
 				}
 				frAcc.StakingAmount = frAcc.StakingAmount.Sub(StakingAmount)
+				if err := p.vault.SubStakingAmount(ctw, StakingAmount); err != nil {
+					inErr = err
+					return false
+				}
 
 				if err := p.vault.AddBalance(ctw, addr, StakingAmount); err != nil {
 					inErr = err
@@ -548,6 +576,10 @@ func (p *Formulator) AfterExecuteTransactions(b *types.Block, ctw *types.Context
 				return false
 			}
 		}
+		if err := p.vault.ReleaseAccountNames(ctw, frAcc); err != nil {
+			inErr = err
+			return false
+		}
 		if err := ctw.DeleteAccount(frAcc); err != nil {
 			inErr = err
 			return false
@@ -573,6 +605,10 @@ func (p *Formulator) AfterExecuteTransactions(b *types.Block, ctw *types.Context
 	}
 	UnstakedMap.EachAll(func(Addr common.Address, AmountMap *types.AddressAmountMap) bool {
 		AmountMap.EachAll(func(HyperAddr common.Address, am *amount.Amount) bool {
+			if err := p.vault.SubUnstakingAmount(ctw, am); err != nil {
+				inErr = err
+				return false
+			}
 			if err := p.vault.AddBalance(ctw, Addr, am); err != nil {
 				inErr = err
 				return false
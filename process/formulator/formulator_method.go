@@ -60,9 +60,13 @@ func (p *Formulator) GetStakingAmount(loader types.Loader, HyperAddress common.A
 }
 
 // AddStakingAmount adds staking amount of the address at the hyper formulator
-func (p *Formulator) AddStakingAmount(ctw *types.ContextWrapper, HyperAddress common.Address, StakingAddress common.Address, StakingAmount *amount.Amount) {
+func (p *Formulator) AddStakingAmount(ctw *types.ContextWrapper, HyperAddress common.Address, StakingAddress common.Address, StakingAmount *amount.Amount) error {
 	ctw = types.SwitchContextWrapper(p.pid, ctw)
 
+	if err := p.vault.AddStakingAmount(ctw, StakingAmount); err != nil {
+		return err
+	}
+
 	if ns := ctw.AccountData(HyperAddress, toStakingAmountNumberKey(StakingAddress)); len(ns) == 0 {
 		var Count uint32
 		if bs := ctw.AccountData(HyperAddress, tagStakingAmountCount); len(bs) > 0 {
@@ -74,6 +78,7 @@ func (p *Formulator) AddStakingAmount(ctw *types.ContextWrapper, HyperAddress co
 		ctw.SetAccountData(HyperAddress, tagStakingAmountCount, util.Uint32ToBytes(Count))
 	}
 	ctw.SetAccountData(HyperAddress, toStakingAmountKey(StakingAddress), p.GetStakingAmount(ctw, HyperAddress, StakingAddress).Add(StakingAmount).Bytes())
+	return nil
 }
 
 func (p *Formulator) subStakingAmount(ctw *types.ContextWrapper, HyperAddress common.Address, StakingAddress common.Address, am *amount.Amount) error {
@@ -81,6 +86,9 @@ func (p *Formulator) subStakingAmount(ctw *types.ContextWrapper, HyperAddress co
 	if total.Less(am) {
 		return ErrMinusBalance
 	}
+	if err := p.vault.SubStakingAmount(ctw, am); err != nil {
+		return err
+	}
 	//log.Println("SubBalance", ctw.TargetHeight(), addr.String(), am.String(), p.Balance(ctw, addr).Sub(am).String())
 
 	total = total.Sub(am)
@@ -297,6 +305,9 @@ func (p *Formulator) GetUnstakingAmountMap(loader types.Loader, addr common.Addr
 }
 
 func (p *Formulator) addUnstakingAmount(ctw *types.ContextWrapper, HyperAddr common.Address, addr common.Address, UnstakedHeight uint32, am *amount.Amount) error {
+	if err := p.vault.AddUnstakingAmount(ctw, am); err != nil {
+		return err
+	}
 	if ns := ctw.ProcessData(toUnstakingAmountNumberKey(UnstakedHeight, addr)); len(ns) == 0 {
 		var Count uint32
 		if bs := ctw.ProcessData(toUnstakingAmountCountKey(UnstakedHeight)); len(bs) > 0 {
@@ -339,6 +350,9 @@ func (p *Formulator) subUnstakingAmount(ctw *types.ContextWrapper, HyperAddr com
 	if sum.Less(am) {
 		return ErrMinustUnstakingAmount
 	}
+	if err := p.vault.SubUnstakingAmount(ctw, am); err != nil {
+		return err
+	}
 	if sum.IsZero() {
 		mp.Delete(HyperAddr)
 	} else {
@@ -475,3 +489,320 @@ func (p *Formulator) GetTransmutePolicy(loader types.Loader) (*TransmutePolicy,
 	}
 	return policy, nil
 }
+
+// GetSlashPolicy returns the slash policy
+func (p *Formulator) GetSlashPolicy(loader types.Loader) (*SlashPolicy, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	bs := lw.ProcessData(tagSlashPolicy)
+	if len(bs) == 0 {
+		return nil, ErrNotExistSlashPolicy
+	}
+
+	policy := &SlashPolicy{}
+	if err := encoding.Unmarshal(bs, &policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetSlashedHeight returns the height when the formulator is slashed
+func (p *Formulator) GetSlashedHeight(loader types.Loader, addr common.Address) (uint32, bool) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(addr, tagSlashedHeight); len(bs) > 0 {
+		return util.BytesToUint32(bs), true
+	} else {
+		return 0, false
+	}
+}
+
+func (p *Formulator) slash(ctw *types.ContextWrapper, frAcc *FormulatorAccount, EvidenceHeight uint32, Ratio1000 uint32) (*amount.Amount, *amount.Amount, error) {
+	SlashedAmount := frAcc.Amount.MulC(int64(Ratio1000)).DivC(1000)
+	if err := p.vault.SubFormulatorAmount(ctw, SlashedAmount); err != nil {
+		return nil, nil, err
+	}
+	frAcc.Amount = frAcc.Amount.Sub(SlashedAmount)
+
+	SlashedStakingAmount := amount.NewCoinAmount(0, 0)
+	if frAcc.FormulatorType == HyperFormulatorType {
+		StakingAmountMap, err := p.GetStakingAmountMap(ctw, frAcc.Address())
+		if err != nil {
+			return nil, nil, err
+		}
+		for StakingAddress, StakingAmount := range StakingAmountMap {
+			am := StakingAmount.MulC(int64(Ratio1000)).DivC(1000)
+			if am.IsZero() {
+				continue
+			}
+			if err := p.subStakingAmount(ctw, frAcc.Address(), StakingAddress, am); err != nil {
+				return nil, nil, err
+			}
+			SlashedStakingAmount = SlashedStakingAmount.Add(am)
+		}
+		if frAcc.StakingAmount.Less(SlashedStakingAmount) {
+			return nil, nil, ErrCriticalStakingAmount
+		}
+		frAcc.StakingAmount = frAcc.StakingAmount.Sub(SlashedStakingAmount)
+
+		RedelegatedAmount, err := p.slashRedelegations(ctw, frAcc.Address(), EvidenceHeight, Ratio1000)
+		if err != nil {
+			return nil, nil, err
+		}
+		SlashedStakingAmount = SlashedStakingAmount.Add(RedelegatedAmount)
+	}
+	ctw.SetAccountData(frAcc.Address(), tagSlashedHeight, util.Uint32ToBytes(ctw.TargetHeight()))
+	frAcc.IsRevoked = true
+	return SlashedAmount, SlashedStakingAmount, nil
+}
+
+// burnRevokedFormulator removes the amount and the balance of the revoked formulator that has no heritor from the supply
+func (p *Formulator) burnRevokedFormulator(ctw *types.ContextWrapper, frAcc *FormulatorAccount) error {
+	Balance := p.vault.Balance(ctw, frAcc.Address())
+	if err := p.vault.RemoveBalance(ctw, frAcc.Address()); err != nil {
+		return err
+	}
+	if err := p.vault.AddBurnedAmount(ctw, frAcc.Amount.Add(Balance)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetRedelegateCooldownHeight returns the height until which the address can not redelegate between the hyper formulators
+func (p *Formulator) GetRedelegateCooldownHeight(loader types.Loader, StakingAddress common.Address, HyperFrom common.Address, HyperTo common.Address) uint32 {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(StakingAddress, toRedelegateCooldownKey(HyperFrom, HyperTo)); len(bs) > 0 {
+		return util.BytesToUint32(bs)
+	} else {
+		return 0
+	}
+}
+
+func (p *Formulator) setRedelegateCooldownHeight(ctw *types.ContextWrapper, StakingAddress common.Address, HyperFrom common.Address, HyperTo common.Address, Height uint32) {
+	ctw.SetAccountData(StakingAddress, toRedelegateCooldownKey(HyperFrom, HyperTo), util.Uint32ToBytes(Height))
+}
+
+// redelegateWindowBlocks returns the blocks while the redelegated stake is slashed by the evidence of the source
+// it uses the evidence valid blocks of the slash policy and falls back to the staking unlock required blocks when the evidence does not expire
+func (p *Formulator) redelegateWindowBlocks(loader types.Loader) (uint32, error) {
+	if policy, err := p.GetSlashPolicy(loader); err == nil && policy.EvidenceValidBlocks > 0 {
+		return policy.EvidenceValidBlocks, nil
+	} else if err != nil && err != ErrNotExistSlashPolicy {
+		return 0, err
+	}
+	policy, err := p.GetHyperPolicy(loader)
+	if err != nil {
+		return 0, err
+	}
+	return policy.StakingUnlockRequiredBlocks, nil
+}
+
+// GetRedelegatedHeight returns the height when the stake of the address is redelegated into the hyper formulator
+func (p *Formulator) GetRedelegatedHeight(loader types.Loader, StakingAddress common.Address, HyperAddress common.Address) (uint32, bool) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(StakingAddress, toRedelegatedHeightKey(HyperAddress)); len(bs) > 0 {
+		return util.BytesToUint32(bs), true
+	} else {
+		return 0, false
+	}
+}
+
+// GetRedelegations returns the redelegations out of the hyper formulator that are not pruned yet
+func (p *Formulator) GetRedelegations(loader types.Loader, HyperAddress common.Address) ([]*Redelegation, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	list := []*Redelegation{}
+	if bs := lw.AccountData(HyperAddress, tagRedelegations); len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, &list); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (p *Formulator) setRedelegations(ctw *types.ContextWrapper, HyperAddress common.Address, list []*Redelegation) error {
+	if len(list) == 0 {
+		ctw.SetAccountData(HyperAddress, tagRedelegations, nil)
+		return nil
+	}
+	if bs, err := encoding.Marshal(list); err != nil {
+		return err
+	} else {
+		ctw.SetAccountData(HyperAddress, tagRedelegations, bs)
+	}
+	return nil
+}
+
+// addRedelegation records the stake moved out of the hyper formulator so the evidence of the source still slashes it
+// records older than the window are pruned because the evidence of them is already expired
+func (p *Formulator) addRedelegation(ctw *types.ContextWrapper, HyperFrom common.Address, rd *Redelegation, WindowBlocks uint32) error {
+	list, err := p.GetRedelegations(ctw, HyperFrom)
+	if err != nil {
+		return err
+	}
+	TargetHeight := ctw.TargetHeight()
+	remains := make([]*Redelegation, 0, len(list)+1)
+	for _, v := range list {
+		if v.Height+WindowBlocks >= TargetHeight {
+			remains = append(remains, v)
+		}
+	}
+	remains = append(remains, rd)
+	if err := p.setRedelegations(ctw, HyperFrom, remains); err != nil {
+		return err
+	}
+	ctw.SetAccountData(rd.StakingAddress, toRedelegatedHeightKey(rd.HyperTo), util.Uint32ToBytes(rd.Height))
+	return nil
+}
+
+// slashRedelegations slashes the stake moved out of the hyper formulator from the evidence height at its destination
+func (p *Formulator) slashRedelegations(ctw *types.ContextWrapper, HyperFrom common.Address, EvidenceHeight uint32, Ratio1000 uint32) (*amount.Amount, error) {
+	list, err := p.GetRedelegations(ctw, HyperFrom)
+	if err != nil {
+		return nil, err
+	}
+	SlashedAmount := amount.NewCoinAmount(0, 0)
+	for _, rd := range list {
+		if rd.Height < EvidenceHeight {
+			continue
+		}
+		am := rd.Amount.MulC(int64(Ratio1000)).DivC(1000)
+		if StakingAmount := p.GetStakingAmount(ctw, rd.HyperTo, rd.StakingAddress); StakingAmount.Less(am) {
+			am = StakingAmount
+		}
+		if am.IsZero() {
+			continue
+		}
+		acc, err := ctw.Account(rd.HyperTo)
+		if err != nil {
+			return nil, err
+		}
+		toFrAcc, is := acc.(*FormulatorAccount)
+		if !is {
+			return nil, types.ErrInvalidAccountType
+		}
+		if toFrAcc.StakingAmount.Less(am) {
+			return nil, ErrCriticalStakingAmount
+		}
+		if err := p.subStakingAmount(ctw, rd.HyperTo, rd.StakingAddress, am); err != nil {
+			return nil, err
+		}
+		toFrAcc.StakingAmount = toFrAcc.StakingAmount.Sub(am)
+		SlashedAmount = SlashedAmount.Add(am)
+	}
+	if err := p.setRedelegations(ctw, HyperFrom, nil); err != nil {
+		return nil, err
+	}
+	return SlashedAmount, nil
+}
+
+// moveStakingAmountSnapshot moves the staking amount of the last reward snapshot between the hyper formulators
+// the moved amount is limited by the snapshot of the source so a fresh staking can not earn the reward of the current period
+// the accumulated staking power is kept at the source because it is paid by the stacked reward of the source
+func (p *Formulator) moveStakingAmountSnapshot(ctw *types.ContextWrapper, HyperFrom common.Address, HyperTo common.Address, StakingAddress common.Address, am *amount.Amount) error {
+	FromAmountMap := types.NewAddressAmountMap()
+	if bs := ctw.AccountData(HyperFrom, tagStakingAmountMap); len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, &FromAmountMap); err != nil {
+			return err
+		}
+	}
+	PrevAmount, has := FromAmountMap.Get(StakingAddress)
+	if !has || PrevAmount.IsZero() {
+		return nil
+	}
+	Moved := am
+	if PrevAmount.Less(Moved) {
+		Moved = PrevAmount
+	}
+	if PrevAmount.Equal(Moved) {
+		FromAmountMap.Delete(StakingAddress)
+	} else {
+		FromAmountMap.Put(StakingAddress, PrevAmount.Sub(Moved))
+	}
+
+	ToAmountMap := types.NewAddressAmountMap()
+	if bs := ctw.AccountData(HyperTo, tagStakingAmountMap); len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, &ToAmountMap); err != nil {
+			return err
+		}
+	}
+	if ToAmount, has := ToAmountMap.Get(StakingAddress); has {
+		ToAmountMap.Put(StakingAddress, ToAmount.Add(Moved))
+	} else {
+		ToAmountMap.Put(StakingAddress, Moved)
+	}
+
+	if bs, err := encoding.Marshal(FromAmountMap); err != nil {
+		return err
+	} else {
+		ctw.SetAccountData(HyperFrom, tagStakingAmountMap, bs)
+	}
+	if bs, err := encoding.Marshal(ToAmountMap); err != nil {
+		return err
+	} else {
+		ctw.SetAccountData(HyperTo, tagStakingAmountMap, bs)
+	}
+	return nil
+}
+
+// seedSupply scans formulator accounts and pending unstaking amounts to seed totals of the vault
+// The unstaking amount is unlocked StakingUnlockRequiredBlocks later, so pending ones are in the window from the target height
+func (p *Formulator) seedSupply(ctw *types.ContextWrapper) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	addrs, err := ctw.Addresses()
+	if err != nil {
+		return err
+	}
+	FormulatorAmount := amount.NewCoinAmount(0, 0)
+	StakingAmount := amount.NewCoinAmount(0, 0)
+	for _, addr := range addrs {
+		acc, err := ctw.Account(addr)
+		if err != nil {
+			if err == types.ErrDeletedAccount {
+				continue
+			}
+			return err
+		}
+		frAcc, is := acc.(*FormulatorAccount)
+		if !is {
+			continue
+		}
+		FormulatorAmount = FormulatorAmount.Add(frAcc.Amount)
+		if frAcc.FormulatorType == HyperFormulatorType {
+			PowerMap, err := p.GetStakingAmountMap(ctw, addr)
+			if err != nil {
+				return err
+			}
+			for _, am := range PowerMap {
+				StakingAmount = StakingAmount.Add(am)
+			}
+		}
+	}
+
+	policy := &HyperPolicy{}
+	if err := encoding.Unmarshal(ctw.ProcessData(tagHyperPolicy), &policy); err != nil {
+		return err
+	}
+	UnstakingAmount := amount.NewCoinAmount(0, 0)
+	for h := ctw.TargetHeight(); h <= ctw.TargetHeight()+policy.StakingUnlockRequiredBlocks; h++ {
+		if bs := ctw.ProcessData(toUnstakingAmountCountKey(h)); len(bs) > 0 {
+			Count := util.BytesToUint32(bs)
+			for i := uint32(0); i < Count; i++ {
+				var addr common.Address
+				copy(addr[:], ctw.ProcessData(toUnstakingAmountReverseKey(h, i)))
+				mp, err := p.GetUnstakingAmountMap(ctw, addr, h)
+				if err != nil {
+					return err
+				}
+				mp.EachAll(func(HyperAddr common.Address, am *amount.Amount) bool {
+					UnstakingAmount = UnstakingAmount.Add(am)
+					return true
+				})
+			}
+		}
+	}
+	return p.vault.SeedFormulatorSupply(ctw, FormulatorAmount, StakingAmount, UnstakingAmount)
+}
@@ -284,3 +284,38 @@ func (pc *TransmutePolicy) MarshalJSON() ([]byte, error) {
 	buffer.WriteString(`}`)
 	return buffer.Bytes(), nil
 }
+
+// SlashPolicy defines a slash policy
+type SlashPolicy struct {
+	SlashRatio1000      uint32
+	ReporterRatio1000   uint32
+	EvidenceValidBlocks uint32
+}
+
+// MarshalJSON is a marshaler function
+func (pc *SlashPolicy) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"slash_ratio_1000":`)
+	if bs, err := json.Marshal(pc.SlashRatio1000); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"reporter_ratio_1000":`)
+	if bs, err := json.Marshal(pc.ReporterRatio1000); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"evidence_valid_blocks":`)
+	if bs, err := json.Marshal(pc.EvidenceValidBlocks); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
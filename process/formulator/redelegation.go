@@ -0,0 +1,14 @@
+package formulator
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// Redelegation is the stake moved out of the hyper formulator by the redelegate transaction
+type Redelegation struct {
+	StakingAddress common.Address
+	HyperTo        common.Address
+	Amount         *amount.Amount
+	Height         uint32
+}
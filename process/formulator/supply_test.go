@@ -0,0 +1,341 @@
+package formulator
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+const supplyUnlockBlocks = 10
+
+type supplyFixture struct {
+	p       *Formulator
+	vp      *vault.Vault
+	ctx     *types.Context
+	Hypers  []common.Address
+	Alpha   common.Address
+	Stakers []common.Address
+	Gone    common.Address
+	Minted  *amount.Amount
+}
+
+// newSupplyFixture creates two hyper formulators, an alpha formulator, two stakers and a deleted account
+func newSupplyFixture(t *testing.T, Upgraded bool) *supplyFixture {
+	p := NewFormulator(3)
+	vp := vault.NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), vp, p); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := types.NewEmptyContext()
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+	if Upgraded {
+		if err := vp.InitUpgrades(ctw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.InitPolicy(ctw,
+		&RewardPolicy{
+			RewardPerBlock:       amount.NewCoinAmount(0, 0),
+			PayRewardEveryBlocks: 1000000,
+		},
+		&AlphaPolicy{AlphaCreationAmount: amount.NewCoinAmount(200, 0)},
+		&SigmaPolicy{},
+		&OmegaPolicy{},
+		&HyperPolicy{
+			HyperCreationAmount:         amount.NewCoinAmount(1000, 0),
+			StakingUnlockRequiredBlocks: supplyUnlockBlocks,
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	fx := &supplyFixture{
+		p:      p,
+		vp:     vp,
+		ctx:    ctx,
+		Alpha:  common.NewAddress(0, 10, 0),
+		Gone:   common.NewAddress(0, 200, 0),
+		Minted: amount.NewCoinAmount(0, 0),
+	}
+	frAccs := []*FormulatorAccount{}
+	for i := 1; i <= 2; i++ {
+		frAccs = append(frAccs, &FormulatorAccount{
+			Address_:       common.NewAddress(0, uint16(i), 0),
+			Name_:          "hyper" + string(rune('0'+i)),
+			FormulatorType: HyperFormulatorType,
+			Amount:         amount.NewCoinAmount(1000, 0),
+			StakingAmount:  amount.NewCoinAmount(0, 0),
+			Policy:         &ValidatorPolicy{MinimumStaking: amount.NewCoinAmount(0, 0)},
+		})
+		fx.Hypers = append(fx.Hypers, common.NewAddress(0, uint16(i), 0))
+	}
+	frAccs = append(frAccs, &FormulatorAccount{
+		Address_:       fx.Alpha,
+		Name_:          "alpha",
+		FormulatorType: AlphaFormulatorType,
+		Amount:         amount.NewCoinAmount(200, 0),
+	})
+	for _, frAcc := range frAccs {
+		if err := ctw.CreateAccount(frAcc); err != nil {
+			t.Fatal(err)
+		}
+		if err := vp.AddFormulatorAmount(ctw, frAcc.Amount); err != nil {
+			t.Fatal(err)
+		}
+		fx.Minted = fx.Minted.Add(frAcc.Amount)
+	}
+	for i := 1; i <= 2; i++ {
+		addr := common.NewAddress(0, uint16(100+i), 0)
+		if err := ctw.CreateAccount(&vault.SingleAccount{
+			Address_: addr,
+			Name_:    "staker" + string(rune('0'+i)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		fx.mint(t, ctw, addr, amount.NewCoinAmount(1000, 0))
+		fx.Stakers = append(fx.Stakers, addr)
+	}
+	if err := ctw.CreateAccount(&vault.SingleAccount{
+		Address_: fx.Gone,
+		Name_:    "gone",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vp.AddLockedBalance(ctw, fx.Stakers[1], 8, amount.NewCoinAmount(50, 0)); err != nil {
+		t.Fatal(err)
+	}
+	fx.Minted = fx.Minted.Add(amount.NewCoinAmount(50, 0))
+
+	fx.stake(t, ctw, fx.Hypers[0], fx.Stakers[0], amount.NewCoinAmount(300, 0))
+	fx.stake(t, ctw, fx.Hypers[1], fx.Stakers[1], amount.NewCoinAmount(200, 0))
+	fx.unstake(t, ctw, fx.Hypers[0], fx.Stakers[0], amount.NewCoinAmount(100, 0))
+	return fx
+}
+
+func (fx *supplyFixture) mint(t *testing.T, ctw *types.ContextWrapper, addr common.Address, am *amount.Amount) {
+	if err := fx.vp.AddBalance(ctw, addr, am); err != nil {
+		t.Fatal(err)
+	}
+	fx.Minted = fx.Minted.Add(am)
+}
+
+func (fx *supplyFixture) hyper(t *testing.T, ctw *types.ContextWrapper, addr common.Address) *FormulatorAccount {
+	acc, err := ctw.Account(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return acc.(*FormulatorAccount)
+}
+
+func (fx *supplyFixture) stake(t *testing.T, ctw *types.ContextWrapper, HyperAddr common.Address, addr common.Address, am *amount.Amount) {
+	if err := fx.vp.SubBalance(ctw, addr, am); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.p.AddStakingAmount(ctw, HyperAddr, addr, am); err != nil {
+		t.Fatal(err)
+	}
+	frAcc := fx.hyper(t, ctw, HyperAddr)
+	frAcc.StakingAmount = frAcc.StakingAmount.Add(am)
+}
+
+func (fx *supplyFixture) unstake(t *testing.T, ctw *types.ContextWrapper, HyperAddr common.Address, addr common.Address, am *amount.Amount) {
+	if err := fx.p.subStakingAmount(ctw, HyperAddr, addr, am); err != nil {
+		t.Fatal(err)
+	}
+	frAcc := fx.hyper(t, ctw, HyperAddr)
+	frAcc.StakingAmount = frAcc.StakingAmount.Sub(am)
+	if err := fx.p.addUnstakingAmount(ctw, HyperAddr, addr, ctw.TargetHeight()+supplyUnlockBlocks, am); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scanSupply recomputes every total except fees from the full account scan and the pending unstaking window
+func (fx *supplyFixture) scanSupply(t *testing.T) *vault.Supply {
+	ctx := fx.ctx
+	addrs, err := ctx.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &vault.Supply{
+		Balance:          amount.NewCoinAmount(0, 0),
+		LockedBalance:    amount.NewCoinAmount(0, 0),
+		FormulatorAmount: amount.NewCoinAmount(0, 0),
+		StakingAmount:    amount.NewCoinAmount(0, 0),
+		UnstakingAmount:  amount.NewCoinAmount(0, 0),
+	}
+	for _, addr := range addrs {
+		for _, UnlockedHeight := range fx.vp.LockedBalanceHeights(ctx, addr) {
+			s.LockedBalance = s.LockedBalance.Add(fx.vp.LockedBalance(ctx, addr, UnlockedHeight))
+		}
+		for h := ctx.TargetHeight(); h <= ctx.TargetHeight()+supplyUnlockBlocks; h++ {
+			if mp, err := fx.p.GetUnstakingAmountMap(ctx, addr, h); err == nil {
+				mp.EachAll(func(HyperAddr common.Address, am *amount.Amount) bool {
+					s.UnstakingAmount = s.UnstakingAmount.Add(am)
+					return true
+				})
+			} else if err != ErrNotExistUnstakingAmount {
+				t.Fatal(err)
+			}
+		}
+		if has, err := ctx.HasAccount(addr); err != nil {
+			if err == types.ErrDeletedAccount {
+				continue
+			}
+			t.Fatal(err)
+		} else if !has {
+			continue
+		}
+		s.Balance = s.Balance.Add(fx.vp.Balance(ctx, addr))
+
+		acc, err := ctx.Account(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frAcc, is := acc.(*FormulatorAccount)
+		if !is {
+			continue
+		}
+		s.FormulatorAmount = s.FormulatorAmount.Add(frAcc.Amount)
+		if frAcc.FormulatorType == HyperFormulatorType {
+			StakingAmountMap, err := fx.p.GetStakingAmountMap(ctx, addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			Sum := amount.NewCoinAmount(0, 0)
+			for _, am := range StakingAmountMap {
+				Sum = Sum.Add(am)
+			}
+			if !Sum.Equal(frAcc.StakingAmount) {
+				t.Fatalf("staking amount of %v is %v, scanned %v", addr, frAcc.StakingAmount, Sum)
+			}
+			s.StakingAmount = s.StakingAmount.Add(Sum)
+		}
+	}
+	return s
+}
+
+func (fx *supplyFixture) checkSupply(t *testing.T) {
+	t.Helper()
+	s, err := fx.vp.Supply(fx.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanned := fx.scanSupply(t)
+	for _, v := range []struct {
+		name     string
+		total    *amount.Amount
+		expected *amount.Amount
+	}{
+		{"balance", s.Balance, scanned.Balance},
+		{"locked balance", s.LockedBalance, scanned.LockedBalance},
+		{"formulator amount", s.FormulatorAmount, scanned.FormulatorAmount},
+		{"staking amount", s.StakingAmount, scanned.StakingAmount},
+		{"unstaking amount", s.UnstakingAmount, scanned.UnstakingAmount},
+		{"held balance", s.HeldBalance, fx.vp.HeldBalance(fx.ctx)},
+		{"collected fee", s.CollectedFee, fx.vp.CollectedFee(types.NewLoaderWrapper(fx.vp.ID(), fx.ctx))},
+	} {
+		if !v.total.Equal(v.expected) {
+			t.Fatalf("%v total %v, scanned %v", v.name, v.total, v.expected)
+		}
+	}
+	if !s.TotalSupply.Add(s.BurnedAmount).Equal(fx.Minted) {
+		t.Fatalf("total supply %v with burned %v, minted %v", s.TotalSupply, s.BurnedAmount, fx.Minted)
+	}
+}
+
+func TestSupply_FormulatorTotals(t *testing.T) {
+	tests := []struct {
+		name             string
+		upgraded         bool
+		activationHeight uint32
+	}{
+		{"upgraded at the genesis", true, 0},
+		{"seeded at the activation height", false, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newSupplyFixture(t, tt.upgraded)
+			if !tt.upgraded {
+				ctw := types.NewContextWrapper(fx.vp.ID(), fx.ctx)
+				tx := &vault.ScheduleUpgrade{Kind: vault.SupplyUpgrade, ActivationHeight: tt.activationHeight}
+				if err := tx.Execute(fx.vp, ctw, 0); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := fx.vp.Supply(fx.ctx); err != vault.ErrNotUpgraded {
+					t.Fatalf("expected %v but %v", vault.ErrNotUpgraded, err)
+				}
+			} else {
+				fx.checkSupply(t)
+			}
+
+			for h := uint32(1); h <= 15; h++ {
+				fx.ctx = processtest.Advance(fx.ctx, h)
+				ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+				if err := fx.vp.BeforeExecuteTransactions(types.SwitchContextWrapper(fx.vp.ID(), ctw)); err != nil {
+					t.Fatal(err)
+				}
+				if err := fx.p.BeforeExecuteTransactions(ctw); err != nil {
+					t.Fatal(err)
+				}
+
+				switch h {
+				case 5:
+					fx.stake(t, ctw, fx.Hypers[0], fx.Stakers[1], amount.NewCoinAmount(100, 0))
+					fx.unstake(t, ctw, fx.Hypers[1], fx.Stakers[1], amount.NewCoinAmount(50, 0))
+				case 6:
+					frAcc := fx.hyper(t, ctw, fx.Hypers[1])
+					SlashedAmount, SlashedStakingAmount, err := fx.p.slash(ctw, frAcc, h, 500)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := fx.vp.AddCollectedFee(ctw, SlashedAmount.Add(SlashedStakingAmount)); err != nil {
+						t.Fatal(err)
+					}
+					if err := fx.p.addRevokedFormulator(ctw, fx.Hypers[1], 12, fx.Stakers[0]); err != nil {
+						t.Fatal(err)
+					}
+				case 7:
+					if acc, err := ctw.Account(fx.Gone); err != nil {
+						t.Fatal(err)
+					} else if err := ctw.DeleteAccount(acc); err != nil {
+						t.Fatal(err)
+					}
+					if err := fx.p.addRevokedFormulator(ctw, fx.Alpha, 12, fx.Gone); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				b := &types.Block{}
+				b.Header.Height = h
+				b.Header.Generator = fx.Hypers[0]
+				if err := fx.vp.AfterExecuteTransactions(b, types.SwitchContextWrapper(fx.vp.ID(), ctw)); err != nil {
+					t.Fatal(err)
+				}
+				if err := fx.p.AfterExecuteTransactions(b, ctw); err != nil {
+					t.Fatal(err)
+				}
+				if h >= tt.activationHeight {
+					fx.checkSupply(t)
+				}
+			}
+
+			// the revoked alpha without the heritor is burned and every pending amount is flushed
+			s, err := fx.vp.Supply(fx.ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !s.BurnedAmount.Equal(amount.NewCoinAmount(200, 0)) {
+				t.Fatalf("burned amount %v", s.BurnedAmount)
+			}
+			if !s.UnstakingAmount.IsZero() || !s.LockedBalance.IsZero() {
+				t.Fatalf("pending amounts are remained %v, %v", s.UnstakingAmount, s.LockedBalance)
+			}
+		})
+	}
+}
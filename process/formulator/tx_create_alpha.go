@@ -91,6 +91,9 @@ func (tx *CreateAlpha) Execute(p types.Process, ctw *types.ContextWrapper, index
 		if err := sp.vault.SubBalance(ctw, tx.From(), policy.AlphaCreationAmount); err != nil {
 			return err
 		}
+		if err := sp.vault.AddFormulatorAmount(ctw, policy.AlphaCreationAmount); err != nil {
+			return err
+		}
 
 		acc := &FormulatorAccount{
 			Address_:       common.NewAddress(ctw.TargetHeight(), index, 0),
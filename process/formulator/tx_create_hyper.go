@@ -83,6 +83,9 @@ func (tx *CreateHyper) Execute(p types.Process, ctw *types.ContextWrapper, index
 	if err := sp.vault.SubBalance(ctw, tx.From(), policy.HyperCreationAmount); err != nil {
 		return err
 	}
+	if err := sp.vault.AddFormulatorAmount(ctw, policy.HyperCreationAmount); err != nil {
+		return err
+	}
 
 	acc := &FormulatorAccount{
 		Address_:       common.NewAddress(ctw.TargetHeight(), index, 0),
@@ -112,6 +112,9 @@ func (tx *CreateSigma) Execute(p types.Process, ctw *types.ContextWrapper, index
 					return err
 				}
 				frAcc.Amount = frAcc.Amount.Add(subAcc.Amount)
+				if err := sp.vault.ReleaseAccountNames(ctw, subAcc); err != nil {
+					return err
+				}
 				if err := ctw.DeleteAccount(subAcc); err != nil {
 					return err
 				}
@@ -0,0 +1,218 @@
+package formulator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Redelegate is used to move staking coin from the hyper formulator to another without unstaking
+// the moved stake stays slashed by the evidence of the source until the evidence valid blocks are passed
+type Redelegate struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	HyperFrom  common.Address
+	HyperTo    common.Address
+	Amount     *amount.Amount
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Redelegate) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Redelegate) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Redelegate) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Redelegate) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Redelegate) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Formulator)
+
+	if tx.Amount.Less(amount.COIN) {
+		return ErrInvalidStakingAmount
+	}
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if tx.HyperFrom == tx.HyperTo {
+		return ErrSameHyperFormulator
+	}
+	if tx.From() == tx.HyperTo {
+		return ErrInvalidStakingAddress
+	}
+
+	fromFrAcc, err := loadHyperFormulator(loader, tx.HyperFrom)
+	if err != nil {
+		return err
+	}
+	toFrAcc, err := loadHyperFormulator(loader, tx.HyperTo)
+	if err != nil {
+		return err
+	}
+	if !toFrAcc.Policy.MinimumStaking.IsZero() && tx.Amount.Less(toFrAcc.Policy.MinimumStaking) {
+		return ErrInvalidStakingAmount
+	}
+
+	if sp.GetStakingAmount(loader, tx.HyperFrom, tx.From()).Less(tx.Amount) {
+		return ErrInsufficientStakingAmount
+	}
+	if fromFrAcc.StakingAmount.Less(tx.Amount) {
+		return ErrCriticalStakingAmount
+	}
+	if loader.TargetHeight() <= sp.GetRedelegateCooldownHeight(loader, tx.From(), tx.HyperFrom, tx.HyperTo) {
+		return ErrRedelegateCooldown
+	}
+	WindowBlocks, err := sp.redelegateWindowBlocks(loader)
+	if err != nil {
+		return err
+	}
+	if RedelegatedHeight, has := sp.GetRedelegatedHeight(loader, tx.From(), tx.HyperFrom); has && loader.TargetHeight() <= RedelegatedHeight+WindowBlocks {
+		return ErrRedelegateCooldown
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func loadHyperFormulator(loader types.LoaderWrapper, addr common.Address) (*FormulatorAccount, error) {
+	acc, err := loader.Account(addr)
+	if err != nil {
+		return nil, err
+	}
+	frAcc, is := acc.(*FormulatorAccount)
+	if !is {
+		return nil, types.ErrInvalidAccountType
+	}
+	if frAcc.FormulatorType != HyperFormulatorType {
+		return nil, types.ErrInvalidAccountType
+	}
+	if frAcc.IsRevoked {
+		return nil, ErrRevokedFormulator
+	}
+	return frAcc, nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Redelegate) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Formulator)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		acc, err := ctw.Account(tx.HyperFrom)
+		if err != nil {
+			return err
+		}
+		fromFrAcc := acc.(*FormulatorAccount)
+
+		acc, err = ctw.Account(tx.HyperTo)
+		if err != nil {
+			return err
+		}
+		toFrAcc := acc.(*FormulatorAccount)
+
+		if err := sp.subStakingAmount(ctw, tx.HyperFrom, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+		fromFrAcc.StakingAmount = fromFrAcc.StakingAmount.Sub(tx.Amount)
+
+		if err := sp.AddStakingAmount(ctw, tx.HyperTo, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+		toFrAcc.StakingAmount = toFrAcc.StakingAmount.Add(tx.Amount)
+
+		if err := sp.moveStakingAmountSnapshot(ctw, tx.HyperFrom, tx.HyperTo, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+
+		WindowBlocks, err := sp.redelegateWindowBlocks(ctw)
+		if err != nil {
+			return err
+		}
+		sp.setRedelegateCooldownHeight(ctw, tx.From(), tx.HyperFrom, tx.HyperTo, ctw.TargetHeight()+WindowBlocks)
+		if err := sp.addRedelegation(ctw, tx.HyperFrom, &Redelegation{
+			StakingAddress: tx.From(),
+			HyperTo:        tx.HyperTo,
+			Amount:         tx.Amount,
+			Height:         ctw.TargetHeight(),
+		}, WindowBlocks); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Redelegate) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"hyper_from":`)
+	if bs, err := tx.HyperFrom.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"hyper_to":`)
+	if bs, err := tx.HyperTo.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
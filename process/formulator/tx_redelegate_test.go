@@ -0,0 +1,208 @@
+package formulator
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+type redelegateFixture struct {
+	p      *Formulator
+	ctx    *types.Context
+	Hypers []common.Address
+	Staker common.Address
+	Key    common.PublicHash
+}
+
+// newRedelegateFixture creates three hyper formulators and a staker that stakes 100 coins at the first one
+func newRedelegateFixture(t *testing.T, EvidenceValidBlocks uint32) *redelegateFixture {
+	p := NewFormulator(3)
+	vp := vault.NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), vp, p); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := types.NewEmptyContext()
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+	if bs, err := encoding.Marshal(&HyperPolicy{
+		HyperCreationAmount:         amount.NewCoinAmount(1000, 0),
+		StakingUnlockRequiredBlocks: 100,
+	}); err != nil {
+		t.Fatal(err)
+	} else {
+		ctw.SetProcessData(tagHyperPolicy, bs)
+	}
+	if bs, err := encoding.Marshal(&SlashPolicy{
+		SlashRatio1000:      500,
+		EvidenceValidBlocks: EvidenceValidBlocks,
+	}); err != nil {
+		t.Fatal(err)
+	} else {
+		ctw.SetProcessData(tagSlashPolicy, bs)
+	}
+
+	fx := &redelegateFixture{
+		p:      p,
+		Staker: common.NewAddress(0, 100, 0),
+		Key:    common.PublicHash{1},
+	}
+	for i := 1; i <= 3; i++ {
+		acc := &FormulatorAccount{
+			Address_:       common.NewAddress(0, uint16(i), 0),
+			Name_:          "hyper" + string(rune('0'+i)),
+			FormulatorType: HyperFormulatorType,
+			Amount:         amount.NewCoinAmount(1000, 0),
+			StakingAmount:  amount.NewCoinAmount(0, 0),
+			Policy:         &ValidatorPolicy{MinimumStaking: amount.NewCoinAmount(0, 0)},
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := vp.AddFormulatorAmount(ctw, acc.Amount); err != nil {
+			t.Fatal(err)
+		}
+		fx.Hypers = append(fx.Hypers, acc.Address())
+	}
+	if err := ctw.CreateAccount(&vault.SingleAccount{
+		Address_: fx.Staker,
+		Name_:    "staker",
+		KeyHash:  fx.Key,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vp.AddBalance(ctw, fx.Staker, amount.NewCoinAmount(10, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddStakingAmount(ctw, fx.Hypers[0], fx.Staker, amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := ctw.Account(fx.Hypers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc.(*FormulatorAccount).StakingAmount = amount.NewCoinAmount(100, 0)
+	fx.ctx = ctx
+	return fx
+}
+
+func (fx *redelegateFixture) redelegate(t *testing.T, From int, To int, Coin int64) error {
+	tx := &Redelegate{
+		Seq_:      fx.ctx.Seq(fx.Staker) + 1,
+		From_:     fx.Staker,
+		HyperFrom: fx.Hypers[From],
+		HyperTo:   fx.Hypers[To],
+		Amount:    amount.NewCoinAmount(uint64(Coin), 0),
+	}
+	if err := tx.Validate(fx.p, types.NewLoaderWrapper(fx.p.ID(), fx.ctx), []common.PublicHash{fx.Key}); err != nil {
+		return err
+	}
+	ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+	if err := tx.Execute(fx.p, ctw, 0); err != nil {
+		t.Fatal(err)
+	}
+	fx.ctx.AddSeq(fx.Staker)
+	return nil
+}
+
+func (fx *redelegateFixture) stakingAmount(t *testing.T, Hyper int) (*amount.Amount, *amount.Amount) {
+	acc, err := fx.ctx.Account(fx.Hypers[Hyper])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fx.p.GetStakingAmount(fx.ctx, fx.Hypers[Hyper], fx.Staker), acc.(*FormulatorAccount).StakingAmount
+}
+
+func TestRedelegate_Cooldown(t *testing.T) {
+	fx := newRedelegateFixture(t, 10)
+	fx.ctx = processtest.Advance(fx.ctx, 5)
+
+	if err := fx.redelegate(t, 0, 1, 40); err != nil {
+		t.Fatal(err)
+	}
+	if am, total := fx.stakingAmount(t, 1); !am.Equal(amount.NewCoinAmount(40, 0)) || !total.Equal(am) {
+		t.Fatalf("invalid destination staking amount %v, %v", am, total)
+	}
+	if am, total := fx.stakingAmount(t, 0); !am.Equal(amount.NewCoinAmount(60, 0)) || !total.Equal(am) {
+		t.Fatalf("invalid source staking amount %v, %v", am, total)
+	}
+
+	tests := []struct {
+		name   string
+		height uint32
+		from   int
+		to     int
+		err    error
+	}{
+		{"same pair in reverse", 6, 1, 0, ErrRedelegateCooldown},
+		{"second hop in window", 6, 1, 2, ErrRedelegateCooldown},
+		{"second hop at window end", 15, 1, 2, ErrRedelegateCooldown},
+		{"same pair at window end", 15, 0, 1, ErrRedelegateCooldown},
+		{"same pair after window", 16, 0, 1, nil},
+		{"other pair from source", 6, 0, 2, nil},
+		{"same hyper", 6, 0, 0, ErrSameHyperFormulator},
+		{"second hop after window", 16, 1, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &redelegateFixture{
+				p:      fx.p,
+				ctx:    processtest.Advance(fx.ctx, tt.height),
+				Hypers: fx.Hypers,
+				Staker: fx.Staker,
+				Key:    fx.Key,
+			}
+			if err := sub.redelegate(t, tt.from, tt.to, 10); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRedelegate_SlashedBySourceEvidence(t *testing.T) {
+	tests := []struct {
+		name           string
+		evidenceHeight uint32
+		source         *amount.Amount
+		destination    *amount.Amount
+	}{
+		{"evidence before the redelegation", 3, amount.NewCoinAmount(30, 0), amount.NewCoinAmount(20, 0)},
+		{"evidence at the redelegation", 5, amount.NewCoinAmount(30, 0), amount.NewCoinAmount(20, 0)},
+		{"evidence after the redelegation", 6, amount.NewCoinAmount(30, 0), amount.NewCoinAmount(40, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newRedelegateFixture(t, 10)
+			fx.ctx = processtest.Advance(fx.ctx, 5)
+			if err := fx.redelegate(t, 0, 1, 40); err != nil {
+				t.Fatal(err)
+			}
+			fx.ctx = processtest.Advance(fx.ctx, 8)
+
+			ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+			acc, err := ctw.Account(fx.Hypers[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, SlashedStakingAmount, err := fx.p.slash(ctw, acc.(*FormulatorAccount), tt.evidenceHeight, 500)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if am, total := fx.stakingAmount(t, 0); !am.Equal(tt.source) || !total.Equal(am) {
+				t.Fatalf("invalid source staking amount %v, %v", am, total)
+			}
+			if am, total := fx.stakingAmount(t, 1); !am.Equal(tt.destination) || !total.Equal(am) {
+				t.Fatalf("invalid destination staking amount %v, %v", am, total)
+			}
+			Slashed := amount.NewCoinAmount(100, 0).Sub(tt.source).Sub(tt.destination)
+			if !SlashedStakingAmount.Equal(Slashed) {
+				t.Fatalf("invalid slashed staking amount %v, expected %v", SlashedStakingAmount, Slashed)
+			}
+		})
+	}
+}
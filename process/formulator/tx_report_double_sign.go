@@ -0,0 +1,227 @@
+package formulator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// ReportDoubleSign is used to report two conflicting headers signed by the same generator
+type ReportDoubleSign struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	HeaderA    types.Header
+	SignatureA common.Signature
+	HeaderB    types.Header
+	SignatureB common.Signature
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *ReportDoubleSign) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *ReportDoubleSign) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *ReportDoubleSign) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *ReportDoubleSign) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *ReportDoubleSign) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Formulator)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	policy, err := sp.GetSlashPolicy(loader)
+	if err != nil {
+		return err
+	}
+	if err := tx.validateEvidence(loader.ChainID()); err != nil {
+		return err
+	}
+	if policy.EvidenceValidBlocks > 0 && tx.HeaderA.Height+policy.EvidenceValidBlocks < loader.TargetHeight() {
+		return ErrExpiredDoubleSignEvidence
+	}
+
+	acc, err := loader.Account(tx.HeaderA.Generator)
+	if err != nil {
+		return err
+	}
+	frAcc, is := acc.(*FormulatorAccount)
+	if !is {
+		return types.ErrInvalidAccountType
+	}
+	if _, has := sp.GetSlashedHeight(loader, frAcc.Address()); has {
+		return ErrSlashedFormulator
+	}
+	if pubkey, err := common.RecoverPubkey(encoding.Hash(tx.HeaderA), tx.SignatureA); err != nil {
+		return err
+	} else if common.NewPublicHash(pubkey) != frAcc.GenHash {
+		return ErrInvalidDoubleSignEvidence
+	}
+	if pubkey, err := common.RecoverPubkey(encoding.Hash(tx.HeaderB), tx.SignatureB); err != nil {
+		return err
+	} else if common.NewPublicHash(pubkey) != frAcc.GenHash {
+		return ErrInvalidDoubleSignEvidence
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tx *ReportDoubleSign) validateEvidence(ChainID uint8) error {
+	if tx.HeaderA.ChainID != ChainID || tx.HeaderB.ChainID != ChainID {
+		return ErrInvalidDoubleSignEvidence
+	}
+	if tx.HeaderA.Generator != tx.HeaderB.Generator {
+		return ErrInvalidDoubleSignEvidence
+	}
+	if tx.HeaderA.Height != tx.HeaderB.Height {
+		return ErrInvalidDoubleSignEvidence
+	}
+	TimeoutCountA, err := types.DecodeTimeoutCount(tx.HeaderA.ConsensusData)
+	if err != nil {
+		return err
+	}
+	TimeoutCountB, err := types.DecodeTimeoutCount(tx.HeaderB.ConsensusData)
+	if err != nil {
+		return err
+	}
+	if TimeoutCountA != TimeoutCountB {
+		return ErrInvalidDoubleSignEvidence
+	}
+	if encoding.Hash(tx.HeaderA) == encoding.Hash(tx.HeaderB) {
+		return ErrInvalidDoubleSignEvidence
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *ReportDoubleSign) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Formulator)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		policy, err := sp.GetSlashPolicy(ctw)
+		if err != nil {
+			return err
+		}
+		acc, err := ctw.Account(tx.HeaderA.Generator)
+		if err != nil {
+			return err
+		}
+		frAcc := acc.(*FormulatorAccount)
+
+		SlashedAmount, SlashedStakingAmount, err := sp.slash(ctw, frAcc, tx.HeaderA.Height, policy.SlashRatio1000)
+		if err != nil {
+			return err
+		}
+		Total := SlashedAmount.Add(SlashedStakingAmount)
+		ReporterReward := Total.MulC(int64(policy.ReporterRatio1000)).DivC(1000)
+		if !ReporterReward.IsZero() {
+			if err := sp.vault.AddBalance(ctw, tx.From(), ReporterReward); err != nil {
+				return err
+			}
+		}
+		if err := sp.vault.AddCollectedFee(ctw, Total.Sub(ReporterReward)); err != nil {
+			return err
+		}
+
+		ev := &SlashedEvent{
+			Height_:              ctw.TargetHeight(),
+			Index_:               index,
+			Formulator:           frAcc.Address(),
+			Reporter:             tx.From(),
+			EvidenceHeight:       tx.HeaderA.Height,
+			SlashedAmount:        SlashedAmount,
+			SlashedStakingAmount: SlashedStakingAmount,
+			ReporterReward:       ReporterReward,
+		}
+		if err := ctw.EmitEvent(ev); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *ReportDoubleSign) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"header_a":`)
+	if bs, err := json.Marshal(tx.HeaderA); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signature_a":`)
+	if bs, err := tx.SignatureA.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"header_b":`)
+	if bs, err := json.Marshal(tx.HeaderB); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signature_b":`)
+	if bs, err := tx.SignatureB.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
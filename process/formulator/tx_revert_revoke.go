@@ -55,6 +55,9 @@ func (tx *RevertRevoke) Validate(p types.Process, loader types.LoaderWrapper, si
 	if !frAcc.IsRevoked {
 		return ErrNotRevoked
 	}
+	if _, has := sp.GetSlashedHeight(loader, frAcc.Address()); has {
+		return ErrSlashedFormulator
+	}
 	if err := frAcc.Validate(loader, signers); err != nil {
 		return err
 	}
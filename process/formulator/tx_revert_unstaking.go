@@ -100,7 +100,9 @@ func (tx *RevertUnstaking) Execute(p types.Process, ctw *types.ContextWrapper, i
 			return err
 		}
 
-		sp.AddStakingAmount(ctw, tx.HyperFormulator, tx.From(), tx.Amount)
+		if err := sp.AddStakingAmount(ctw, tx.HyperFormulator, tx.From(), tx.Amount); err != nil {
+			return err
+		}
 		frAcc.StakingAmount = frAcc.StakingAmount.Add(tx.Amount)
 		return nil
 	})
@@ -64,7 +64,12 @@ func (tx *Revoke) Validate(p types.Process, loader types.LoaderWrapper, signers
 		return types.ErrInvalidAccountType
 	}
 	if frAcc.IsRevoked {
-		return ErrRevokedFormulator
+		if _, has := sp.GetSlashedHeight(loader, frAcc.Address()); !has {
+			return ErrRevokedFormulator
+		}
+		if _, err := sp.GetRevokedFormulatorHeight(loader, frAcc.Address()); err == nil {
+			return ErrRevokedFormulator
+		}
 	}
 	if err := frAcc.Validate(loader, signers); err != nil {
 		return err
@@ -105,7 +105,9 @@ func (tx *Staking) Execute(p types.Process, ctw *types.ContextWrapper, index uin
 			return err
 		}
 
-		sp.AddStakingAmount(ctw, tx.HyperFormulator, tx.From(), tx.Amount)
+		if err := sp.AddStakingAmount(ctw, tx.HyperFormulator, tx.From(), tx.Amount); err != nil {
+			return err
+		}
 		frAcc.StakingAmount = frAcc.StakingAmount.Add(tx.Amount)
 		return nil
 	})
@@ -160,6 +160,9 @@ func (tx *Transmute) Execute(p types.Process, ctw *types.ContextWrapper, index u
 	if err := encoding.Unmarshal(ctw.ProcessData(tagAlphaPolicy), &policy); err != nil {
 		return err
 	}
+	if err := sp.vault.AddFormulatorAmount(ctw, policy.AlphaCreationAmount); err != nil {
+		return err
+	}
 
 	acc := &FormulatorAccount{
 		Address_:       common.NewAddress(ctw.TargetHeight(), index, 0),
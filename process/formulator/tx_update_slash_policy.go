@@ -0,0 +1,110 @@
+package formulator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/process/admin"
+)
+
+// UpdateSlashPolicy is used to update slash policy
+type UpdateSlashPolicy struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	Policy     *SlashPolicy
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *UpdateSlashPolicy) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *UpdateSlashPolicy) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *UpdateSlashPolicy) From() common.Address {
+	return tx.From_
+}
+
+// Validate validates signatures of the transaction
+func (tx *UpdateSlashPolicy) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Formulator)
+
+	if tx.From() != sp.admin.AdminAddress(loader, p.Name()) {
+		return admin.ErrUnauthorizedTransaction
+	}
+	if tx.Policy != nil {
+		if tx.Policy.SlashRatio1000 > 1000 || tx.Policy.ReporterRatio1000 > 1000 {
+			return ErrInvalidSlashPolicy
+		}
+	}
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *UpdateSlashPolicy) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	if tx.Policy == nil {
+		ctw.SetProcessData(tagSlashPolicy, nil)
+	} else {
+		if bs, err := encoding.Marshal(tx.Policy); err != nil {
+			return err
+		} else {
+			ctw.SetProcessData(tagSlashPolicy, bs)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *UpdateSlashPolicy) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"policy":`)
+	if bs, err := tx.Policy.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -1,6 +1,7 @@
 package formulator
 
 import (
+	"bytes"
 	"encoding/binary"
 
 	"github.com/fletaio/fleta_testnet/common"
@@ -18,6 +19,7 @@ var (
 	tagOmegaPolicy              = []byte{2, 3}
 	tagHyperPolicy              = []byte{2, 4}
 	tagTransmutePolicy          = []byte{2, 5}
+	tagSlashPolicy              = []byte{2, 6}
 	tagGenCount                 = []byte{3, 0}
 	tagGenCountNumber           = []byte{3, 1}
 	tagGenCountReverse          = []byte{3, 2}
@@ -37,6 +39,10 @@ var (
 	tagUnstakingAmountNumber    = []byte{6, 1}
 	tagUnstakingAmountReverse   = []byte{6, 2}
 	tagUnstakingAmountCount     = []byte{6, 3}
+	tagSlashedHeight            = []byte{7, 0}
+	tagRedelegateCooldown       = []byte{8, 0}
+	tagRedelegations            = []byte{8, 1}
+	tagRedelegatedHeight        = []byte{8, 2}
 )
 
 func toStakingAmountKey(StakingAddrss common.Address) []byte {
@@ -149,3 +155,21 @@ func toUnstakingAmountCountKey(height uint32) []byte {
 	binary.BigEndian.PutUint32(bs[2:], height)
 	return bs
 }
+
+func toRedelegateCooldownKey(HyperFrom common.Address, HyperTo common.Address) []byte {
+	if bytes.Compare(HyperFrom[:], HyperTo[:]) > 0 {
+		HyperFrom, HyperTo = HyperTo, HyperFrom
+	}
+	bs := make([]byte, 2+common.AddressSize*2)
+	copy(bs, tagRedelegateCooldown)
+	copy(bs[2:], HyperFrom[:])
+	copy(bs[2+common.AddressSize:], HyperTo[:])
+	return bs
+}
+
+func toRedelegatedHeightKey(HyperAddress common.Address) []byte {
+	bs := make([]byte, 2+common.AddressSize)
+	copy(bs, tagRedelegatedHeight)
+	copy(bs[2:], HyperAddress[:])
+	return bs
+}
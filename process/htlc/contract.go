@@ -0,0 +1,107 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// MaxLockPeriod is the maximum number of blocks that the amount can be locked by a contract
+const MaxLockPeriod = 172800 * 30 // 30 days
+
+// MaxPreimageSize is the maximum size of the preimage of the hashlock
+const MaxPreimageSize = 256
+
+// contract statuses
+const (
+	StatusLocked   = uint8(1)
+	StatusClaimed  = uint8(2)
+	StatusRefunded = uint8(3)
+)
+
+// Contract is a hashed time-locked contract
+// The amount is claimed to the recipient by the preimage of the hashlock before the expire height
+// or refunded to the sender from the expire height
+type Contract struct {
+	Sender       common.Address
+	Recipient    common.Address
+	Amount       *amount.Amount
+	HashLock     hash.Hash256
+	ExpireHeight uint32
+	Status       uint8
+	Preimage     []byte
+}
+
+// StatusName returns the name of the status of the contract
+func (ct *Contract) StatusName() string {
+	switch ct.Status {
+	case StatusLocked:
+		return "locked"
+	case StatusClaimed:
+		return "claimed"
+	case StatusRefunded:
+		return "refunded"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON is a marshaler function
+func (ct *Contract) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"sender":`)
+	if bs, err := ct.Sender.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"recipient":`)
+	if bs, err := ct.Recipient.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := ct.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"hash_lock":`)
+	if bs, err := ct.HashLock.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"expire_height":`)
+	if bs, err := json.Marshal(ct.ExpireHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"status":`)
+	if bs, err := json.Marshal(ct.StatusName()); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"preimage":`)
+	if bs, err := json.Marshal(hex.EncodeToString(ct.Preimage)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,14 @@
+package htlc
+
+import "errors"
+
+// errors
+var (
+	ErrInvalidExpireHeight = errors.New("invalid expire height")
+	ErrInvalidPreimage     = errors.New("invalid preimage")
+	ErrNotExistContract    = errors.New("not exist contract")
+	ErrExistContract       = errors.New("exist contract")
+	ErrNotLockedContract   = errors.New("not locked contract")
+	ErrExpiredContract     = errors.New("expired contract")
+	ErrNotExpiredContract  = errors.New("not expired contract")
+)
@@ -0,0 +1,97 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// ClaimedEvent is emitted when the contract is claimed by the preimage
+type ClaimedEvent struct {
+	Height_    uint32
+	Index_     uint16
+	N_         uint16
+	ContractID string
+	Recipient  common.Address
+	Amount     *amount.Amount
+	Preimage   []byte
+}
+
+// Height returns the height of the event
+func (ev *ClaimedEvent) Height() uint32 {
+	return ev.Height_
+}
+
+// Index returns the index of the event
+func (ev *ClaimedEvent) Index() uint16 {
+	return ev.Index_
+}
+
+// N returns the n of the event
+func (ev *ClaimedEvent) N() uint16 {
+	return ev.N_
+}
+
+// SetN updates the n of the event
+func (ev *ClaimedEvent) SetN(n uint16) {
+	ev.N_ = n
+}
+
+// MarshalJSON is a marshaler function
+func (ev *ClaimedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ev.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"n":`)
+	if bs, err := json.Marshal(ev.N_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(ev.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"recipient":`)
+	if bs, err := ev.Recipient.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := ev.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"preimage":`)
+	if bs, err := json.Marshal(hex.EncodeToString(ev.Preimage)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+)
+
+// LockedEvent is emitted when the amount is locked by the contract
+type LockedEvent struct {
+	Height_      uint32
+	Index_       uint16
+	N_           uint16
+	ContractID   string
+	Sender       common.Address
+	Recipient    common.Address
+	Amount       *amount.Amount
+	HashLock     hash.Hash256
+	ExpireHeight uint32
+}
+
+// Height returns the height of the event
+func (ev *LockedEvent) Height() uint32 {
+	return ev.Height_
+}
+
+// Index returns the index of the event
+func (ev *LockedEvent) Index() uint16 {
+	return ev.Index_
+}
+
+// N returns the n of the event
+func (ev *LockedEvent) N() uint16 {
+	return ev.N_
+}
+
+// SetN updates the n of the event
+func (ev *LockedEvent) SetN(n uint16) {
+	ev.N_ = n
+}
+
+// MarshalJSON is a marshaler function
+func (ev *LockedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(ev.Height_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ev.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"n":`)
+	if bs, err := json.Marshal(ev.N_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(ev.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"sender":`)
+	if bs, err := ev.Sender.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"recipient":`)
+	if bs, err := ev.Recipient.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := ev.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"hash_lock":`)
+	if bs, err := ev.HashLock.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"expire_height":`)
+	if bs, err := json.Marshal(ev.ExpireHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
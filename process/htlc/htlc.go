@@ -0,0 +1,106 @@
+package htlc
+
+import (
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/vault"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// HTLC manages hashed time-locked contracts whose amounts are held by the vault
+type HTLC struct {
+	*types.ProcessBase
+	pid   uint8
+	pm    types.ProcessManager
+	cn    types.Provider
+	vault *vault.Vault
+}
+
+// NewHTLC returns a HTLC
+func NewHTLC(pid uint8) *HTLC {
+	p := &HTLC{
+		pid: pid,
+	}
+	return p
+}
+
+// ID returns the id of the process
+func (p *HTLC) ID() uint8 {
+	return p.pid
+}
+
+// Name returns the name of the process
+func (p *HTLC) Name() string {
+	return "fleta.htlc"
+}
+
+// Version returns the version of the process
+func (p *HTLC) Version() string {
+	return "0.0.1"
+}
+
+// Init initializes the process
+func (p *HTLC) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
+	p.pm = pm
+	p.cn = cn
+
+	if vp, err := pm.ProcessByName("fleta.vault"); err != nil {
+		return err
+	} else if v, is := vp.(*vault.Vault); !is {
+		return types.ErrInvalidProcess
+	} else {
+		p.vault = v
+	}
+
+	reg.RegisterTransaction(1, &Lock{})
+	reg.RegisterTransaction(2, &Claim{})
+	reg.RegisterTransaction(3, &Refund{})
+	reg.RegisterEvent(1, &LockedEvent{})
+	reg.RegisterEvent(2, &ClaimedEvent{})
+	reg.RegisterEvent(3, &RefundedEvent{})
+
+	if vs, err := pm.ServiceByName("fleta.apiserver"); err != nil {
+		//ignore when not loaded
+	} else if v, is := vs.(*apiserver.APIServer); !is {
+		//ignore when not loaded
+	} else {
+		s, err := v.JRPC("htlc")
+		if err != nil {
+			return err
+		}
+		s.Set("get", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			ContractID, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			if _, _, err := types.ParseTransactionID(ContractID); err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.Contract(ctw, ContractID)
+		})
+	}
+	return nil
+}
+
+// OnLoadChain called when the chain loaded
+func (p *HTLC) OnLoadChain(loader types.LoaderWrapper) error {
+	return nil
+}
+
+// BeforeExecuteTransactions called before processes transactions of the block
+func (p *HTLC) BeforeExecuteTransactions(ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// AfterExecuteTransactions called after processes transactions of the block
+func (p *HTLC) AfterExecuteTransactions(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// OnSaveData called when the context of the block saved
+func (p *HTLC) OnSaveData(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package htlc
+
+import (
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// Contract returns the contract of the id
+func (p *HTLC) Contract(loader types.Loader, ID string) (*Contract, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toContractKey(ID)); len(bs) > 0 {
+		ct := &Contract{}
+		if err := encoding.Unmarshal(bs, &ct); err != nil {
+			return nil, err
+		}
+		return ct, nil
+	} else {
+		return nil, ErrNotExistContract
+	}
+}
+
+func (p *HTLC) setContract(ctw *types.ContextWrapper, ID string, ct *Contract) error {
+	bs, err := encoding.Marshal(ct)
+	if err != nil {
+		return err
+	}
+	ctw.SetProcessData(toContractKey(ID), bs)
+	return nil
+}
@@ -0,0 +1,186 @@
+package htlc
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+type htlcFixture struct {
+	p     *HTLC
+	vp    *vault.Vault
+	ctx   *types.Context
+	Addrs []common.Address
+	Keys  []common.PublicHash
+}
+
+// newHTLCFixture creates alice, bob and carol that have 100 coins each at the height 10
+func newHTLCFixture(t *testing.T) *htlcFixture {
+	p := NewHTLC(7)
+	vp := vault.NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), vp, p); err != nil {
+		t.Fatal(err)
+	}
+	fx := &htlcFixture{
+		p:   p,
+		vp:  vp,
+		ctx: types.NewEmptyContext(),
+	}
+	ctw := types.NewContextWrapper(p.ID(), fx.ctx)
+	for i, Name := range []string{"alice", "bob", "carol"} {
+		acc := &vault.SingleAccount{
+			Address_: common.NewAddress(0, uint16(i+1), 0),
+			Name_:    Name,
+			KeyHash:  common.PublicHash{byte(i + 1)},
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := vp.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(100, 0)); err != nil {
+			t.Fatal(err)
+		}
+		fx.Addrs = append(fx.Addrs, acc.Address())
+		fx.Keys = append(fx.Keys, acc.KeyHash)
+	}
+	fx.ctx = processtest.Advance(fx.ctx, 10)
+	return fx
+}
+
+func (fx *htlcFixture) lock(ExpireHeight uint32) (string, error) {
+	ID := types.TransactionID(fx.ctx.TargetHeight(), 0)
+	return ID, processtest.Execute(fx.ctx, fx.p, &Lock{
+		Seq_:         processtest.NextSeq(fx.ctx, fx.Addrs[0]),
+		From_:        fx.Addrs[0],
+		Recipient:    fx.Addrs[1],
+		Amount:       amount.NewCoinAmount(10, 0),
+		HashLock:     hash.Hash([]byte("secret")),
+		ExpireHeight: ExpireHeight,
+	}, fx.Keys[0])
+}
+
+func (fx *htlcFixture) claim(Signer int, ID string, Preimage string) error {
+	return processtest.Execute(fx.ctx, fx.p, &Claim{
+		Seq_:       processtest.NextSeq(fx.ctx, fx.Addrs[Signer]),
+		From_:      fx.Addrs[Signer],
+		ContractID: ID,
+		Preimage:   []byte(Preimage),
+	}, fx.Keys[Signer])
+}
+
+func (fx *htlcFixture) refund(Signer int, ID string) error {
+	return processtest.Execute(fx.ctx, fx.p, &Refund{
+		Seq_:       processtest.NextSeq(fx.ctx, fx.Addrs[Signer]),
+		From_:      fx.Addrs[Signer],
+		ContractID: ID,
+	}, fx.Keys[Signer])
+}
+
+func TestHTLC_Lock(t *testing.T) {
+	tests := []struct {
+		name   string
+		expire uint32
+		err    error
+	}{
+		{"expire at the next height", 11, nil},
+		{"expire at the target height", 10, ErrInvalidExpireHeight},
+		{"expire at the max lock period", 10 + MaxLockPeriod, nil},
+		{"expire over the max lock period", 10 + MaxLockPeriod + 1, ErrInvalidExpireHeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newHTLCFixture(t)
+			ID, err := fx.lock(tt.expire)
+			if err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			if err != nil {
+				if _, err := fx.p.Contract(fx.ctx, ID); err != ErrNotExistContract {
+					t.Fatalf("contract is made %v", err)
+				}
+				return
+			}
+			if am := fx.vp.Balance(fx.ctx, fx.Addrs[0]); !am.Equal(amount.MustParseAmount("89.9")) {
+				t.Fatalf("invalid sender balance %v", am)
+			}
+			if am := fx.vp.HeldBalance(fx.ctx); !am.Equal(amount.NewCoinAmount(10, 0)) {
+				t.Fatalf("invalid held balance %v", am)
+			}
+		})
+	}
+}
+
+func TestHTLC_ClaimAndRefund(t *testing.T) {
+	tests := []struct {
+		name     string
+		refund   bool
+		signer   int
+		height   uint32
+		preimage string
+		err      error
+		status   uint8
+		balances []string
+	}{
+		{"claim before the expiry", false, 1, 19, "secret", nil, StatusClaimed, []string{"89.9", "109.9", "100"}},
+		{"claim at the expiry", false, 1, 20, "secret", ErrExpiredContract, StatusLocked, []string{"89.9", "100", "100"}},
+		{"claim after the expiry", false, 1, 21, "secret", ErrExpiredContract, StatusLocked, []string{"89.9", "100", "100"}},
+		{"claim with the wrong preimage", false, 1, 15, "wrong", ErrInvalidPreimage, StatusLocked, []string{"89.9", "100", "100"}},
+		{"claim by the third party", false, 2, 15, "secret", nil, StatusClaimed, []string{"89.9", "110", "99.9"}},
+		{"refund before the expiry", true, 0, 19, "", ErrNotExpiredContract, StatusLocked, []string{"89.9", "100", "100"}},
+		{"refund at the expiry", true, 0, 20, "", nil, StatusRefunded, []string{"99.8", "100", "100"}},
+		{"refund by the recipient", true, 1, 21, "", nil, StatusRefunded, []string{"99.9", "99.9", "100"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newHTLCFixture(t)
+			ID, err := fx.lock(20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fx.ctx = processtest.Advance(fx.ctx, tt.height)
+
+			if tt.refund {
+				err = fx.refund(tt.signer, ID)
+			} else {
+				err = fx.claim(tt.signer, ID, tt.preimage)
+			}
+			if err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			ct, err := fx.p.Contract(fx.ctx, ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ct.Status != tt.status {
+				t.Fatalf("invalid status %v, expected %v", ct.StatusName(), tt.status)
+			}
+			for i, v := range tt.balances {
+				if am := fx.vp.Balance(fx.ctx, fx.Addrs[i]); !am.Equal(amount.MustParseAmount(v)) {
+					t.Fatalf("invalid balance of %v: %v, expected %v", i, am, v)
+				}
+			}
+			Held := amount.NewCoinAmount(0, 0)
+			if ct.Status == StatusLocked {
+				Held = ct.Amount
+			}
+			if am := fx.vp.HeldBalance(fx.ctx); !am.Equal(Held) {
+				t.Fatalf("invalid held balance %v, expected %v", am, Held)
+			}
+
+			// the settled contract cannot be claimed or refunded again
+			if ct.Status != StatusLocked {
+				if err := fx.claim(1, ID, "secret"); err != ErrNotLockedContract {
+					t.Fatalf("expected %v but %v", ErrNotLockedContract, err)
+				}
+				if err := fx.refund(0, ID); err != ErrNotLockedContract {
+					t.Fatalf("expected %v but %v", ErrNotLockedContract, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Claim transfers the locked amount to the recipient by revealing the preimage of the hashlock
+// Anyone can submit it because the amount is always transferred to the recipient
+type Claim struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	ContractID string
+	Preimage   []byte
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Claim) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Claim) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Claim) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Claim) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Claim) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*HTLC)
+
+	if len(tx.Preimage) == 0 || len(tx.Preimage) > MaxPreimageSize {
+		return ErrInvalidPreimage
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	ct, err := sp.Contract(loader, tx.ContractID)
+	if err != nil {
+		return err
+	}
+	if ct.Status != StatusLocked {
+		return ErrNotLockedContract
+	}
+	if ct.ExpireHeight <= loader.TargetHeight() {
+		return ErrExpiredContract
+	}
+	if hash.Hash(tx.Preimage) != ct.HashLock {
+		return ErrInvalidPreimage
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Claim) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*HTLC)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ct, err := sp.Contract(ctw, tx.ContractID)
+		if err != nil {
+			return err
+		}
+		if ct.Status != StatusLocked {
+			return ErrNotLockedContract
+		}
+		if err := sp.vault.Release(ctw, ct.Recipient, ct.Amount); err != nil {
+			return err
+		}
+		ct.Status = StatusClaimed
+		ct.Preimage = tx.Preimage
+		if err := sp.setContract(ctw, tx.ContractID, ct); err != nil {
+			return err
+		}
+
+		ev := &ClaimedEvent{
+			Height_:    ctw.TargetHeight(),
+			Index_:     index,
+			ContractID: tx.ContractID,
+			Recipient:  ct.Recipient,
+			Amount:     ct.Amount,
+			Preimage:   tx.Preimage,
+		}
+		if err := ctw.EmitEvent(ev); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Claim) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(tx.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"preimage":`)
+	if bs, err := json.Marshal(hex.EncodeToString(tx.Preimage)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,173 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Lock locks the amount to the recipient under the hashlock until the expire height
+// The id of the contract is the transaction id of it
+type Lock struct {
+	Timestamp_   uint64
+	Seq_         uint64
+	From_        common.Address
+	Recipient    common.Address
+	Amount       *amount.Amount
+	HashLock     hash.Hash256
+	ExpireHeight uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Lock) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Lock) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Lock) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Lock) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Lock) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*HTLC)
+
+	if tx.Amount.Less(amount.COIN.DivC(10)) {
+		return types.ErrDustAmount
+	}
+	if tx.ExpireHeight <= loader.TargetHeight() || tx.ExpireHeight > loader.TargetHeight()+MaxLockPeriod {
+		return ErrInvalidExpireHeight
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if has, err := loader.HasAccount(tx.Recipient); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayableWith(loader, tx, tx.Amount); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Lock) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*HTLC)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ID := types.TransactionID(ctw.TargetHeight(), index)
+		if _, err := sp.Contract(ctw, ID); err == nil {
+			return ErrExistContract
+		}
+		if err := sp.vault.Hold(ctw, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+		if err := sp.setContract(ctw, ID, &Contract{
+			Sender:       tx.From(),
+			Recipient:    tx.Recipient,
+			Amount:       tx.Amount,
+			HashLock:     tx.HashLock,
+			ExpireHeight: tx.ExpireHeight,
+			Status:       StatusLocked,
+		}); err != nil {
+			return err
+		}
+
+		ev := &LockedEvent{
+			Height_:      ctw.TargetHeight(),
+			Index_:       index,
+			ContractID:   ID,
+			Sender:       tx.From(),
+			Recipient:    tx.Recipient,
+			Amount:       tx.Amount,
+			HashLock:     tx.HashLock,
+			ExpireHeight: tx.ExpireHeight,
+		}
+		if err := ctw.EmitEvent(ev); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Lock) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"recipient":`)
+	if bs, err := tx.Recipient.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"hash_lock":`)
+	if bs, err := tx.HashLock.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"expire_height":`)
+	if bs, err := json.Marshal(tx.ExpireHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Refund returns the locked amount to the sender from the expire height of the contract
+// Anyone can submit it because the amount is always returned to the sender
+type Refund struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	ContractID string
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Refund) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Refund) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Refund) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Refund) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Refund) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*HTLC)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	ct, err := sp.Contract(loader, tx.ContractID)
+	if err != nil {
+		return err
+	}
+	if ct.Status != StatusLocked {
+		return ErrNotLockedContract
+	}
+	if ct.ExpireHeight > loader.TargetHeight() {
+		return ErrNotExpiredContract
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Refund) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*HTLC)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ct, err := sp.Contract(ctw, tx.ContractID)
+		if err != nil {
+			return err
+		}
+		if ct.Status != StatusLocked {
+			return ErrNotLockedContract
+		}
+		if err := sp.vault.Release(ctw, ct.Sender, ct.Amount); err != nil {
+			return err
+		}
+		ct.Status = StatusRefunded
+		if err := sp.setContract(ctw, tx.ContractID, ct); err != nil {
+			return err
+		}
+
+		ev := &RefundedEvent{
+			Height_:    ctw.TargetHeight(),
+			Index_:     index,
+			ContractID: tx.ContractID,
+			Sender:     ct.Sender,
+			Amount:     ct.Amount,
+		}
+		if err := ctw.EmitEvent(ev); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Refund) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"contract_id":`)
+	if bs, err := json.Marshal(tx.ContractID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,13 @@
+package htlc
+
+// tags
+var (
+	tagContract = []byte{1, 0}
+)
+
+func toContractKey(ID string) []byte {
+	bs := make([]byte, 2+len(ID))
+	copy(bs, tagContract)
+	copy(bs[2:], []byte(ID))
+	return bs
+}
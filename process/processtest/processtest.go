@@ -0,0 +1,108 @@
+// Package processtest provides an in-memory process manager and context helpers for the process tests
+package processtest
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/chain"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// ProcessManager is an in-memory process manager without services
+type ProcessManager struct {
+	processes []types.Process
+	idMap     map[uint8]types.Process
+	nameMap   map[string]types.Process
+}
+
+// NewProcessManager returns a ProcessManager that initializes the processes in the order
+func NewProcessManager(ps ...types.Process) (*ProcessManager, error) {
+	pm := &ProcessManager{
+		idMap:   map[uint8]types.Process{},
+		nameMap: map[string]types.Process{},
+	}
+	for _, p := range ps {
+		pm.processes = append(pm.processes, p)
+		pm.idMap[p.ID()] = p
+		pm.nameMap[p.Name()] = p
+	}
+	for _, p := range ps {
+		if err := p.Init(types.NewRegister(p.ID()), pm, nil); err != nil {
+			return nil, err
+		}
+	}
+	return pm, nil
+}
+
+// Processes returns processes
+func (pm *ProcessManager) Processes() []types.Process {
+	return pm.processes
+}
+
+// Process returns the process by the id
+func (pm *ProcessManager) Process(id uint8) (types.Process, error) {
+	p, has := pm.idMap[id]
+	if !has {
+		return nil, types.ErrNotExistProcess
+	}
+	return p, nil
+}
+
+// ProcessByName returns the process by the name
+func (pm *ProcessManager) ProcessByName(name string) (types.Process, error) {
+	p, has := pm.nameMap[name]
+	if !has {
+		return nil, types.ErrNotExistProcess
+	}
+	return p, nil
+}
+
+// Services returns no services
+func (pm *ProcessManager) Services() []types.Service {
+	return nil
+}
+
+// ServiceByName always returns chain.ErrNotExistService
+func (pm *ProcessManager) ServiceByName(name string) (types.Service, error) {
+	return nil, chain.ErrNotExistService
+}
+
+// Advance returns the context of the target height that stacks the given context
+func Advance(ctx *types.Context, TargetHeight uint32) *types.Context {
+	for ctx.TargetHeight() < TargetHeight {
+		ctx = ctx.NextContext(hash.Hash256{}, uint64(ctx.TargetHeight()+1))
+	}
+	return ctx
+}
+
+// NextSeq returns the sequence of the next transaction of the address
+func NextSeq(ctx *types.Context, addr common.Address) uint64 {
+	return ctx.Seq(addr) + 1
+}
+
+// Execute validates the transaction with signers and executes it by the process as the chain does
+// The failed account transaction keeps the sequence and the fee, other failed transactions are reverted
+func Execute(ctx *types.Context, p types.Process, tx types.Transaction, Signers ...common.PublicHash) error {
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+	sn := ctw.Snapshot()
+	if err := tx.Validate(p, ctw, Signers); err != nil {
+		ctw.Revert(sn)
+		return err
+	}
+	if at, is := tx.(chain.AccountTransaction); is {
+		if at.Seq() != ctw.Seq(at.From())+1 {
+			ctw.Revert(sn)
+			return types.ErrInvalidSequence
+		}
+		ctw.AddSeq(at.From())
+		err := tx.Execute(p, ctw, 0)
+		ctw.Commit(sn)
+		return err
+	}
+	if err := tx.Execute(p, ctw, 0); err != nil {
+		ctw.Revert(sn)
+		return err
+	}
+	ctw.Commit(sn)
+	return nil
+}
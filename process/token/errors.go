@@ -0,0 +1,24 @@
+package token
+
+import "errors"
+
+// errors
+var (
+	ErrMinusInput             = errors.New("minus input")
+	ErrMinusBalance           = errors.New("minus balance")
+	ErrMinusAllowance         = errors.New("minus allowance")
+	ErrInvalidAmount          = errors.New("invalid amount")
+	ErrInvalidSymbol          = errors.New("invalid symbol")
+	ErrInvalidTokenName       = errors.New("invalid token name")
+	ErrInvalidDecimals        = errors.New("invalid decimals")
+	ErrInvalidMaxSupply       = errors.New("invalid max supply")
+	ErrInvalidTokenID         = errors.New("invalid token id")
+	ErrExistToken             = errors.New("exist token")
+	ErrNotExistToken          = errors.New("not exist token")
+	ErrNotTokenIssuer         = errors.New("not token issuer")
+	ErrExceedMaxSupply        = errors.New("exceed max supply")
+	ErrInsufficientBalance    = errors.New("insufficient balance")
+	ErrInsufficientAllowance  = errors.New("insufficient allowance")
+	ErrNotAllowedSelfTransfer = errors.New("not allowed self transfer")
+	ErrNotAllowedSelfApproval = errors.New("not allowed self approval")
+)
@@ -0,0 +1,192 @@
+package token
+
+import (
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/vault"
+	"github.com/fletaio/fleta_testnet/service/apiserver"
+)
+
+// Token manages fungible tokens that are issued by accounts of the chain
+type Token struct {
+	*types.ProcessBase
+	pid   uint8
+	pm    types.ProcessManager
+	cn    types.Provider
+	vault *vault.Vault
+}
+
+// NewToken returns a Token
+func NewToken(pid uint8) *Token {
+	p := &Token{
+		pid: pid,
+	}
+	return p
+}
+
+// ID returns the id of the process
+func (p *Token) ID() uint8 {
+	return p.pid
+}
+
+// Name returns the name of the process
+func (p *Token) Name() string {
+	return "fleta.token"
+}
+
+// Version returns the version of the process
+func (p *Token) Version() string {
+	return "0.0.1"
+}
+
+// Init initializes the process
+func (p *Token) Init(reg *types.Register, pm types.ProcessManager, cn types.Provider) error {
+	p.pm = pm
+	p.cn = cn
+
+	if vp, err := pm.ProcessByName("fleta.vault"); err != nil {
+		return err
+	} else if v, is := vp.(*vault.Vault); !is {
+		return types.ErrInvalidProcess
+	} else {
+		p.vault = v
+	}
+
+	reg.RegisterTransaction(1, &CreateToken{})
+	reg.RegisterTransaction(2, &Mint{})
+	reg.RegisterTransaction(3, &Burn{})
+	reg.RegisterTransaction(4, &Transfer{})
+	reg.RegisterTransaction(5, &Approve{})
+	reg.RegisterTransaction(6, &TransferFrom{})
+	reg.RegisterEvent(1, &TransferEvent{})
+
+	if vs, err := pm.ServiceByName("fleta.apiserver"); err != nil {
+		//ignore when not loaded
+	} else if v, is := vs.(*apiserver.APIServer); !is {
+		//ignore when not loaded
+	} else {
+		s, err := v.JRPC("token")
+		if err != nil {
+			return err
+		}
+		s.Set("tokenID", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			Symbol, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			return TokenID(Symbol), nil
+		})
+		s.Set("info", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			TokenID, err := parseTokenID(arg, 0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.TokenInfo(ctw, TokenID)
+		})
+		s.Set("totalSupply", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			TokenID, err := parseTokenID(arg, 0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			if _, err := p.TokenInfo(ctw, TokenID); err != nil {
+				return nil, err
+			}
+			return p.TotalSupply(ctw, TokenID), nil
+		})
+		s.Set("balance", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 2 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			TokenID, err := parseTokenID(arg, 0)
+			if err != nil {
+				return nil, err
+			}
+			addr, err := parseAddress(arg, 1)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			if _, err := p.TokenInfo(ctw, TokenID); err != nil {
+				return nil, err
+			}
+			return p.Balance(ctw, TokenID, addr), nil
+		})
+		s.Set("allowance", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 3 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			TokenID, err := parseTokenID(arg, 0)
+			if err != nil {
+				return nil, err
+			}
+			Owner, err := parseAddress(arg, 1)
+			if err != nil {
+				return nil, err
+			}
+			Spender, err := parseAddress(arg, 2)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			if _, err := p.TokenInfo(ctw, TokenID); err != nil {
+				return nil, err
+			}
+			return p.Allowance(ctw, TokenID, Owner, Spender), nil
+		})
+	}
+	return nil
+}
+
+// parseTokenID accepts both of the symbol and the numeric token id
+func parseTokenID(arg *apiserver.Argument, index int) (uint64, error) {
+	if v, err := arg.Uint64(index); err == nil {
+		return v, nil
+	}
+	Symbol, err := arg.String(index)
+	if err != nil {
+		return 0, err
+	}
+	if !IsValidSymbol(Symbol) {
+		return 0, ErrInvalidSymbol
+	}
+	return TokenID(Symbol), nil
+}
+
+func parseAddress(arg *apiserver.Argument, index int) (common.Address, error) {
+	v, err := arg.String(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.ParseAddress(v)
+}
+
+// OnLoadChain called when the chain loaded
+func (p *Token) OnLoadChain(loader types.LoaderWrapper) error {
+	return nil
+}
+
+// BeforeExecuteTransactions called before processes transactions of the block
+func (p *Token) BeforeExecuteTransactions(ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// AfterExecuteTransactions called after processes transactions of the block
+func (p *Token) AfterExecuteTransactions(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
+
+// OnSaveData called when the context of the block saved
+func (p *Token) OnSaveData(b *types.Block, ctw *types.ContextWrapper) error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// TokenInfo defines the metadata of the token
+type TokenInfo struct {
+	Symbol    string
+	Name      string
+	Decimals  uint8
+	MaxSupply *amount.Amount
+	Issuer    common.Address
+	Creator   common.Address
+}
+
+// MarshalJSON is a marshaler function
+func (ti *TokenInfo) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"token_id":`)
+	if bs, err := json.Marshal(TokenID(ti.Symbol)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"symbol":`)
+	if bs, err := json.Marshal(ti.Symbol); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(ti.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"decimals":`)
+	if bs, err := json.Marshal(ti.Decimals); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"max_supply":`)
+	if bs, err := ti.MaxSupply.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"issuer":`)
+	if bs, err := ti.Issuer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"creator":`)
+	if bs, err := ti.Creator.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,170 @@
+package token
+
+import (
+	"math/big"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// TokenInfo returns the metadata of the token
+func (p *Token) TokenInfo(loader types.Loader, TokenID uint64) (*TokenInfo, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toTokenKey(TokenID)); len(bs) > 0 {
+		ti := &TokenInfo{}
+		if err := encoding.Unmarshal(bs, &ti); err != nil {
+			return nil, err
+		}
+		return ti, nil
+	} else {
+		return nil, ErrNotExistToken
+	}
+}
+
+// HasToken returns the token is created or not
+func (p *Token) HasToken(loader types.Loader, TokenID uint64) bool {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toTokenKey(TokenID)); len(bs) > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+func (p *Token) addToken(ctw *types.ContextWrapper, ti *TokenInfo) error {
+	TokenID := TokenID(ti.Symbol)
+	if bs := ctw.ProcessData(toTokenKey(TokenID)); len(bs) > 0 {
+		return ErrExistToken
+	}
+	bs, err := encoding.Marshal(ti)
+	if err != nil {
+		return err
+	}
+	ctw.SetProcessData(toTokenKey(TokenID), bs)
+	return nil
+}
+
+// TotalSupply returns the circulating supply of the token
+func (p *Token) TotalSupply(loader types.Loader, TokenID uint64) *amount.Amount {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toTotalSupplyKey(TokenID)); len(bs) > 0 {
+		return amount.NewAmountFromBytes(bs)
+	} else {
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+func (p *Token) setTotalSupply(ctw *types.ContextWrapper, TokenID uint64, am *amount.Amount) {
+	if am.IsZero() {
+		ctw.SetProcessData(toTotalSupplyKey(TokenID), nil)
+	} else {
+		ctw.SetProcessData(toTotalSupplyKey(TokenID), am.Bytes())
+	}
+}
+
+// Balance returns the token balance of the account of the address
+func (p *Token) Balance(loader types.Loader, TokenID uint64, addr common.Address) *amount.Amount {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(addr, toBalanceKey(TokenID)); len(bs) > 0 {
+		return amount.NewAmountFromBytes(bs)
+	} else {
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+func (p *Token) addBalance(ctw *types.ContextWrapper, TokenID uint64, addr common.Address, am *amount.Amount) error {
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	ctw.SetAccountData(addr, toBalanceKey(TokenID), p.Balance(ctw, TokenID, addr).Add(am).Bytes())
+	return nil
+}
+
+func (p *Token) subBalance(ctw *types.ContextWrapper, TokenID uint64, addr common.Address, am *amount.Amount) error {
+	sum := p.Balance(ctw, TokenID, addr)
+	if sum.Less(am) {
+		return ErrMinusBalance
+	}
+	sum = sum.Sub(am)
+	if sum.IsZero() {
+		ctw.SetAccountData(addr, toBalanceKey(TokenID), nil)
+	} else {
+		ctw.SetAccountData(addr, toBalanceKey(TokenID), sum.Bytes())
+	}
+	return nil
+}
+
+// Allowance returns the amount that the spender can transfer from the owner
+func (p *Token) Allowance(loader types.Loader, TokenID uint64, Owner common.Address, Spender common.Address) *amount.Amount {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(Owner, toAllowanceKey(TokenID, Spender)); len(bs) > 0 {
+		return amount.NewAmountFromBytes(bs)
+	} else {
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+func (p *Token) setAllowance(ctw *types.ContextWrapper, TokenID uint64, Owner common.Address, Spender common.Address, am *amount.Amount) {
+	if am.IsZero() {
+		ctw.SetAccountData(Owner, toAllowanceKey(TokenID, Spender), nil)
+	} else {
+		ctw.SetAccountData(Owner, toAllowanceKey(TokenID, Spender), am.Bytes())
+	}
+}
+
+func (p *Token) subAllowance(ctw *types.ContextWrapper, TokenID uint64, Owner common.Address, Spender common.Address, am *amount.Amount) error {
+	sum := p.Allowance(ctw, TokenID, Owner, Spender)
+	if sum.Less(am) {
+		return ErrMinusAllowance
+	}
+	p.setAllowance(ctw, TokenID, Owner, Spender, sum.Sub(am))
+	return nil
+}
+
+// transfer moves the token balance and emits the transfer event
+// the zero address is used as the from address of the minting and the to address of the burning
+func (p *Token) transfer(ctw *types.ContextWrapper, index uint16, TokenID uint64, From common.Address, To common.Address, am *amount.Amount) error {
+	if From != (common.Address{}) {
+		if err := p.subBalance(ctw, TokenID, From, am); err != nil {
+			return err
+		}
+	}
+	if To != (common.Address{}) {
+		if err := p.addBalance(ctw, TokenID, To, am); err != nil {
+			return err
+		}
+	}
+	ev := &TransferEvent{
+		Height_: ctw.TargetHeight(),
+		Index_:  index,
+		TokenID: TokenID,
+		From:    From,
+		To:      To,
+		Amount:  am,
+	}
+	if err := ctw.EmitEvent(ev); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsValidAmount returns the amount is positive and fits in the decimals of the token
+func IsValidAmount(ti *TokenInfo, am *amount.Amount) bool {
+	if am == nil || am.Int == nil || am.Sign() <= 0 {
+		return false
+	}
+	if ti.Decimals >= amount.FractionalCount {
+		return true
+	}
+	unit := big.NewInt(10)
+	unit.Exp(unit, big.NewInt(int64(amount.FractionalCount-ti.Decimals)), nil)
+	return new(big.Int).Mod(am.Int, unit).Sign() == 0
+}
@@ -0,0 +1,189 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+	"github.com/fletaio/fleta_testnet/process/vault"
+)
+
+type tokenFixture struct {
+	p       *Token
+	vp      *vault.Vault
+	ctx     *types.Context
+	Addrs   []common.Address
+	Keys    []common.PublicHash
+	TokenID uint64
+}
+
+// newTokenFixture creates the issuer, alice and bob with 10 coins each
+// The issuer creates TKN of 2 decimals and 1000 max supply, mints 100 to alice and alice approves 50 to bob
+func newTokenFixture(t *testing.T) *tokenFixture {
+	p := NewToken(6)
+	vp := vault.NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), vp, p); err != nil {
+		t.Fatal(err)
+	}
+
+	fx := &tokenFixture{
+		p:       p,
+		vp:      vp,
+		ctx:     types.NewEmptyContext(),
+		TokenID: TokenID("TKN"),
+	}
+	ctw := types.NewContextWrapper(p.ID(), fx.ctx)
+	for i, Name := range []string{"issuer", "alice", "bob"} {
+		acc := &vault.SingleAccount{
+			Address_: common.NewAddress(0, uint16(i+1), 0),
+			Name_:    Name,
+			KeyHash:  common.PublicHash{byte(i + 1)},
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := vp.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(10, 0)); err != nil {
+			t.Fatal(err)
+		}
+		fx.Addrs = append(fx.Addrs, acc.Address())
+		fx.Keys = append(fx.Keys, acc.KeyHash)
+	}
+
+	if err := processtest.Execute(fx.ctx, fx.p, &CreateToken{
+		Seq_:      processtest.NextSeq(fx.ctx, fx.Addrs[0]),
+		From_:     fx.Addrs[0],
+		Symbol:    "TKN",
+		Name:      "Test Token",
+		Decimals:  2,
+		MaxSupply: amount.NewCoinAmount(1000, 0),
+		Issuer:    fx.Addrs[0],
+	}, fx.Keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := processtest.Execute(fx.ctx, fx.p, fx.mint(0, 1, amount.NewCoinAmount(100, 0)), fx.Keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := processtest.Execute(fx.ctx, fx.p, &Approve{
+		Seq_:    processtest.NextSeq(fx.ctx, fx.Addrs[1]),
+		From_:   fx.Addrs[1],
+		TokenID: fx.TokenID,
+		Spender: fx.Addrs[2],
+		Amount:  amount.NewCoinAmount(50, 0),
+	}, fx.Keys[1]); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func (fx *tokenFixture) mint(From int, To int, am *amount.Amount) *Mint {
+	return &Mint{
+		Seq_:    processtest.NextSeq(fx.ctx, fx.Addrs[From]),
+		From_:   fx.Addrs[From],
+		TokenID: fx.TokenID,
+		To:      fx.Addrs[To],
+		Amount:  am,
+	}
+}
+
+func TestToken_Transactions(t *testing.T) {
+	tests := []struct {
+		name      string
+		signer    int
+		tx        func(fx *tokenFixture) types.Transaction
+		err       error
+		balances  []int64
+		supply    int64
+		allowance int64
+	}{
+		{"transfer", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[2], Amount: amount.NewCoinAmount(30, 0)}
+		}, nil, []int64{0, 70, 30}, 100, 50},
+		{"transfer the whole balance", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[2], Amount: amount.NewCoinAmount(100, 0)}
+		}, nil, []int64{0, 0, 100}, 100, 50},
+		{"transfer over the balance", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[2], Amount: amount.NewCoinAmount(101, 0)}
+		}, ErrInsufficientBalance, []int64{0, 100, 0}, 100, 50},
+		{"transfer to self", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[1], Amount: amount.NewCoinAmount(10, 0)}
+		}, ErrNotAllowedSelfTransfer, []int64{0, 100, 0}, 100, 50},
+		{"transfer below the decimals", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[2], Amount: amount.MustParseAmount("0.001")}
+		}, ErrInvalidAmount, []int64{0, 100, 0}, 100, 50},
+		{"transfer signed by other key", 2, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, To: fx.Addrs[2], Amount: amount.NewCoinAmount(10, 0)}
+		}, types.ErrInvalidAccountSigner, []int64{0, 100, 0}, 100, 50},
+		{"transfer of the unknown token", 1, func(fx *tokenFixture) types.Transaction {
+			return &Transfer{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: TokenID("NONE"), To: fx.Addrs[2], Amount: amount.NewCoinAmount(10, 0)}
+		}, ErrNotExistToken, []int64{0, 100, 0}, 100, 50},
+		{"mint up to the max supply", 0, func(fx *tokenFixture) types.Transaction {
+			return fx.mint(0, 2, amount.NewCoinAmount(900, 0))
+		}, nil, []int64{0, 100, 900}, 1000, 50},
+		{"mint over the max supply", 0, func(fx *tokenFixture) types.Transaction {
+			return fx.mint(0, 2, amount.NewCoinAmount(901, 0))
+		}, ErrExceedMaxSupply, []int64{0, 100, 0}, 100, 50},
+		{"mint by the holder", 1, func(fx *tokenFixture) types.Transaction {
+			return fx.mint(1, 2, amount.NewCoinAmount(10, 0))
+		}, ErrNotTokenIssuer, []int64{0, 100, 0}, 100, 50},
+		{"burn", 1, func(fx *tokenFixture) types.Transaction {
+			return &Burn{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, Amount: amount.NewCoinAmount(40, 0)}
+		}, nil, []int64{0, 60, 0}, 60, 50},
+		{"burn over the balance", 1, func(fx *tokenFixture) types.Transaction {
+			return &Burn{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, Amount: amount.NewCoinAmount(101, 0)}
+		}, ErrInsufficientBalance, []int64{0, 100, 0}, 100, 50},
+		{"transfer from the allowance", 2, func(fx *tokenFixture) types.Transaction {
+			return &TransferFrom{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[2]), From_: fx.Addrs[2], TokenID: fx.TokenID, Owner: fx.Addrs[1], To: fx.Addrs[0], Amount: amount.NewCoinAmount(20, 0)}
+		}, nil, []int64{20, 80, 0}, 100, 30},
+		{"transfer from the whole allowance", 2, func(fx *tokenFixture) types.Transaction {
+			return &TransferFrom{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[2]), From_: fx.Addrs[2], TokenID: fx.TokenID, Owner: fx.Addrs[1], To: fx.Addrs[2], Amount: amount.NewCoinAmount(50, 0)}
+		}, nil, []int64{0, 50, 50}, 100, 0},
+		{"transfer from over the allowance", 2, func(fx *tokenFixture) types.Transaction {
+			return &TransferFrom{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[2]), From_: fx.Addrs[2], TokenID: fx.TokenID, Owner: fx.Addrs[1], To: fx.Addrs[2], Amount: amount.NewCoinAmount(51, 0)}
+		}, ErrInsufficientAllowance, []int64{0, 100, 0}, 100, 50},
+		{"transfer from without the allowance", 0, func(fx *tokenFixture) types.Transaction {
+			return &TransferFrom{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[0]), From_: fx.Addrs[0], TokenID: fx.TokenID, Owner: fx.Addrs[1], To: fx.Addrs[2], Amount: amount.NewCoinAmount(1, 0)}
+		}, ErrInsufficientAllowance, []int64{0, 100, 0}, 100, 50},
+		{"approve zero", 1, func(fx *tokenFixture) types.Transaction {
+			return &Approve{Seq_: processtest.NextSeq(fx.ctx, fx.Addrs[1]), From_: fx.Addrs[1], TokenID: fx.TokenID, Spender: fx.Addrs[2], Amount: amount.NewCoinAmount(0, 0)}
+		}, nil, []int64{0, 100, 0}, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newTokenFixture(t)
+			tx := tt.tx(fx)
+			From := tx.(vault.FeeTransaction).From()
+			Fee := amount.COIN.DivC(10)
+			Before := fx.vp.Balance(fx.ctx, From)
+			Seq := fx.ctx.Seq(From)
+
+			if err := processtest.Execute(fx.ctx, fx.p, tx, fx.Keys[tt.signer]); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			for i, v := range tt.balances {
+				if am := fx.p.Balance(fx.ctx, fx.TokenID, fx.Addrs[i]); !am.Equal(amount.NewCoinAmount(uint64(v), 0)) {
+					t.Fatalf("invalid balance of %v: %v, expected %v", i, am, v)
+				}
+			}
+			if am := fx.p.TotalSupply(fx.ctx, fx.TokenID); !am.Equal(amount.NewCoinAmount(uint64(tt.supply), 0)) {
+				t.Fatalf("invalid total supply %v, expected %v", am, tt.supply)
+			}
+			if am := fx.p.Allowance(fx.ctx, fx.TokenID, fx.Addrs[1], fx.Addrs[2]); !am.Equal(amount.NewCoinAmount(uint64(tt.allowance), 0)) {
+				t.Fatalf("invalid allowance %v, expected %v", am, tt.allowance)
+			}
+			Expected := Before
+			if tt.err == nil {
+				Expected = Before.Sub(Fee)
+				Seq++
+			}
+			if v := fx.ctx.Seq(From); v != Seq {
+				t.Fatalf("invalid sequence %v, expected %v", v, Seq)
+			}
+			if am := fx.vp.Balance(fx.ctx, From); !am.Equal(Expected) {
+				t.Fatalf("invalid coin balance %v, expected %v", am, Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Approve sets the amount that the spender can transfer from the balance of the sender
+// The zero amount removes the allowance of the spender
+type Approve struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	TokenID    uint64
+	Spender    common.Address
+	Amount     *amount.Amount
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Approve) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Approve) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Approve) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Approve) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Approve) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Token)
+
+	ti, err := sp.TokenInfo(loader, tx.TokenID)
+	if err != nil {
+		return err
+	}
+	if tx.Amount == nil || tx.Amount.Int == nil {
+		return ErrInvalidAmount
+	}
+	if !tx.Amount.IsZero() && !IsValidAmount(ti, tx.Amount) {
+		return ErrInvalidAmount
+	}
+	if tx.From() == tx.Spender {
+		return ErrNotAllowedSelfApproval
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if has, err := loader.HasAccount(tx.Spender); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Approve) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Token)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		sp.setAllowance(ctw, tx.TokenID, tx.From(), tx.Spender, tx.Amount)
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Approve) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_id":`)
+	if bs, err := json.Marshal(tx.TokenID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"spender":`)
+	if bs, err := tx.Spender.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Burn is a Burn
+type Burn struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	TokenID    uint64
+	Amount     *amount.Amount
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Burn) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Burn) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Burn) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Burn) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Burn) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Token)
+
+	ti, err := sp.TokenInfo(loader, tx.TokenID)
+	if err != nil {
+		return err
+	}
+	if !IsValidAmount(ti, tx.Amount) {
+		return ErrInvalidAmount
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if sp.Balance(loader, tx.TokenID, tx.From()).Less(tx.Amount) {
+		return ErrInsufficientBalance
+	}
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Burn) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Token)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		if err := sp.transfer(ctw, index, tx.TokenID, tx.From(), common.Address{}, tx.Amount); err != nil {
+			return err
+		}
+		sp.setTotalSupply(ctw, tx.TokenID, sp.TotalSupply(ctw, tx.TokenID).Sub(tx.Amount))
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Burn) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_id":`)
+	if bs, err := json.Marshal(tx.TokenID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,172 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// CreateToken is a CreateToken
+type CreateToken struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	Symbol     string
+	Name       string
+	Decimals   uint8
+	MaxSupply  *amount.Amount
+	Issuer     common.Address
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *CreateToken) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *CreateToken) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *CreateToken) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *CreateToken) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// TokenInfo returns the metadata of the token that is created by the transaction
+func (tx *CreateToken) TokenInfo() *TokenInfo {
+	return &TokenInfo{
+		Symbol:    tx.Symbol,
+		Name:      tx.Name,
+		Decimals:  tx.Decimals,
+		MaxSupply: tx.MaxSupply,
+		Issuer:    tx.Issuer,
+		Creator:   tx.From(),
+	}
+}
+
+// Validate validates signatures of the transaction
+func (tx *CreateToken) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Token)
+
+	if !IsValidSymbol(tx.Symbol) {
+		return ErrInvalidSymbol
+	}
+	if len(tx.Name) == 0 || len(tx.Name) > 64 {
+		return ErrInvalidTokenName
+	}
+	if tx.Decimals > amount.FractionalCount {
+		return ErrInvalidDecimals
+	}
+	if !IsValidAmount(tx.TokenInfo(), tx.MaxSupply) {
+		return ErrInvalidMaxSupply
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if sp.HasToken(loader, TokenID(tx.Symbol)) {
+		return ErrExistToken
+	}
+	if has, err := loader.HasAccount(tx.Issuer); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *CreateToken) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Token)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		if err := sp.addToken(ctw, tx.TokenInfo()); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *CreateToken) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"symbol":`)
+	if bs, err := json.Marshal(tx.Symbol); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(tx.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"decimals":`)
+	if bs, err := json.Marshal(tx.Decimals); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"max_supply":`)
+	if bs, err := tx.MaxSupply.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"issuer":`)
+	if bs, err := tx.Issuer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
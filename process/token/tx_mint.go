@@ -0,0 +1,151 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// Mint is a Mint
+type Mint struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	TokenID    uint64
+	To         common.Address
+	Amount     *amount.Amount
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Mint) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Mint) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Mint) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Mint) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *Mint) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Token)
+
+	ti, err := sp.TokenInfo(loader, tx.TokenID)
+	if err != nil {
+		return err
+	}
+	if tx.From() != ti.Issuer {
+		return ErrNotTokenIssuer
+	}
+	if !IsValidAmount(ti, tx.Amount) {
+		return ErrInvalidAmount
+	}
+	if ti.MaxSupply.Less(sp.TotalSupply(loader, tx.TokenID).Add(tx.Amount)) {
+		return ErrExceedMaxSupply
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if has, err := loader.HasAccount(tx.To); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.vault.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Mint) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Token)
+
+	return sp.vault.WithFee(ctw, tx, func() error {
+		ti, err := sp.TokenInfo(ctw, tx.TokenID)
+		if err != nil {
+			return err
+		}
+		TotalSupply := sp.TotalSupply(ctw, tx.TokenID).Add(tx.Amount)
+		if ti.MaxSupply.Less(TotalSupply) {
+			return ErrExceedMaxSupply
+		}
+		sp.setTotalSupply(ctw, tx.TokenID, TotalSupply)
+		if err := sp.transfer(ctw, index, tx.TokenID, common.Address{}, tx.To, tx.Amount); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Mint) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_id":`)
+	if bs, err := json.Marshal(tx.TokenID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to":`)
+	if bs, err := tx.To.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/util"
+)
+
+// TokenID returns the token id of the symbol
+func TokenID(Symbol string) uint64 {
+	h := hash.Hash([]byte("fleta.token#TokenID#" + Symbol))
+	return util.BytesToUint64(h[:])
+}
+
+// IsValidSymbol returns the symbol is composed of 2~12 upper case letters or digits that starts with a letter
+func IsValidSymbol(Symbol string) bool {
+	if len(Symbol) < 2 || len(Symbol) > 12 {
+		return false
+	}
+	for i, c := range []byte(Symbol) {
+		if c >= 'A' && c <= 'Z' {
+			continue
+		}
+		if i > 0 && c >= '0' && c <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package token
+
+import (
+	"encoding/binary"
+
+	"github.com/fletaio/fleta_testnet/common"
+)
+
+// tags
+var (
+	tagToken       = []byte{1, 0}
+	tagTotalSupply = []byte{1, 1}
+	tagBalance     = []byte{2, 0}
+	tagAllowance   = []byte{3, 0}
+)
+
+func toTokenKey(TokenID uint64) []byte {
+	bs := make([]byte, 10)
+	copy(bs, tagToken)
+	binary.BigEndian.PutUint64(bs[2:], TokenID)
+	return bs
+}
+
+func toTotalSupplyKey(TokenID uint64) []byte {
+	bs := make([]byte, 10)
+	copy(bs, tagTotalSupply)
+	binary.BigEndian.PutUint64(bs[2:], TokenID)
+	return bs
+}
+
+func toBalanceKey(TokenID uint64) []byte {
+	bs := make([]byte, 10)
+	copy(bs, tagBalance)
+	binary.BigEndian.PutUint64(bs[2:], TokenID)
+	return bs
+}
+
+func toAllowanceKey(TokenID uint64, Spender common.Address) []byte {
+	bs := make([]byte, 10+common.AddressSize)
+	copy(bs, tagAllowance)
+	binary.BigEndian.PutUint64(bs[2:], TokenID)
+	copy(bs[10:], Spender[:])
+	return bs
+}
@@ -7,6 +7,7 @@ var (
 	ErrMinusInput                       = errors.New("minus input")
 	ErrMinusBalance                     = errors.New("minus balance")
 	ErrMinusCollectedFee                = errors.New("minus collected fee")
+	ErrMinusHeldBalance                 = errors.New("minus held balance")
 	ErrInvalidMultiKeyHashCount         = errors.New("invalid multi key hash count")
 	ErrInvalidRequiredKeyHashCount      = errors.New("invalid required key hash count")
 	ErrInvalidLockedBalanceKey          = errors.New("invalid locked balance key")
@@ -15,4 +16,30 @@ var (
 	ErrInsufficientBalance              = errors.New("insufficient balance")
 	ErrNotExistFeeOfTransaction         = errors.New("not exist fee of transaction")
 	ErrPolicyShouldBeSetupInApplication = errors.New("policy should be setup in application")
+	ErrInvalidUnlockHeight              = errors.New("invalid unlock height")
+	ErrInvalidVestingSchedule           = errors.New("invalid vesting schedule")
+	ErrTooManyVestingTranches           = errors.New("too many vesting tranches")
+	ErrNotMultiAccount                  = errors.New("not multi account")
+	ErrNotMultiAccountKeyHolder         = errors.New("not multi account key holder")
+	ErrInvalidProposedTransaction       = errors.New("invalid proposed transaction")
+	ErrInvalidExpireHeight              = errors.New("invalid expire height")
+	ErrExistMultiProposal               = errors.New("exist multi proposal")
+	ErrNotExistMultiProposal            = errors.New("not exist multi proposal")
+	ErrExpiredMultiProposal             = errors.New("expired multi proposal")
+	ErrAlreadyApprovedMultiProposal     = errors.New("already approved multi proposal")
+	ErrNotSingleAccount                 = errors.New("not single account")
+	ErrSameKeyHash                      = errors.New("same key hash")
+	ErrInvalidRecoveryDelay             = errors.New("invalid recovery delay")
+	ErrNotExistRecoveryKey              = errors.New("not exist recovery key")
+	ErrNotExistRecoveryRequest          = errors.New("not exist recovery request")
+	ErrNotActivatedRecoveryRequest      = errors.New("not activated recovery request")
+	ErrInvalidOutputCount               = errors.New("invalid output count")
+	ErrNotOwnedAccountName              = errors.New("not owned account name")
+	ErrTooManyAccountNames              = errors.New("too many account names")
+	ErrInvalidToAddress                 = errors.New("invalid to address")
+	ErrMinusSupplyTotal                 = errors.New("minus supply total")
+	ErrInvalidUpgradeKind               = errors.New("invalid upgrade kind")
+	ErrScheduledUpgrade                 = errors.New("scheduled upgrade")
+	ErrNotUpgraded                      = errors.New("not upgraded")
+	ErrInvalidSeedHeight                = errors.New("invalid seed height")
 )
@@ -0,0 +1,35 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// LockedBalance is the pending locked balance that is released at the unlock height
+type LockedBalance struct {
+	UnlockHeight uint32
+	Amount       *amount.Amount
+}
+
+// MarshalJSON is a marshaler function
+func (lb *LockedBalance) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"unlock_height":`)
+	if bs, err := json.Marshal(lb.UnlockHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := lb.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// MaxMultiProposalPeriod is the maximum number of blocks that a multi account proposal can be pending
+const MaxMultiProposalPeriod = 172800 * 7 // 7 days
+
+// MultiProposal is a transaction of the multi account that is waiting for approvals of key holders
+type MultiProposal struct {
+	Address      common.Address
+	Proposer     common.PublicHash
+	TxType       uint16
+	Payload      []byte
+	ExpireHeight uint32
+	Approvals    []common.PublicHash
+}
+
+// Transaction returns the proposed transaction
+func (mp *MultiProposal) Transaction() (types.Transaction, error) {
+	return decodeProposedTransaction(mp.Address, mp.TxType, mp.Payload)
+}
+
+// IsApproved returns the key holder has approved the proposal or not
+func (mp *MultiProposal) IsApproved(pubhash common.PublicHash) bool {
+	for _, v := range mp.Approvals {
+		if v == pubhash {
+			return true
+		}
+	}
+	return false
+}
+
+// Signers returns signers that satisfy the signature validation of the multi account by approvals
+// A multi account validates exactly the required number of key holders among the signer list of the key count
+func (mp *MultiProposal) Signers(acc *MultiAccount) []common.PublicHash {
+	signers := make([]common.PublicHash, 0, len(acc.KeyHashes))
+	for _, pubhash := range mp.Approvals {
+		if len(signers) >= int(acc.Required) {
+			break
+		}
+		signers = append(signers, pubhash)
+	}
+	for len(signers) < len(acc.KeyHashes) {
+		signers = append(signers, common.PublicHash{})
+	}
+	return signers
+}
+
+func decodeProposedTransaction(addr common.Address, t uint16, Payload []byte) (types.Transaction, error) {
+	v, err := encoding.Factory("transaction").Create(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := encoding.Unmarshal(Payload, v); err != nil {
+		return nil, err
+	}
+	tx, is := v.(types.Transaction)
+	if !is {
+		return nil, ErrInvalidProposedTransaction
+	}
+	switch tx.(type) {
+	case *ProposeMultiTransaction, *ApproveMultiTransaction, *CancelMultiTransaction:
+		return nil, ErrInvalidProposedTransaction
+	}
+	if ft, is := tx.(FeeTransaction); !is {
+		return nil, ErrInvalidProposedTransaction
+	} else if ft.From() != addr {
+		return nil, ErrInvalidProposedTransaction
+	}
+	return tx, nil
+}
+
+func isMultiAccountKeyHolder(acc *MultiAccount, pubhash common.PublicHash) bool {
+	for _, v := range acc.KeyHashes {
+		if v == pubhash {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON is a marshaler function
+func (mp *MultiProposal) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"address":`)
+	if bs, err := mp.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"proposer":`)
+	if bs, err := mp.Proposer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tx_type":`)
+	if bs, err := json.Marshal(mp.TxType); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tx":`)
+	if tx, err := mp.Transaction(); err != nil {
+		return nil, err
+	} else if bs, err := tx.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"expire_height":`)
+	if bs, err := json.Marshal(mp.ExpireHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"approvals":`)
+	buffer.WriteString(`[`)
+	for i, pubhash := range mp.Approvals {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := pubhash.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,256 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// newMultiFixture creates alice, accounts of 3 key holders and the multi account of them that has 100 coins
+// Accounts of key holders are signed by the same keys of the multi account to pay fees of proposals
+func newMultiFixture(t *testing.T, Required uint8) (*txFixture, *MultiAccount) {
+	fx := newTxFixture(t, "alice", "holder1", "holder2", "holder3")
+	acc := &MultiAccount{
+		Address_:  common.NewAddress(0, 100, 0),
+		Name_:     "multi",
+		Required:  Required,
+		KeyHashes: fx.Keys[1:4],
+	}
+	ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+	if err := ctw.CreateAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.p.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	return fx, acc
+}
+
+// propose proposes the transaction of the multi account by the first key holder that pays the fee
+func (fx *txFixture) propose(t *testing.T, acc *MultiAccount, TxType uint8, tx types.Transaction, ExpireHeight uint32) (*ProposeMultiTransaction, error) {
+	bs, err := encoding.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ptx := &ProposeMultiTransaction{
+		Seq_:         fx.seq(fx.Addrs[1]),
+		From_:        fx.Addrs[1],
+		Address:      acc.Address(),
+		Proposer:     acc.KeyHashes[0],
+		TxType:       uint16(fx.p.ID())<<8 | uint16(TxType),
+		Payload:      bs,
+		ExpireHeight: ExpireHeight,
+	}
+	return ptx, fx.execute(ptx, ptx.Proposer)
+}
+
+// proposeTransfer proposes the transfer from the multi account to alice
+func (fx *txFixture) proposeTransfer(t *testing.T, acc *MultiAccount, am *amount.Amount, ExpireHeight uint32) (*ProposeMultiTransaction, error) {
+	return fx.propose(t, acc, 1, &Transfer{
+		From_:  acc.Address(),
+		To:     fx.Addrs[0],
+		Amount: am,
+	}, ExpireHeight)
+}
+
+func TestMultiProposal_Approve(t *testing.T) {
+	Outsider := common.PublicHash{99}
+	tests := []struct {
+		name      string
+		required  uint8
+		approvers []common.PublicHash
+		height    uint32
+		amount    int64
+		err       error
+		executed  bool
+		pending   int
+	}{
+		{"executed by the proposal", 1, nil, 10, 10, nil, true, 0},
+		{"executed at the required approvals", 2, []common.PublicHash{{3}}, 10, 10, nil, true, 0},
+		{"executed by all key holders", 3, []common.PublicHash{{3}, {4}}, 10, 10, nil, true, 0},
+		{"pending under the required approvals", 3, []common.PublicHash{{4}}, 10, 10, nil, false, 2},
+		{"approved by the proposer again", 2, []common.PublicHash{{2}}, 10, 10, ErrAlreadyApprovedMultiProposal, false, 1},
+		{"approved by the outsider", 2, []common.PublicHash{Outsider}, 10, 10, ErrNotMultiAccountKeyHolder, false, 1},
+		{"approved before the expire height", 2, []common.PublicHash{{3}}, 19, 10, nil, true, 0},
+		{"approved at the expire height", 2, []common.PublicHash{{3}}, 20, 10, ErrExpiredMultiProposal, false, 1},
+		{"approved after the expire height", 2, []common.PublicHash{{3}}, 21, 10, ErrNotExistMultiProposal, false, 0},
+		{"inner transaction over the balance", 2, []common.PublicHash{{3}}, 10, 100, ErrInsufficientFee, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx, acc := newMultiFixture(t, tt.required)
+			fx.advance(t, 10)
+
+			Fee := amount.COIN.DivC(10)
+			am := amount.NewCoinAmount(uint64(tt.amount), 0)
+			ptx, err := fx.proposeTransfer(t, acc, am, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ID := ptx.ProposalID()
+			Paid := amount.NewCoinAmount(0, 0)
+			fx.advance(t, tt.height)
+
+			var last error
+			for _, Approver := range tt.approvers {
+				last = fx.execute(&ApproveMultiTransaction{
+					From_:      acc.Address(),
+					ProposalID: ID,
+					Approver:   Approver,
+				}, Approver)
+				if last == nil {
+					Paid = Paid.Add(Fee)
+				}
+			}
+			if last != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, last)
+			}
+
+			if tt.executed {
+				checkBalance(t, fx.p, fx.ctx, fx.Addrs[0], amount.NewCoinAmount(100, 0).Add(am))
+				Paid = Paid.Add(am).Add(Fee)
+			} else {
+				checkBalance(t, fx.p, fx.ctx, fx.Addrs[0], amount.NewCoinAmount(100, 0))
+			}
+			checkBalance(t, fx.p, fx.ctx, acc.Address(), amount.NewCoinAmount(100, 0).Sub(Paid))
+			checkBalance(t, fx.p, fx.ctx, fx.Addrs[1], amount.NewCoinAmount(100, 0).Sub(Fee))
+
+			mp, err := fx.p.MultiProposal(fx.ctx, ID)
+			if tt.pending == 0 {
+				if err != ErrNotExistMultiProposal {
+					t.Fatalf("proposal is remained %v", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if len(mp.Approvals) != tt.pending {
+				t.Fatalf("invalid approval count %v, expected %v", len(mp.Approvals), tt.pending)
+			}
+			if !fx.p.IsUsedMultiProposalID(fx.ctx, ID) {
+				t.Fatal("proposal id is not marked as used")
+			}
+		})
+	}
+}
+
+func TestMultiProposal_Cancel(t *testing.T) {
+	fx, acc := newMultiFixture(t, 2)
+	ptx, err := fx.proposeTransfer(t, acc, amount.NewCoinAmount(10, 0), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ID := ptx.ProposalID()
+
+	// other key holder cannot cancel the proposal by the own account
+	if err := fx.execute(&CancelMultiTransaction{
+		Seq_:       fx.seq(fx.Addrs[2]),
+		From_:      fx.Addrs[2],
+		ProposalID: ID,
+	}, acc.KeyHashes[1]); err != types.ErrInvalidAccountSigner {
+		t.Fatalf("expected %v but %v", types.ErrInvalidAccountSigner, err)
+	}
+	if err := fx.execute(&CancelMultiTransaction{
+		Seq_:       fx.seq(fx.Addrs[1]),
+		From_:      fx.Addrs[1],
+		ProposalID: ID,
+	}, acc.KeyHashes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fx.p.MultiProposal(fx.ctx, ID); err != ErrNotExistMultiProposal {
+		t.Fatalf("canceled proposal is remained %v", err)
+	}
+	// fees of the proposal and the cancel are paid by the proposer, not by the multi account
+	checkBalance(t, fx.p, fx.ctx, fx.Addrs[1], amount.MustParseAmount("99.8"))
+	checkBalance(t, fx.p, fx.ctx, acc.Address(), amount.NewCoinAmount(100, 0))
+
+	// the canceled proposal cannot be proposed again
+	if err := fx.execute(ptx, ptx.Proposer); err != types.ErrInvalidSequence {
+		t.Fatalf("expected %v but %v", types.ErrInvalidSequence, err)
+	}
+}
+
+func TestMultiProposal_FeeOfProposer(t *testing.T) {
+	fx, acc := newMultiFixture(t, 2)
+
+	// the proposer cannot pay the fee by the account of other key
+	ptx := &ProposeMultiTransaction{
+		Seq_:         fx.seq(fx.Addrs[2]),
+		From_:        fx.Addrs[2],
+		Address:      acc.Address(),
+		Proposer:     acc.KeyHashes[0],
+		TxType:       uint16(fx.p.ID())<<8 | 1,
+		ExpireHeight: 20,
+	}
+	bs, err := encoding.Marshal(&Transfer{
+		From_:  acc.Address(),
+		To:     fx.Addrs[0],
+		Amount: amount.NewCoinAmount(10, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ptx.Payload = bs
+	if err := fx.execute(ptx, acc.KeyHashes[0]); err != types.ErrInvalidAccountSigner {
+		t.Fatalf("expected %v but %v", types.ErrInvalidAccountSigner, err)
+	}
+
+	// the proposer without the balance cannot propose even though the multi account has the balance
+	ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+	if err := fx.p.SubBalance(ctw, fx.Addrs[1], amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fx.proposeTransfer(t, acc, amount.NewCoinAmount(10, 0), 20); err != ErrInsufficientFee {
+		t.Fatalf("expected %v but %v", ErrInsufficientFee, err)
+	}
+	checkBalance(t, fx.p, fx.ctx, acc.Address(), amount.NewCoinAmount(100, 0))
+}
+
+func TestMultiProposal_SequenceOfInnerTransaction(t *testing.T) {
+	fx, acc := newMultiFixture(t, 2)
+	fx.advance(t, 10)
+
+	lockedTransfer := func(Seq uint64) *LockedTransfer {
+		return &LockedTransfer{
+			Seq_:         Seq,
+			From_:        acc.Address(),
+			To:           fx.Addrs[0],
+			Amount:       amount.NewCoinAmount(10, 0),
+			UnlockHeight: 30,
+		}
+	}
+	approve := func(ID hash.Hash256) error {
+		return fx.execute(&ApproveMultiTransaction{
+			From_:      acc.Address(),
+			ProposalID: ID,
+			Approver:   acc.KeyHashes[1],
+		}, acc.KeyHashes[1])
+	}
+
+	ptx, err := fx.propose(t, acc, 11, lockedTransfer(1), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the proposal of the same sequence is pending until the first one is executed
+	replay, err := fx.propose(t, acc, 11, lockedTransfer(1), 21)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := approve(ptx.ProposalID()); err != nil {
+		t.Fatal(err)
+	}
+	if seq := fx.ctx.Seq(acc.Address()); seq != 1 {
+		t.Fatalf("invalid sequence of the multi account %v", seq)
+	}
+	if am := fx.p.LockedBalance(fx.ctx, fx.Addrs[0], 30); !am.Equal(amount.NewCoinAmount(10, 0)) {
+		t.Fatalf("invalid locked balance %v", am)
+	}
+	if err := approve(replay.ProposalID()); err != types.ErrInvalidSequence {
+		t.Fatalf("expected %v but %v", types.ErrInvalidSequence, err)
+	}
+	if seq := fx.ctx.Seq(acc.Address()); seq != 1 {
+		t.Fatalf("invalid sequence of the multi account %v", seq)
+	}
+}
@@ -0,0 +1,111 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+)
+
+func newNameFixture(t *testing.T, Upgraded bool) (*Vault, *types.Context, *types.ContextWrapper, []types.Account) {
+	p := NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), p); err != nil {
+		t.Fatal(err)
+	}
+	ctx := types.NewEmptyContext()
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+	if Upgraded {
+		if err := p.InitUpgrades(ctw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accs := []types.Account{}
+	for i, Name := range []string{"alice", "bob"} {
+		acc := &SingleAccount{
+			Address_: common.NewAddress(0, uint16(i+1), 0),
+			Name_:    Name,
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		accs = append(accs, acc)
+	}
+	return p, ctx, ctw, accs
+}
+
+func checkNameOwner(t *testing.T, ctw *types.ContextWrapper, Name string, addr common.Address) {
+	t.Helper()
+	if v, err := ctw.AddressByName(Name); err != nil || v != addr {
+		t.Fatalf("%v is bound to %v, %v", Name, v, err)
+	}
+}
+
+func TestName_TransferCreationName(t *testing.T) {
+	p, _, ctw, accs := newNameFixture(t, false)
+	alice, bob := accs[0].Address(), accs[1].Address()
+
+	if err := p.releaseAccountName(ctw, alice, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.registerAccountName(ctw, bob, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	checkNameOwner(t, ctw, "alice", bob)
+	if has, err := p.HasAccountNameOf(ctw, alice, "alice"); err != nil || has {
+		t.Fatalf("the transferred creation name is owned by the creator %v", err)
+	}
+	if has, err := p.HasAccountNameOf(ctw, bob, "alice"); err != nil || !has {
+		t.Fatalf("the transferred creation name is not owned by the receiver %v", err)
+	}
+	if err := p.releaseAccountName(ctw, alice, "alice"); err != ErrNotOwnedAccountName {
+		t.Fatalf("expected %v but %v", ErrNotOwnedAccountName, err)
+	}
+
+	// deleting the creator keeps the name of the receiver
+	if err := p.ReleaseAccountNames(ctw, accs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctw.DeleteAccount(accs[0]); err != nil {
+		t.Fatal(err)
+	}
+	checkNameOwner(t, ctw, "alice", bob)
+}
+
+func TestName_ReleaseAccountNames(t *testing.T) {
+	tests := []struct {
+		name             string
+		upgraded         bool
+		creationReleased bool
+	}{
+		{"before the upgrade", false, false},
+		{"after the upgrade", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ctx, ctw, accs := newNameFixture(t, tt.upgraded)
+			alice := accs[0].Address()
+			if err := p.registerAccountName(ctw, alice, "alice.extra"); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.ReleaseAccountNames(ctw, accs[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := ctw.DeleteAccount(accs[0]); err != nil {
+				t.Fatal(err)
+			}
+			if has, err := ctw.HasAccountName("alice.extra"); err != nil || has {
+				t.Fatalf("the registered name is not released %v", err)
+			}
+			// the store keeps the binding of the deleted account unless the name is released
+			if ctx.Top().DeletedAccountNameMap.Has("alice") != tt.creationReleased {
+				t.Fatalf("the creation name is released %v", !tt.creationReleased)
+			}
+			if names, err := p.AccountNames(ctw, alice); err != nil || len(names) != 0 {
+				t.Fatalf("names are remained %v, %v", names, err)
+			}
+		})
+	}
+}
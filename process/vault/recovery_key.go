@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+)
+
+// recovery delay limits
+const (
+	MinRecoveryDelay = 172800      // 1 day
+	MaxRecoveryDelay = 172800 * 30 // 30 days
+)
+
+// RecoveryKey is the key that can rotate the key of the single account after the delay
+type RecoveryKey struct {
+	KeyHash common.PublicHash
+	Delay   uint32
+}
+
+// MarshalJSON is a marshaler function
+func (rk *RecoveryKey) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := rk.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"delay":`)
+	if bs, err := json.Marshal(rk.Delay); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// RecoveryRequest is the key rotation that is requested by the recovery key
+// The current key can cancel it until the activation height
+type RecoveryRequest struct {
+	KeyHash          common.PublicHash
+	ActivationHeight uint32
+}
+
+// MarshalJSON is a marshaler function
+func (rr *RecoveryRequest) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := rr.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"activation_height":`)
+	if bs, err := json.Marshal(rr.ActivationHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
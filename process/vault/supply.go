@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"bytes"
+
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+// Supply has running totals of the coin that are maintained by the vault
+// The total supply is the sum of every other total except the burned amount
+// and the circulating supply is the balance that is not locked, staked or held
+type Supply struct {
+	TotalSupply      *amount.Amount
+	Balance          *amount.Amount
+	LockedBalance    *amount.Amount
+	FormulatorAmount *amount.Amount
+	StakingAmount    *amount.Amount
+	UnstakingAmount  *amount.Amount
+	HeldBalance      *amount.Amount
+	CollectedFee     *amount.Amount
+	BurnedAmount     *amount.Amount
+}
+
+// MarshalJSON is a marshaler function
+func (s *Supply) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"total_supply":`)
+	if bs, err := s.TotalSupply.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"circulating_supply":`)
+	if bs, err := s.Balance.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"locked_balance":`)
+	if bs, err := s.LockedBalance.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"formulator_amount":`)
+	if bs, err := s.FormulatorAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"staking_amount":`)
+	if bs, err := s.StakingAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"unstaking_amount":`)
+	if bs, err := s.UnstakingAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"held_balance":`)
+	if bs, err := s.HeldBalance.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"collected_fee":`)
+	if bs, err := s.CollectedFee.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"burned_amount":`)
+	if bs, err := s.BurnedAmount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,209 @@
+package vault
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+)
+
+// scanSupply recomputes the balance and the locked balance totals from the full account scan
+func scanSupply(t *testing.T, p *Vault, ctx *types.Context) (*amount.Amount, *amount.Amount) {
+	addrs, err := ctx.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Balance := amount.NewCoinAmount(0, 0)
+	LockedBalance := amount.NewCoinAmount(0, 0)
+	for _, addr := range addrs {
+		if has, err := ctx.HasAccount(addr); err != nil {
+			t.Fatal(err)
+		} else if has {
+			Balance = Balance.Add(p.Balance(ctx, addr))
+		}
+		for _, UnlockedHeight := range p.LockedBalanceHeights(ctx, addr) {
+			LockedBalance = LockedBalance.Add(p.LockedBalance(ctx, addr, UnlockedHeight))
+		}
+	}
+	return Balance, LockedBalance
+}
+
+func checkSupply(t *testing.T, p *Vault, ctx *types.Context, Minted *amount.Amount) {
+	t.Helper()
+	s, err := p.Supply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Balance, LockedBalance := scanSupply(t, p, ctx)
+	if !s.Balance.Equal(Balance) {
+		t.Fatalf("balance total %v, scanned %v", s.Balance, Balance)
+	}
+	if !s.LockedBalance.Equal(LockedBalance) {
+		t.Fatalf("locked balance total %v, scanned %v", s.LockedBalance, LockedBalance)
+	}
+	if !s.TotalSupply.Add(s.BurnedAmount).Equal(Minted) {
+		t.Fatalf("total supply %v with burned %v, minted %v", s.TotalSupply, s.BurnedAmount, Minted)
+	}
+}
+
+func TestSupply_Invariant(t *testing.T) {
+	p := NewVault(1)
+	ctx := types.NewEmptyContext()
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+	if err := p.InitUpgrades(ctw); err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := []common.Address{}
+	for i := 1; i <= 5; i++ {
+		acc := &SingleAccount{
+			Address_: common.NewAddress(0, uint16(i), 0),
+			Name_:    "account" + strconv.Itoa(i),
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, acc.Address())
+	}
+
+	Minted := amount.NewCoinAmount(0, 0)
+	for _, addr := range addrs {
+		am := amount.NewCoinAmount(1000, 0)
+		if err := p.AddBalance(ctw, addr, am); err != nil {
+			t.Fatal(err)
+		}
+		Minted = Minted.Add(am)
+	}
+	checkSupply(t, p, ctx, Minted)
+
+	if err := p.SubBalance(ctw, addrs[0], amount.NewCoinAmount(300, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddBalance(ctw, addrs[1], amount.NewCoinAmount(300, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SubBalance(ctw, addrs[2], amount.NewCoinAmount(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddLockedBalance(ctw, addrs[3], 10, amount.NewCoinAmount(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(t, p, ctx, Minted)
+
+	if err := p.Hold(ctw, addrs[1], amount.NewCoinAmount(500, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Release(ctw, addrs[4], amount.NewCoinAmount(200, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SubBalance(ctw, addrs[4], amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddBurnedAmount(ctw, amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SubBalance(ctw, addrs[0], amount.NewCoinAmount(50, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddFormulatorAmount(ctw, amount.NewCoinAmount(50, 0)); err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(t, p, ctx, Minted)
+
+	b := &types.Block{}
+	b.Header.Height = 10
+	if err := p.AfterExecuteTransactions(b, ctw); err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(t, p, ctx, Minted)
+
+	s, err := p.Supply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.LockedBalance.IsZero() {
+		t.Fatalf("locked balance is not released %v", s.LockedBalance)
+	}
+	if !s.Balance.Equal(Minted.Sub(amount.NewCoinAmount(300+100+50, 0))) {
+		t.Fatalf("invalid circulating supply %v", s.Balance)
+	}
+
+	if err := p.SubBalance(ctw, addrs[0], amount.NewCoinAmount(10000, 0)); err != ErrMinusBalance {
+		t.Fatalf("invalid sub balance error %v", err)
+	}
+}
+
+func TestSupply_SeededAtActivationHeight(t *testing.T) {
+	p := NewVault(1)
+	ctx := types.NewEmptyContext()
+	ctw := types.NewContextWrapper(p.ID(), ctx)
+
+	addrs := []common.Address{}
+	for i := 1; i <= 3; i++ {
+		acc := &SingleAccount{
+			Address_: common.NewAddress(0, uint16(i), 0),
+			Name_:    "account" + strconv.Itoa(i),
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(1000, 0)); err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, acc.Address())
+	}
+	if err := p.SubBalance(ctw, addrs[0], amount.NewCoinAmount(400, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddLockedBalance(ctw, addrs[1], 20, amount.NewCoinAmount(400, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddLockedBalance(ctw, addrs[2], 30, amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if acc, err := ctw.Account(addrs[2]); err != nil {
+		t.Fatal(err)
+	} else if err := ctw.DeleteAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	// balances of live accounts and every pending locked balance including the one of the deleted account
+	Seeded := amount.NewCoinAmount(600+1000+400+100, 0)
+
+	tx := &ScheduleUpgrade{Kind: SupplyUpgrade, ActivationHeight: 10}
+	if err := tx.Execute(p, ctw, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Supply(ctx); err != ErrNotUpgraded {
+		t.Fatalf("expected %v but %v", ErrNotUpgraded, err)
+	}
+	if bs := ctx.ProcessData(p.ID(), tagTotalBalance); len(bs) > 0 {
+		t.Fatalf("the total is written before the upgrade")
+	}
+
+	ctx = processtest.Advance(ctx, 10)
+	ctw = types.NewContextWrapper(p.ID(), ctx)
+	if err := p.BeforeExecuteTransactions(ctw); err != nil {
+		t.Fatal(err)
+	}
+	checkSupply(t, p, ctx, Seeded)
+
+	for _, h := range []uint32{20, 30} {
+		ctx = processtest.Advance(ctx, h)
+		ctw = types.NewContextWrapper(p.ID(), ctx)
+		b := &types.Block{}
+		b.Header.Height = h
+		if err := p.AfterExecuteTransactions(b, ctw); err != nil {
+			t.Fatal(err)
+		}
+		checkSupply(t, p, ctx, Seeded)
+	}
+	// the locked balance of the deleted account is burned when it is unlocked
+	if s, err := p.Supply(ctx); err != nil {
+		t.Fatal(err)
+	} else if !s.BurnedAmount.Equal(amount.NewCoinAmount(100, 0)) || !s.LockedBalance.IsZero() {
+		t.Fatalf("invalid supply after unlocks %v, %v", s.BurnedAmount, s.LockedBalance)
+	}
+}
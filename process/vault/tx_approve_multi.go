@@ -0,0 +1,125 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// ApproveMultiTransaction approves the pending proposal of the multi account
+// The proposed transaction is executed by the approval that fills the required count
+type ApproveMultiTransaction struct {
+	Timestamp_ uint64
+	From_      common.Address
+	ProposalID hash.Hash256
+	Approver   common.PublicHash
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *ApproveMultiTransaction) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// From returns the from address of the transaction
+func (tx *ApproveMultiTransaction) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *ApproveMultiTransaction) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *ApproveMultiTransaction) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	mp, err := sp.MultiProposal(loader, tx.ProposalID)
+	if err != nil {
+		return err
+	}
+	if mp.Address != tx.From() {
+		return ErrNotExistMultiProposal
+	}
+	if mp.ExpireHeight <= loader.TargetHeight() {
+		return ErrExpiredMultiProposal
+	}
+	if mp.IsApproved(tx.Approver) {
+		return ErrAlreadyApprovedMultiProposal
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	multiAcc, is := acc.(*MultiAccount)
+	if !is {
+		return ErrNotMultiAccount
+	}
+	if len(signers) != 1 || signers[0] != tx.Approver {
+		return types.ErrInvalidSignerCount
+	}
+	if !isMultiAccountKeyHolder(multiAcc, tx.Approver) {
+		return ErrNotMultiAccountKeyHolder
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *ApproveMultiTransaction) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		mp, err := sp.MultiProposal(ctw, tx.ProposalID)
+		if err != nil {
+			return err
+		}
+		if err := sp.approveMultiProposal(ctw, tx.ProposalID, mp, tx.Approver, index); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *ApproveMultiTransaction) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"proposal_id":`)
+	if bs, err := tx.ProposalID.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"approver":`)
+	if bs, err := tx.Approver.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,182 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// MaxBatchTransferOutputs is the maximum number of outputs of the batch transfer
+const MaxBatchTransferOutputs = 500
+
+// TransferOutput is an output of the batch transfer
+type TransferOutput struct {
+	To     common.Address
+	Amount *amount.Amount
+}
+
+// MarshalJSON is a marshaler function
+func (out *TransferOutput) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"to":`)
+	if bs, err := out.To.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := out.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// BatchTransfer transfers to multiple outputs by a single signature and sequence
+// All outputs are transferred or nothing is transferred
+type BatchTransfer struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	Outputs    []*TransferOutput
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *BatchTransfer) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *BatchTransfer) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *BatchTransfer) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *BatchTransfer) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10).MulC(int64(len(tx.Outputs)))
+}
+
+// TotalAmount returns the sum of amounts of outputs
+func (tx *BatchTransfer) TotalAmount() *amount.Amount {
+	sum := amount.NewCoinAmount(0, 0)
+	for _, out := range tx.Outputs {
+		sum = sum.Add(out.Amount)
+	}
+	return sum
+}
+
+// Validate validates signatures of the transaction
+func (tx *BatchTransfer) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if len(tx.Outputs) == 0 || len(tx.Outputs) > MaxBatchTransferOutputs {
+		return ErrInvalidOutputCount
+	}
+	for _, out := range tx.Outputs {
+		if out.Amount == nil || out.Amount.Less(amount.COIN.DivC(10)) {
+			return types.ErrDustAmount
+		}
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	for _, out := range tx.Outputs {
+		if has, err := loader.HasAccount(out.To); err != nil {
+			return err
+		} else if !has {
+			return types.ErrNotExistAccount
+		}
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayableWith(loader, tx, tx.TotalAmount()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *BatchTransfer) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if err := sp.SubBalance(ctw, tx.From(), tx.TotalAmount()); err != nil {
+			return err
+		}
+		for _, out := range tx.Outputs {
+			if err := sp.AddBalance(ctw, out.To, out.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *BatchTransfer) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"outputs":`)
+	buffer.WriteString(`[`)
+	for i, out := range tx.Outputs {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := out.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"total_amount":`)
+	if bs, err := tx.TotalAmount().MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+func TestBatchTransfer(t *testing.T) {
+	type output struct {
+		to     int
+		amount string
+	}
+	repeat := func(out output, n int) []output {
+		list := make([]output, 0, n)
+		for i := 0; i < n; i++ {
+			list = append(list, out)
+		}
+		return list
+	}
+	tests := []struct {
+		name     string
+		outputs  []output
+		err      error
+		balances []string
+	}{
+		{"single output", []output{{1, "10"}}, nil, []string{"89.9", "110", "100"}},
+		{"fee per output", []output{{1, "10"}, {2, "20"}}, nil, []string{"69.8", "110", "120"}},
+		{"same receiver twice", []output{{1, "10"}, {1, "5"}}, nil, []string{"84.8", "115", "100"}},
+		{"output to the sender", []output{{0, "10"}}, nil, []string{"99.9", "100", "100"}},
+		{"whole balance with the fee", []output{{1, "49.9"}, {2, "49.9"}}, nil, []string{"0", "149.9", "149.9"}},
+		{"most outputs", repeat(output{1, "0.1"}, MaxBatchTransferOutputs), nil, []string{"0", "150", "100"}},
+		{"over the balance with the fee", []output{{1, "50"}, {2, "49.9"}}, ErrInsufficientFee, []string{"100", "100", "100"}},
+		{"no outputs", nil, ErrInvalidOutputCount, []string{"100", "100", "100"}},
+		{"too many outputs", repeat(output{1, "0.1"}, MaxBatchTransferOutputs+1), ErrInvalidOutputCount, []string{"100", "100", "100"}},
+		{"dust output", []output{{1, "10"}, {2, "0.01"}}, types.ErrDustAmount, []string{"100", "100", "100"}},
+		{"missing amount", []output{{1, "10"}, {2, ""}}, types.ErrDustAmount, []string{"100", "100", "100"}},
+		{"unknown receiver", []output{{1, "10"}, {3, "10"}}, types.ErrNotExistAccount, []string{"100", "100", "100"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newTxFixture(t, "alice", "bob", "carol")
+			addrs := append(fx.Addrs, common.NewAddress(0, 99, 0))
+
+			tx := &BatchTransfer{
+				Seq_:  fx.seq(fx.Addrs[0]),
+				From_: fx.Addrs[0],
+			}
+			for _, out := range tt.outputs {
+				to := &TransferOutput{To: addrs[out.to]}
+				if len(out.amount) > 0 {
+					to.Amount = amount.MustParseAmount(out.amount)
+				}
+				tx.Outputs = append(tx.Outputs, to)
+			}
+			if err := fx.execute(tx, fx.Keys[0]); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			for i, v := range tt.balances {
+				checkBalance(t, fx.p, fx.ctx, fx.Addrs[i], amount.MustParseAmount(v))
+			}
+		})
+	}
+}
@@ -67,6 +67,9 @@ func (tx *Burn) Execute(p types.Process, ctw *types.ContextWrapper, index uint16
 		if err := sp.SubBalance(ctw, tx.From(), tx.Amount); err != nil {
 			return err
 		}
+		if err := sp.AddBurnedAmount(ctw, tx.Amount); err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -0,0 +1,117 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// CancelMultiTransaction cancels the pending proposal of the multi account by the proposer
+// The fee is paid by the account of the proposer like the proposal
+type CancelMultiTransaction struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	ProposalID hash.Hash256
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *CancelMultiTransaction) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *CancelMultiTransaction) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *CancelMultiTransaction) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *CancelMultiTransaction) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *CancelMultiTransaction) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	mp, err := sp.MultiProposal(loader, tx.ProposalID)
+	if err != nil {
+		return err
+	}
+	if len(signers) != 1 || signers[0] != mp.Proposer {
+		return types.ErrInvalidAccountSigner
+	}
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *CancelMultiTransaction) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if _, err := sp.MultiProposal(ctw, tx.ProposalID); err != nil {
+			return err
+		}
+		sp.removeMultiProposal(ctw, tx.ProposalID)
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *CancelMultiTransaction) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"proposal_id":`)
+	if bs, err := tx.ProposalID.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// CancelRecovery cancels the pending recovery request of the single account that is signed by the current key
+type CancelRecovery struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *CancelRecovery) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *CancelRecovery) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *CancelRecovery) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *CancelRecovery) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *CancelRecovery) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+	if _, err := sp.RecoveryRequest(loader, tx.From()); err != nil {
+		return err
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	singleAcc, is := acc.(*SingleAccount)
+	if !is {
+		return ErrNotSingleAccount
+	}
+	if err := singleAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *CancelRecovery) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if err := sp.setRecoveryRequest(ctw, tx.From(), nil); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *CancelRecovery) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+func TestLockedTransfer_UnlockHeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int
+		amount string
+		err    error
+	}{
+		{"unlock at the next height", 1, "10", nil},
+		{"unlock at the target height", 0, "10", ErrInvalidUnlockHeight},
+		{"unlock before the target height", -1, "10", ErrInvalidUnlockHeight},
+		{"dust amount", 5, "0.01", types.ErrDustAmount},
+		{"whole balance with the fee", 5, "99.9", nil},
+		{"over the balance with the fee", 5, "100", ErrInsufficientFee},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newTxFixture(t, "alice", "bob")
+			alice, bob := fx.Addrs[0], fx.Addrs[1]
+			fx.advance(t, 10)
+
+			am := amount.MustParseAmount(tt.amount)
+			UnlockHeight := uint32(10 + tt.offset)
+			if err := fx.execute(&LockedTransfer{
+				Seq_:         fx.seq(alice),
+				From_:        alice,
+				To:           bob,
+				Amount:       am,
+				UnlockHeight: UnlockHeight,
+			}, fx.Keys[0]); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			if tt.err != nil {
+				checkBalance(t, fx.p, fx.ctx, alice, amount.NewCoinAmount(100, 0))
+				if lbs := fx.p.LockedBalances(fx.ctx, bob); len(lbs) != 0 {
+					t.Fatalf("locked balances are made %v", lbs)
+				}
+				return
+			}
+			checkBalance(t, fx.p, fx.ctx, alice, amount.NewCoinAmount(100, 0).Sub(am).Sub(amount.COIN.DivC(10)))
+
+			// the locked balance is released after the block of the unlock height
+			fx.advance(t, UnlockHeight)
+			if lbs := fx.p.LockedBalances(fx.ctx, bob); len(lbs) != 1 || lbs[0].UnlockHeight != UnlockHeight || !lbs[0].Amount.Equal(am) {
+				t.Fatalf("invalid locked balances %v", lbs)
+			}
+			checkBalance(t, fx.p, fx.ctx, bob, amount.NewCoinAmount(100, 0))
+			fx.advance(t, UnlockHeight+1)
+			if lbs := fx.p.LockedBalances(fx.ctx, bob); len(lbs) != 0 {
+				t.Fatalf("locked balances are not released %v", lbs)
+			}
+			if sum := fx.p.TotalLockedBalanceByAddress(fx.ctx, bob); !sum.IsZero() {
+				t.Fatalf("locked balance sum is remained %v", sum)
+			}
+			checkBalance(t, fx.p, fx.ctx, bob, amount.NewCoinAmount(100, 0).Add(am))
+		})
+	}
+}
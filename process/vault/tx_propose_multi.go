@@ -0,0 +1,185 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+)
+
+// ProposeMultiTransaction proposes the transaction of the multi account that is executed when key holders approve it
+// It is signed by the proposer only and the proposer is counted as the first approval
+// The fee is paid by the account of the proposer, so that a key holder cannot spend the multi account by proposals
+// The sequence of the proposed transaction is checked when it is executed
+type ProposeMultiTransaction struct {
+	Timestamp_   uint64
+	Seq_         uint64
+	From_        common.Address
+	Address      common.Address
+	Proposer     common.PublicHash
+	TxType       uint16
+	Payload      []byte
+	ExpireHeight uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *ProposeMultiTransaction) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *ProposeMultiTransaction) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *ProposeMultiTransaction) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *ProposeMultiTransaction) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// ProposalID returns the id of the proposal that is made by the transaction
+func (tx *ProposeMultiTransaction) ProposalID() hash.Hash256 {
+	return encoding.Hash(tx)
+}
+
+// Validate validates signatures of the transaction
+func (tx *ProposeMultiTransaction) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.ExpireHeight <= loader.TargetHeight() || tx.ExpireHeight > loader.TargetHeight()+MaxMultiProposalPeriod {
+		return ErrInvalidExpireHeight
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+	if _, err := decodeProposedTransaction(tx.Address, tx.TxType, tx.Payload); err != nil {
+		return err
+	}
+	if sp.IsUsedMultiProposalID(loader, tx.ProposalID()) {
+		return ErrExistMultiProposal
+	}
+
+	acc, err := loader.Account(tx.Address)
+	if err != nil {
+		return err
+	}
+	multiAcc, is := acc.(*MultiAccount)
+	if !is {
+		return ErrNotMultiAccount
+	}
+	if len(signers) != 1 || signers[0] != tx.Proposer {
+		return types.ErrInvalidSignerCount
+	}
+	if !isMultiAccountKeyHolder(multiAcc, tx.Proposer) {
+		return ErrNotMultiAccountKeyHolder
+	}
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *ProposeMultiTransaction) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		ID := tx.ProposalID()
+		mp := &MultiProposal{
+			Address:      tx.Address,
+			Proposer:     tx.Proposer,
+			TxType:       tx.TxType,
+			Payload:      tx.Payload,
+			ExpireHeight: tx.ExpireHeight,
+			Approvals:    []common.PublicHash{},
+		}
+		if err := sp.addMultiProposal(ctw, ID, mp); err != nil {
+			return err
+		}
+		if err := sp.approveMultiProposal(ctw, ID, mp, tx.Proposer, index); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *ProposeMultiTransaction) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"address":`)
+	if bs, err := tx.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"proposer":`)
+	if bs, err := tx.Proposer.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tx_type":`)
+	if bs, err := json.Marshal(tx.TxType); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"payload":`)
+	if bs, err := json.Marshal(hex.EncodeToString(tx.Payload)); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"expire_height":`)
+	if bs, err := json.Marshal(tx.ExpireHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,133 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// MaxAccountNames is the maximum number of names that are registered to an account by the name service
+const MaxAccountNames = 10
+
+// RegisterName registers the name to the existing account as an additional name
+// The name can be transferred to another account or released by the owner
+type RegisterName struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	Name       string
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *RegisterName) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *RegisterName) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *RegisterName) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *RegisterName) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *RegisterName) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if !types.IsAllowedAccountName(tx.Name) {
+		return types.ErrInvalidAccountName
+	}
+	if _, err := common.ParseAddress(tx.Name); err == nil {
+		return types.ErrNotAllowedAddressAccountName
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+	if has, err := loader.HasAccountName(tx.Name); err != nil {
+		if err != types.ErrNotExistAccount {
+			return err
+		}
+	} else if has {
+		return types.ErrExistAccountName
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := acc.Validate(loader, signers); err != nil {
+		return err
+	}
+	if names, err := sp.AccountNames(loader, tx.From()); err != nil {
+		return err
+	} else if len(names) >= MaxAccountNames {
+		return ErrTooManyAccountNames
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *RegisterName) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if has, err := ctw.HasAccountName(tx.Name); err != nil {
+			if err != types.ErrNotExistAccount {
+				return err
+			}
+		} else if has {
+			return types.ErrExistAccountName
+		}
+		return sp.registerAccountName(ctw, tx.From(), tx.Name)
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *RegisterName) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(tx.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// RequestRecovery requests the key rotation of the single account that is signed by the recovery key
+// The key can be rotated by the recovery key after the delay of the recovery key
+type RequestRecovery struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	KeyHash    common.PublicHash
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *RequestRecovery) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *RequestRecovery) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *RequestRecovery) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *RequestRecovery) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *RequestRecovery) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	singleAcc, is := acc.(*SingleAccount)
+	if !is {
+		return ErrNotSingleAccount
+	}
+	if singleAcc.KeyHash == tx.KeyHash {
+		return ErrSameKeyHash
+	}
+	rk, err := sp.RecoveryKey(loader, tx.From())
+	if err != nil {
+		return err
+	}
+	if len(signers) != 1 {
+		return types.ErrInvalidSignerCount
+	}
+	if signers[0] != rk.KeyHash {
+		return types.ErrInvalidAccountSigner
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *RequestRecovery) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		rk, err := sp.RecoveryKey(ctw, tx.From())
+		if err != nil {
+			return err
+		}
+		if err := sp.setRecoveryRequest(ctw, tx.From(), &RecoveryRequest{
+			KeyHash:          tx.KeyHash,
+			ActivationHeight: ctw.TargetHeight() + rk.Delay,
+		}); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *RequestRecovery) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := tx.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,151 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// RotateKey updates the key hash of the single account without changing the address and the name
+// It is signed by the current key or by the recovery key when the recovery request of the same key hash is activated
+// ByRecovery should be true only when it is signed by the recovery key, it is checked by signers in the validation
+type RotateKey struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	KeyHash    common.PublicHash
+	ByRecovery bool
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *RotateKey) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *RotateKey) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *RotateKey) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *RotateKey) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *RotateKey) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	singleAcc, is := acc.(*SingleAccount)
+	if !is {
+		return ErrNotSingleAccount
+	}
+	if singleAcc.KeyHash == tx.KeyHash {
+		return ErrSameKeyHash
+	}
+	if ByRecovery, err := tx.isRecovery(sp, loader, singleAcc, signers); err != nil {
+		return err
+	} else if ByRecovery != tx.ByRecovery {
+		return types.ErrInvalidAccountSigner
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isRecovery returns the transaction is signed by the recovery key or not
+func (tx *RotateKey) isRecovery(sp *Vault, loader types.LoaderWrapper, acc *SingleAccount, signers []common.PublicHash) (bool, error) {
+	if err := acc.Validate(loader, signers); err == nil {
+		return false, nil
+	}
+	rk, err := sp.RecoveryKey(loader, tx.From())
+	if err != nil {
+		return false, types.ErrInvalidAccountSigner
+	}
+	if len(signers) != 1 || signers[0] != rk.KeyHash {
+		return false, types.ErrInvalidAccountSigner
+	}
+	rr, err := sp.RecoveryRequest(loader, tx.From())
+	if err != nil {
+		return false, err
+	}
+	if rr.KeyHash != tx.KeyHash {
+		return false, ErrNotExistRecoveryRequest
+	}
+	if rr.ActivationHeight > loader.TargetHeight() {
+		return false, ErrNotActivatedRecoveryRequest
+	}
+	return true, nil
+}
+
+// Execute updates the context by the transaction
+func (tx *RotateKey) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if err := sp.rotateKey(ctw, tx.From(), tx.KeyHash, tx.ByRecovery, index); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *RotateKey) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := tx.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"by_recovery":`)
+	if bs, err := json.Marshal(tx.ByRecovery); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
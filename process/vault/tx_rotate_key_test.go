@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+func TestRotateKey(t *testing.T) {
+	Current := common.PublicHash{1}
+	Other := common.PublicHash{9}
+	Recovery := common.PublicHash{50}
+	Requested := common.PublicHash{60}
+	tests := []struct {
+		name       string
+		signer     common.PublicHash
+		keyHash    common.PublicHash
+		request    bool
+		height     uint32
+		err        error
+		byRecovery bool
+	}{
+		{"rotated by the current key", Current, common.PublicHash{2}, false, 10, nil, false},
+		{"rotated by the current key with the request", Current, common.PublicHash{2}, true, 10, nil, false},
+		{"rotated by the current key to the activated request", Current, Requested, true, 15, nil, false},
+		{"rotated to the same key", Current, Current, false, 10, ErrSameKeyHash, false},
+		{"signed by other key", Other, common.PublicHash{2}, true, 15, types.ErrInvalidAccountSigner, false},
+		{"recovered at the activation height", Recovery, Requested, true, 15, nil, true},
+		{"recovered after the activation height", Recovery, Requested, true, 16, nil, true},
+		{"recovered before the activation height", Recovery, Requested, true, 14, ErrNotActivatedRecoveryRequest, false},
+		{"recovered to other key", Recovery, common.PublicHash{61}, true, 15, ErrNotExistRecoveryRequest, false},
+		{"recovered without the request", Recovery, Requested, false, 15, ErrNotExistRecoveryRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newTxFixture(t, "alice")
+			alice := fx.Addrs[0]
+			ctw := types.NewContextWrapper(fx.p.ID(), fx.ctx)
+			if err := fx.p.setRecoveryKey(ctw, alice, &RecoveryKey{
+				KeyHash: Recovery,
+				Delay:   MinRecoveryDelay,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.request {
+				if err := fx.p.setRecoveryRequest(ctw, alice, &RecoveryRequest{
+					KeyHash:          Requested,
+					ActivationHeight: 15,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fx.advance(t, tt.height)
+
+			// the flag of the other signer is not allowed
+			if err := fx.execute(&RotateKey{
+				Seq_:       fx.seq(alice),
+				From_:      alice,
+				KeyHash:    tt.keyHash,
+				ByRecovery: !tt.byRecovery,
+			}, tt.signer); err == nil {
+				t.Fatal("rotated by the invalid recovery flag")
+			}
+			if err := fx.execute(&RotateKey{
+				Seq_:       fx.seq(alice),
+				From_:      alice,
+				KeyHash:    tt.keyHash,
+				ByRecovery: tt.byRecovery,
+			}, tt.signer); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+
+			acc, err := fx.ctx.Account(alice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, ReqErr := fx.p.RecoveryRequest(fx.ctx, alice)
+			if tt.err != nil {
+				if KeyHash := acc.(*SingleAccount).KeyHash; KeyHash != Current {
+					t.Fatalf("key is rotated to %v", KeyHash)
+				}
+				if tt.request && ReqErr != nil {
+					t.Fatalf("recovery request is removed %v", ReqErr)
+				}
+				return
+			}
+			if KeyHash := acc.(*SingleAccount).KeyHash; KeyHash != tt.keyHash {
+				t.Fatalf("invalid key hash %v, expected %v", KeyHash, tt.keyHash)
+			}
+			if ReqErr != ErrNotExistRecoveryRequest {
+				t.Fatalf("recovery request is remained %v", ReqErr)
+			}
+			Events := fx.ctx.Top().Events
+			if len(Events) != 1 {
+				t.Fatalf("invalid event count %v", len(Events))
+			}
+			ev := Events[0].(*KeyRotatedEvent)
+			if ev.OldKeyHash != Current || ev.NewKeyHash != tt.keyHash || ev.ByRecovery != tt.byRecovery {
+				t.Fatalf("invalid event %v -> %v by recovery %v", ev.OldKeyHash, ev.NewKeyHash, ev.ByRecovery)
+			}
+
+			// the old key cannot sign transactions after the rotation
+			if err := fx.execute(&RotateKey{
+				Seq_:    fx.seq(alice),
+				From_:   alice,
+				KeyHash: common.PublicHash{3},
+			}, Current); err != types.ErrInvalidAccountSigner {
+				t.Fatalf("expected %v but %v", types.ErrInvalidAccountSigner, err)
+			}
+			if err := fx.execute(&RotateKey{
+				Seq_:    fx.seq(alice),
+				From_:   alice,
+				KeyHash: common.PublicHash{3},
+			}, tt.keyHash); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
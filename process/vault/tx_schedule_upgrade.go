@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/util"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+)
+
+// ScheduleUpgrade is used to apply the upgrade of the vault to the running chain from the activation height
+// The activation height should be admin.MinActivationDelay blocks later at least, so every node can be updated before it
+type ScheduleUpgrade struct {
+	Timestamp_       uint64
+	Seq_             uint64
+	From_            common.Address
+	Kind             uint8
+	ActivationHeight uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *ScheduleUpgrade) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *ScheduleUpgrade) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *ScheduleUpgrade) From() common.Address {
+	return tx.From_
+}
+
+// Validate validates signatures of the transaction
+func (tx *ScheduleUpgrade) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.From() != sp.admin.AdminAddress(loader, p.Name()) {
+		return admin.ErrUnauthorizedTransaction
+	}
+	if !isValidUpgradeKind(tx.Kind) {
+		return ErrInvalidUpgradeKind
+	}
+	if _, has := sp.UpgradeHeight(loader, tx.Kind); has {
+		return ErrScheduledUpgrade
+	}
+	if tx.ActivationHeight < loader.TargetHeight()+admin.MinActivationDelay {
+		return admin.ErrInvalidActivationHeight
+	}
+
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *ScheduleUpgrade) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	if _, has := sp.UpgradeHeight(ctw, tx.Kind); has {
+		return ErrScheduledUpgrade
+	}
+	ctw.SetProcessData(toUpgradeHeightKey(tx.Kind), util.Uint32ToBytes(tx.ActivationHeight))
+	return nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *ScheduleUpgrade) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"kind":`)
+	if bs, err := json.Marshal(tx.Kind); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"activation_height":`)
+	if bs, err := json.Marshal(tx.ActivationHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,140 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// SetRecoveryKey sets the recovery key of the single account that is signed by the current key
+// The zero key hash removes the recovery key and the pending recovery request is always removed
+type SetRecoveryKey struct {
+	Timestamp_ uint64
+	Seq_       uint64
+	From_      common.Address
+	KeyHash    common.PublicHash
+	Delay      uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *SetRecoveryKey) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *SetRecoveryKey) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *SetRecoveryKey) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *SetRecoveryKey) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Validate validates signatures of the transaction
+func (tx *SetRecoveryKey) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.KeyHash != (common.PublicHash{}) {
+		if tx.Delay < MinRecoveryDelay || tx.Delay > MaxRecoveryDelay {
+			return ErrInvalidRecoveryDelay
+		}
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	acc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	singleAcc, is := acc.(*SingleAccount)
+	if !is {
+		return ErrNotSingleAccount
+	}
+	if singleAcc.KeyHash == tx.KeyHash {
+		return ErrSameKeyHash
+	}
+	if err := singleAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayable(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *SetRecoveryKey) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	return sp.WithFee(ctw, tx, func() error {
+		if tx.KeyHash == (common.PublicHash{}) {
+			if err := sp.setRecoveryKey(ctw, tx.From(), nil); err != nil {
+				return err
+			}
+		} else {
+			if err := sp.setRecoveryKey(ctw, tx.From(), &RecoveryKey{
+				KeyHash: tx.KeyHash,
+				Delay:   tx.Delay,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := sp.setRecoveryRequest(ctw, tx.From(), nil); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *SetRecoveryKey) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := tx.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"delay":`)
+	if bs, err := json.Marshal(tx.Delay); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,218 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// MaxVestingTranches is the maximum number of locked balances that are made by a vesting
+const MaxVestingTranches = 1000
+
+// Vesting transfers the amount that is released linearly from the start height to the end height
+// Nothing is released before the cliff height and the amount vested until the cliff height is released at once
+// The vested amount is released at every interval blocks from the start height
+type Vesting struct {
+	Timestamp_  uint64
+	Seq_        uint64
+	From_       common.Address
+	To          common.Address
+	Amount      *amount.Amount
+	StartHeight uint32
+	CliffHeight uint32
+	EndHeight   uint32
+	Interval    uint32
+}
+
+// Timestamp returns the timestamp of the transaction
+func (tx *Vesting) Timestamp() uint64 {
+	return tx.Timestamp_
+}
+
+// Seq returns the sequence of the transaction
+func (tx *Vesting) Seq() uint64 {
+	return tx.Seq_
+}
+
+// From returns the from address of the transaction
+func (tx *Vesting) From() common.Address {
+	return tx.From_
+}
+
+// Fee returns the fee of the transaction
+func (tx *Vesting) Fee(loader types.LoaderWrapper) *amount.Amount {
+	return amount.COIN.DivC(10)
+}
+
+// Tranches returns locked balances that are made by the vesting schedule
+func (tx *Vesting) Tranches() ([]*LockedBalance, error) {
+	if tx.Interval == 0 || tx.StartHeight >= tx.EndHeight {
+		return nil, ErrInvalidVestingSchedule
+	}
+	if tx.CliffHeight < tx.StartHeight || tx.CliffHeight > tx.EndHeight {
+		return nil, ErrInvalidVestingSchedule
+	}
+	Duration := tx.EndHeight - tx.StartHeight
+	if Duration/tx.Interval >= MaxVestingTranches {
+		return nil, ErrTooManyVestingTranches
+	}
+
+	heights := []uint32{}
+	if tx.CliffHeight > tx.StartHeight && tx.CliffHeight < tx.EndHeight {
+		heights = append(heights, tx.CliffHeight)
+	}
+	for h := uint64(tx.StartHeight) + uint64(tx.Interval); h < uint64(tx.EndHeight); h += uint64(tx.Interval) {
+		if uint32(h) > tx.CliffHeight {
+			heights = append(heights, uint32(h))
+		}
+	}
+	heights = append(heights, tx.EndHeight)
+
+	list := make([]*LockedBalance, 0, len(heights))
+	Released := amount.NewCoinAmount(0, 0)
+	for _, h := range heights {
+		Vested := tx.Amount.MulC(int64(h - tx.StartHeight)).DivC(int64(Duration))
+		if am := Vested.Sub(Released); !am.IsZero() {
+			list = append(list, &LockedBalance{
+				UnlockHeight: h,
+				Amount:       am,
+			})
+			Released = Vested
+		}
+	}
+	return list, nil
+}
+
+// Validate validates signatures of the transaction
+func (tx *Vesting) Validate(p types.Process, loader types.LoaderWrapper, signers []common.PublicHash) error {
+	sp := p.(*Vault)
+
+	if tx.Amount.Less(amount.COIN.DivC(10)) {
+		return types.ErrDustAmount
+	}
+	if tx.StartHeight < loader.TargetHeight() {
+		return ErrInvalidVestingSchedule
+	}
+	if _, err := tx.Tranches(); err != nil {
+		return err
+	}
+	if tx.Seq() <= loader.Seq(tx.From()) {
+		return types.ErrInvalidSequence
+	}
+
+	if has, err := loader.HasAccount(tx.To); err != nil {
+		return err
+	} else if !has {
+		return types.ErrNotExistAccount
+	}
+
+	fromAcc, err := loader.Account(tx.From())
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.Validate(loader, signers); err != nil {
+		return err
+	}
+
+	if err := sp.CheckFeePayableWith(loader, tx, tx.Amount); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute updates the context by the transaction
+func (tx *Vesting) Execute(p types.Process, ctw *types.ContextWrapper, index uint16) error {
+	sp := p.(*Vault)
+
+	if tx.StartHeight < ctw.TargetHeight() {
+		return ErrInvalidVestingSchedule
+	}
+	Tranches, err := tx.Tranches()
+	if err != nil {
+		return err
+	}
+	return sp.WithFee(ctw, tx, func() error {
+		if err := sp.SubBalance(ctw, tx.From(), tx.Amount); err != nil {
+			return err
+		}
+		for _, lb := range Tranches {
+			if err := sp.AddLockedBalance(ctw, tx.To, lb.UnlockHeight, lb.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Vesting) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to":`)
+	if bs, err := tx.To.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"start_height":`)
+	if bs, err := json.Marshal(tx.StartHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"cliff_height":`)
+	if bs, err := json.Marshal(tx.CliffHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"end_height":`)
+	if bs, err := json.Marshal(tx.EndHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"interval":`)
+	if bs, err := json.Marshal(tx.Interval); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,150 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common/amount"
+)
+
+func TestVesting_Tranches(t *testing.T) {
+	type tranche struct {
+		height uint32
+		amount string
+	}
+	tests := []struct {
+		name     string
+		amount   string
+		start    uint32
+		cliff    uint32
+		end      uint32
+		interval uint32
+		tranches []tranche
+		err      error
+	}{
+		{"cliff at the start", "100", 10, 10, 20, 5, []tranche{{15, "50"}, {20, "50"}}, nil},
+		{"cliff at the end", "100", 10, 20, 20, 5, []tranche{{20, "100"}}, nil},
+		{"cliff between intervals", "100", 10, 13, 20, 5, []tranche{{13, "30"}, {15, "20"}, {20, "50"}}, nil},
+		{"cliff on the interval", "100", 10, 15, 20, 5, []tranche{{15, "50"}, {20, "50"}}, nil},
+		{"interval longer than the duration", "100", 10, 10, 20, 30, []tranche{{20, "100"}}, nil},
+		{"remainder at the end", "100", 10, 10, 17, 3, []tranche{
+			{13, "42.857142857142857142"},
+			{16, "42.857142857142857143"},
+			{17, "14.285714285714285715"},
+		}, nil},
+		{"rounded to zero tranches", "0.000000000000000002", 0, 0, 4, 1, []tranche{
+			{2, "0.000000000000000001"},
+			{4, "0.000000000000000001"},
+		}, nil},
+		{"zero interval", "100", 10, 10, 20, 0, nil, ErrInvalidVestingSchedule},
+		{"start at the end", "100", 20, 20, 20, 5, nil, ErrInvalidVestingSchedule},
+		{"start after the end", "100", 21, 21, 20, 5, nil, ErrInvalidVestingSchedule},
+		{"cliff before the start", "100", 10, 9, 20, 5, nil, ErrInvalidVestingSchedule},
+		{"cliff after the end", "100", 10, 21, 20, 5, nil, ErrInvalidVestingSchedule},
+		{"most tranches", "100", 0, 0, 999, 1, nil, nil},
+		{"too many tranches", "100", 0, 0, 1000, 1, nil, ErrTooManyVestingTranches},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &Vesting{
+				Amount:      amount.MustParseAmount(tt.amount),
+				StartHeight: tt.start,
+				CliffHeight: tt.cliff,
+				EndHeight:   tt.end,
+				Interval:    tt.interval,
+			}
+			list, err := tx.Tranches()
+			if err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			sum := amount.NewCoinAmount(0, 0)
+			for i, lb := range list {
+				if i > 0 && lb.UnlockHeight <= list[i-1].UnlockHeight {
+					t.Fatalf("unlock heights are not ascending %v", list)
+				}
+				sum = sum.Add(lb.Amount)
+			}
+			if !sum.Equal(tx.Amount) {
+				t.Fatalf("sum of tranches %v, expected %v", sum, tx.Amount)
+			}
+			if list[len(list)-1].UnlockHeight != tt.end {
+				t.Fatalf("last tranche is unlocked at %v, expected %v", list[len(list)-1].UnlockHeight, tt.end)
+			}
+			if tt.tranches == nil {
+				return
+			}
+			if len(list) != len(tt.tranches) {
+				t.Fatalf("invalid tranche count %v, expected %v", len(list), len(tt.tranches))
+			}
+			for i, v := range tt.tranches {
+				if list[i].UnlockHeight != v.height || !list[i].Amount.Equal(amount.MustParseAmount(v.amount)) {
+					t.Fatalf("invalid tranche %v: %v at %v, expected %v at %v", i, list[i].Amount, list[i].UnlockHeight, v.amount, v.height)
+				}
+			}
+		})
+	}
+}
+
+func TestVesting_Release(t *testing.T) {
+	tests := []struct {
+		name  string
+		start uint32
+		cliff uint32
+		end   uint32
+		err   error
+	}{
+		{"start at the target height", 10, 10, 14, nil},
+		{"cliff at the end", 10, 14, 14, nil},
+		{"cliff between intervals", 11, 12, 15, nil},
+		{"start before the target height", 9, 10, 14, ErrInvalidVestingSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newTxFixture(t, "alice", "bob")
+			alice, bob := fx.Addrs[0], fx.Addrs[1]
+			fx.advance(t, 10)
+
+			tx := &Vesting{
+				Seq_:        fx.seq(alice),
+				From_:       alice,
+				To:          bob,
+				Amount:      amount.NewCoinAmount(40, 0),
+				StartHeight: tt.start,
+				CliffHeight: tt.cliff,
+				EndHeight:   tt.end,
+				Interval:    1,
+			}
+			if err := fx.execute(tx, fx.Keys[0]); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			if tt.err != nil {
+				checkBalance(t, fx.p, fx.ctx, alice, amount.NewCoinAmount(100, 0))
+				return
+			}
+			checkBalance(t, fx.p, fx.ctx, alice, amount.NewCoinAmount(60, 0).Sub(amount.COIN.DivC(10)))
+
+			list, err := tx.Tranches()
+			if err != nil {
+				t.Fatal(err)
+			}
+			Released := amount.NewCoinAmount(100, 0)
+			for _, lb := range list {
+				// each tranche is released after the block of its unlock height
+				fx.advance(t, lb.UnlockHeight)
+				checkBalance(t, fx.p, fx.ctx, bob, Released)
+				if !fx.p.LockedBalance(fx.ctx, bob, lb.UnlockHeight).Equal(lb.Amount) {
+					t.Fatalf("invalid locked balance at %v", lb.UnlockHeight)
+				}
+				fx.advance(t, lb.UnlockHeight+1)
+				Released = Released.Add(lb.Amount)
+				checkBalance(t, fx.p, fx.ctx, bob, Released)
+			}
+			checkBalance(t, fx.p, fx.ctx, bob, amount.NewCoinAmount(140, 0))
+			if lbs := fx.p.LockedBalances(fx.ctx, bob); len(lbs) != 0 {
+				t.Fatalf("locked balances are remained %v", lbs)
+			}
+		})
+	}
+}
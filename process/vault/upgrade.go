@@ -0,0 +1,59 @@
+package vault
+
+import (
+	"github.com/fletaio/fleta_testnet/common/util"
+	"github.com/fletaio/fleta_testnet/core/types"
+)
+
+// upgrade kinds
+// An upgrade changes the result of existing operations, so a running chain applies it from the height that is scheduled by the admin
+const (
+	// NameReleaseUpgrade releases the name of the account that is given at the creation when the account is deleted
+	NameReleaseUpgrade = uint8(1)
+	// SupplyUpgrade maintains totals of the supply, they are seeded from the full account scan at the activation height
+	SupplyUpgrade = uint8(2)
+)
+
+func isValidUpgradeKind(Kind uint8) bool {
+	switch Kind {
+	case NameReleaseUpgrade, SupplyUpgrade:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpgradeHeight returns the height from which the upgrade is applied
+func (p *Vault) UpgradeHeight(loader types.Loader, Kind uint8) (uint32, bool) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toUpgradeHeightKey(Kind)); len(bs) > 0 {
+		return util.BytesToUint32(bs), true
+	} else {
+		return 0, false
+	}
+}
+
+// IsUpgraded returns the upgrade is applied at the target height or not
+func (p *Vault) IsUpgraded(loader types.Loader, Kind uint8) bool {
+	Height, has := p.UpgradeHeight(loader, Kind)
+	return has && loader.TargetHeight() >= Height
+}
+
+// InitUpgrades applies every upgrade from the genesis
+// It is called at OnInitGenesis of an application of a new chain before any coin is issued,
+// the running chain schedules upgrades by ScheduleUpgrade
+func (p *Vault) InitUpgrades(ctw *types.ContextWrapper) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	for _, Kind := range []uint8{NameReleaseUpgrade, SupplyUpgrade} {
+		ctw.SetProcessData(toUpgradeHeightKey(Kind), util.Uint32ToBytes(0))
+	}
+	return nil
+}
+
+// IsSeedHeight returns the target height is the activation height of the upgrade that is scheduled to the running chain
+func (p *Vault) IsSeedHeight(loader types.Loader, Kind uint8) bool {
+	Height, has := p.UpgradeHeight(loader, Kind)
+	return has && Height > 0 && loader.TargetHeight() == Height
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
 )
 
 // tags
@@ -14,8 +15,23 @@ var (
 	tagLockedBalanceReverse = []byte{2, 3}
 	tagLockedBalanceCount   = []byte{2, 4}
 	tagLockedBalanceSum     = []byte{2, 5}
+	tagLockedBalanceHeights = []byte{2, 6}
 	tagCollectedFee         = []byte{3, 1}
+	tagHeldBalance          = []byte{3, 2}
 	tagPolicy               = []byte{4, 0}
+	tagMultiProposal        = []byte{5, 0}
+	tagMultiProposalUsed    = []byte{5, 1}
+	tagMultiProposalExpire  = []byte{5, 2}
+	tagRecoveryKey          = []byte{6, 0}
+	tagRecoveryRequest      = []byte{6, 1}
+	tagAccountNames         = []byte{7, 0}
+	tagTotalBalance         = []byte{8, 0}
+	tagTotalLockedBalance   = []byte{8, 1}
+	tagTotalFormulator      = []byte{8, 2}
+	tagTotalStaking         = []byte{8, 3}
+	tagTotalUnstaking       = []byte{8, 4}
+	tagTotalBurned          = []byte{8, 5}
+	tagUpgradeHeight        = []byte{9, 0}
 )
 
 func toLockedBalanceKey(height uint32, addr common.Address) []byte {
@@ -48,3 +64,31 @@ func toLockedBalanceCountKey(height uint32) []byte {
 	binary.BigEndian.PutUint32(bs[2:], height)
 	return bs
 }
+
+func toMultiProposalKey(ID hash.Hash256) []byte {
+	bs := make([]byte, 2+hash.Hash256Size)
+	copy(bs, tagMultiProposal)
+	copy(bs[2:], ID[:])
+	return bs
+}
+
+func toMultiProposalUsedKey(ID hash.Hash256) []byte {
+	bs := make([]byte, 2+hash.Hash256Size)
+	copy(bs, tagMultiProposalUsed)
+	copy(bs[2:], ID[:])
+	return bs
+}
+
+func toMultiProposalExpireKey(height uint32) []byte {
+	bs := make([]byte, 6)
+	copy(bs, tagMultiProposalExpire)
+	binary.BigEndian.PutUint32(bs[2:], height)
+	return bs
+}
+
+func toUpgradeHeightKey(Kind uint8) []byte {
+	bs := make([]byte, 3)
+	copy(bs, tagUpgradeHeight)
+	bs[2] = Kind
+	return bs
+}
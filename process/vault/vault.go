@@ -2,6 +2,7 @@ package vault
 
 import (
 	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
 	"github.com/fletaio/fleta_testnet/core/types"
 	"github.com/fletaio/fleta_testnet/encoding"
 	"github.com/fletaio/fleta_testnet/process/admin"
@@ -52,6 +53,21 @@ func (p *Vault) Init(reg *types.Register, pm types.ProcessManager, cn types.Prov
 	reg.RegisterTransaction(5, &CreateMultiAccount{})
 	reg.RegisterTransaction(9, &IssueAccount{})
 	reg.RegisterTransaction(10, &UpdatePolicy{})
+	reg.RegisterTransaction(11, &LockedTransfer{})
+	reg.RegisterTransaction(12, &Vesting{})
+	reg.RegisterTransaction(13, &ProposeMultiTransaction{})
+	reg.RegisterTransaction(14, &ApproveMultiTransaction{})
+	reg.RegisterTransaction(15, &CancelMultiTransaction{})
+	reg.RegisterTransaction(16, &RotateKey{})
+	reg.RegisterTransaction(17, &SetRecoveryKey{})
+	reg.RegisterTransaction(18, &RequestRecovery{})
+	reg.RegisterTransaction(19, &CancelRecovery{})
+	reg.RegisterTransaction(20, &BatchTransfer{})
+	reg.RegisterTransaction(21, &RegisterName{})
+	reg.RegisterTransaction(22, &TransferName{})
+	reg.RegisterTransaction(23, &ReleaseName{})
+	reg.RegisterTransaction(24, &ScheduleUpgrade{})
+	reg.RegisterEvent(1, &KeyRotatedEvent{})
 
 	if vp, err := pm.ProcessByName("fleta.admin"); err != nil {
 		return err
@@ -84,6 +100,82 @@ func (p *Vault) Init(reg *types.Register, pm types.ProcessManager, cn types.Prov
 			ctw := cn.NewContextWrapper(p.ID())
 			return p.Balance(ctw, addr), nil
 		})
+		s.Set("lockedBalances", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			arg0, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			addr, err := common.ParseAddress(arg0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.LockedBalances(ctw, addr), nil
+		})
+		s.Set("multiProposal", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			arg0, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			ProposalID, err := hash.ParseHash(arg0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.MultiProposal(ctw, ProposalID)
+		})
+		s.Set("supply", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 0 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return p.Supply(ctw)
+		})
+		s.Set("addressByName", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			Name, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			return ctw.AddressByName(Name)
+		})
+		s.Set("namesByAddress", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+			if arg.Len() != 1 {
+				return nil, apiserver.ErrInvalidArgument
+			}
+			arg0, err := arg.String(0)
+			if err != nil {
+				return nil, err
+			}
+			addr, err := common.ParseAddress(arg0)
+			if err != nil {
+				return nil, err
+			}
+			ctw := cn.NewContextWrapper(p.ID())
+			acc, err := ctw.Account(addr)
+			if err != nil {
+				return nil, err
+			}
+			names, err := p.AccountNames(ctw, addr)
+			if err != nil {
+				return nil, err
+			}
+			if has, err := p.hasCreationName(ctw, addr, acc.Name()); err != nil {
+				return nil, err
+			} else if has {
+				names = append([]string{acc.Name()}, names...)
+			}
+			return names, nil
+		})
 	}
 	return nil
 }
@@ -111,6 +203,11 @@ func (p *Vault) OnLoadChain(loader types.LoaderWrapper) error {
 
 // BeforeExecuteTransactions called before processes transactions of the block
 func (p *Vault) BeforeExecuteTransactions(ctw *types.ContextWrapper) error {
+	if p.IsSeedHeight(ctw, SupplyUpgrade) {
+		if err := p.seedSupply(ctw); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -121,15 +218,23 @@ func (p *Vault) AfterExecuteTransactions(b *types.Block, ctw *types.ContextWrapp
 		return err
 	}
 
+	p.flushExpiredMultiProposals(ctw, b.Header.Height)
+
 	for addr, am := range LockedBalanceMap {
 		if has, err := ctw.HasAccount(addr); err != nil {
 			if err == types.ErrDeletedAccount {
 				ctw.SetAccountData(addr, tagLockedBalanceSum, nil)
+				if err := p.AddBurnedAmount(ctw, am); err != nil {
+					return err
+				}
 			} else {
 				return err
 			}
 		} else if !has {
 			ctw.SetAccountData(addr, tagLockedBalanceSum, nil)
+			if err := p.AddBurnedAmount(ctw, am); err != nil {
+				return err
+			}
 		} else {
 			if err := p.AddBalance(ctw, addr, am); err != nil {
 				return err
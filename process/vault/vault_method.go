@@ -1,10 +1,14 @@
 package vault
 
 import (
+	"sort"
+
 	"github.com/fletaio/fleta_testnet/common"
 	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/common/hash"
 	"github.com/fletaio/fleta_testnet/common/util"
 	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
 )
 
 // Balance returns balance of the account of the address
@@ -30,6 +34,7 @@ func (p *Vault) AddBalance(ctw *types.ContextWrapper, addr common.Address, am *a
 	}
 	//log.Println("AddBalance", ctw.TargetHeight(), addr.String(), am.String(), p.Balance(ctw, addr).Add(am).String())
 	ctw.SetAccountData(addr, tagBalance, p.Balance(ctw, addr).Add(am).Bytes())
+	p.addTotal(ctw, tagTotalBalance, am)
 	return nil
 }
 
@@ -45,9 +50,14 @@ func (p *Vault) SubBalance(ctw *types.ContextWrapper, addr common.Address, am *a
 
 	sum = sum.Sub(am)
 	if sum.IsZero() {
-		p.RemoveBalance(ctw, addr)
+		if err := p.RemoveBalance(ctw, addr); err != nil {
+			return err
+		}
 	} else {
 		ctw.SetAccountData(addr, tagBalance, sum.Bytes())
+		if err := p.subTotal(ctw, tagTotalBalance, am); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -56,6 +66,9 @@ func (p *Vault) SubBalance(ctw *types.ContextWrapper, addr common.Address, am *a
 func (p *Vault) RemoveBalance(ctw *types.ContextWrapper, addr common.Address) error {
 	ctw = types.SwitchContextWrapper(p.pid, ctw)
 
+	if err := p.subTotal(ctw, tagTotalBalance, p.Balance(ctw, addr)); err != nil {
+		return err
+	}
 	ctw.SetAccountData(addr, tagBalance, nil)
 	return nil
 }
@@ -102,9 +115,70 @@ func (p *Vault) AddLockedBalance(ctw *types.ContextWrapper, addr common.Address,
 	}
 	ctw.SetProcessData(toLockedBalanceKey(UnlockedHeight, addr), p.LockedBalance(ctw, addr, UnlockedHeight).Add(am).Bytes())
 	ctw.SetAccountData(addr, tagLockedBalanceSum, p.TotalLockedBalanceByAddress(ctw, addr).Add(am).Bytes())
+	p.addLockedBalanceHeight(ctw, addr, UnlockedHeight)
+	p.addTotal(ctw, tagTotalLockedBalance, am)
 	return nil
 }
 
+// LockedBalanceHeights returns unlock heights of pending locked balances of the account of the address in ascending order
+func (p *Vault) LockedBalanceHeights(loader types.Loader, addr common.Address) []uint32 {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	bs := lw.AccountData(addr, tagLockedBalanceHeights)
+	heights := make([]uint32, 0, len(bs)/4)
+	for i := 0; i+4 <= len(bs); i += 4 {
+		heights = append(heights, util.BytesToUint32(bs[i:i+4]))
+	}
+	return heights
+}
+
+// LockedBalances returns pending locked balances of the account of the address in ascending order of unlock heights
+func (p *Vault) LockedBalances(loader types.Loader, addr common.Address) []*LockedBalance {
+	heights := p.LockedBalanceHeights(loader, addr)
+	list := make([]*LockedBalance, 0, len(heights))
+	for _, h := range heights {
+		list = append(list, &LockedBalance{
+			UnlockHeight: h,
+			Amount:       p.LockedBalance(loader, addr, h),
+		})
+	}
+	return list
+}
+
+func (p *Vault) setLockedBalanceHeights(ctw *types.ContextWrapper, addr common.Address, heights []uint32) {
+	if len(heights) == 0 {
+		ctw.SetAccountData(addr, tagLockedBalanceHeights, nil)
+		return
+	}
+	bs := make([]byte, 0, len(heights)*4)
+	for _, h := range heights {
+		bs = append(bs, util.Uint32ToBytes(h)...)
+	}
+	ctw.SetAccountData(addr, tagLockedBalanceHeights, bs)
+}
+
+func (p *Vault) addLockedBalanceHeight(ctw *types.ContextWrapper, addr common.Address, UnlockedHeight uint32) {
+	heights := p.LockedBalanceHeights(ctw, addr)
+	idx := sort.Search(len(heights), func(i int) bool { return heights[i] >= UnlockedHeight })
+	if idx < len(heights) && heights[idx] == UnlockedHeight {
+		return
+	}
+	heights = append(heights, 0)
+	copy(heights[idx+1:], heights[idx:])
+	heights[idx] = UnlockedHeight
+	p.setLockedBalanceHeights(ctw, addr, heights)
+}
+
+func (p *Vault) removeLockedBalanceHeight(ctw *types.ContextWrapper, addr common.Address, UnlockedHeight uint32) {
+	heights := p.LockedBalanceHeights(ctw, addr)
+	idx := sort.Search(len(heights), func(i int) bool { return heights[i] >= UnlockedHeight })
+	if idx >= len(heights) || heights[idx] != UnlockedHeight {
+		return
+	}
+	heights = append(heights[:idx], heights[idx+1:]...)
+	p.setLockedBalanceHeights(ctw, addr, heights)
+}
+
 func (p *Vault) flushLockedBalanceMap(ctw *types.ContextWrapper, UnlockedHeight uint32) (map[common.Address]*amount.Amount, error) {
 	LockedBalanceMap := map[common.Address]*amount.Amount{}
 	if bs := ctw.ProcessData(toLockedBalanceCountKey(UnlockedHeight)); len(bs) > 0 {
@@ -113,10 +187,14 @@ func (p *Vault) flushLockedBalanceMap(ctw *types.ContextWrapper, UnlockedHeight
 			var addr common.Address
 			copy(addr[:], ctw.ProcessData(toLockedBalanceReverseKey(UnlockedHeight, i)))
 			LockedBalanceMap[addr] = p.LockedBalance(ctw, addr, UnlockedHeight)
+			if err := p.subTotal(ctw, tagTotalLockedBalance, LockedBalanceMap[addr]); err != nil {
+				return nil, err
+			}
 
 			ctw.SetProcessData(toLockedBalanceKey(UnlockedHeight, addr), nil)
 			ctw.SetProcessData(toLockedBalanceNumberKey(UnlockedHeight, addr), nil)
 			ctw.SetProcessData(toLockedBalanceReverseKey(UnlockedHeight, i), nil)
+			p.removeLockedBalanceHeight(ctw, addr, UnlockedHeight)
 		}
 		ctw.SetProcessData(toLockedBalanceCountKey(UnlockedHeight), nil)
 	}
@@ -217,3 +295,537 @@ func (p *Vault) SubCollectedFee(ctw *types.ContextWrapper, am *amount.Amount) er
 	}
 	return nil
 }
+
+// HeldBalance returns a total balance that is held by the vault for processes such as contracts
+func (p *Vault) HeldBalance(loader types.Loader) *amount.Amount {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(tagHeldBalance); len(bs) > 0 {
+		return amount.NewAmountFromBytes(bs)
+	} else {
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+// Hold moves the balance of the account of the address to the held balance
+func (p *Vault) Hold(ctw *types.ContextWrapper, addr common.Address, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	if err := p.SubBalance(ctw, addr, am); err != nil {
+		return err
+	}
+	ctw.SetProcessData(tagHeldBalance, p.HeldBalance(ctw).Add(am).Bytes())
+	return nil
+}
+
+// Release moves the held balance to the account of the address
+func (p *Vault) Release(ctw *types.ContextWrapper, addr common.Address, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	total := p.HeldBalance(ctw)
+	if total.Less(am) {
+		return ErrMinusHeldBalance
+	}
+	total = total.Sub(am)
+	if total.IsZero() {
+		ctw.SetProcessData(tagHeldBalance, nil)
+	} else {
+		ctw.SetProcessData(tagHeldBalance, total.Bytes())
+	}
+	if err := p.AddBalance(ctw, addr, am); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MultiProposal returns the pending proposal of the multi account
+func (p *Vault) MultiProposal(loader types.Loader, ID hash.Hash256) (*MultiProposal, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toMultiProposalKey(ID)); len(bs) > 0 {
+		mp := &MultiProposal{}
+		if err := encoding.Unmarshal(bs, &mp); err != nil {
+			return nil, err
+		}
+		return mp, nil
+	} else {
+		return nil, ErrNotExistMultiProposal
+	}
+}
+
+// IsUsedMultiProposalID returns the proposal id has been proposed or not
+func (p *Vault) IsUsedMultiProposalID(loader types.Loader, ID hash.Hash256) bool {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(toMultiProposalUsedKey(ID)); len(bs) > 0 {
+		return true
+	} else {
+		return false
+	}
+}
+
+func (p *Vault) addMultiProposal(ctw *types.ContextWrapper, ID hash.Hash256, mp *MultiProposal) error {
+	if p.IsUsedMultiProposalID(ctw, ID) {
+		return ErrExistMultiProposal
+	}
+	if err := p.setMultiProposal(ctw, ID, mp); err != nil {
+		return err
+	}
+	ctw.SetProcessData(toMultiProposalUsedKey(ID), []byte{1})
+
+	bs := ctw.ProcessData(toMultiProposalExpireKey(mp.ExpireHeight))
+	ctw.SetProcessData(toMultiProposalExpireKey(mp.ExpireHeight), append(append([]byte{}, bs...), ID[:]...))
+	return nil
+}
+
+func (p *Vault) setMultiProposal(ctw *types.ContextWrapper, ID hash.Hash256, mp *MultiProposal) error {
+	bs, err := encoding.Marshal(mp)
+	if err != nil {
+		return err
+	}
+	ctw.SetProcessData(toMultiProposalKey(ID), bs)
+	return nil
+}
+
+func (p *Vault) removeMultiProposal(ctw *types.ContextWrapper, ID hash.Hash256) {
+	ctw.SetProcessData(toMultiProposalKey(ID), nil)
+}
+
+func (p *Vault) flushExpiredMultiProposals(ctw *types.ContextWrapper, ExpireHeight uint32) {
+	bs := ctw.ProcessData(toMultiProposalExpireKey(ExpireHeight))
+	for i := 0; i+hash.Hash256Size <= len(bs); i += hash.Hash256Size {
+		var ID hash.Hash256
+		copy(ID[:], bs[i:])
+		p.removeMultiProposal(ctw, ID)
+	}
+	ctw.SetProcessData(toMultiProposalExpireKey(ExpireHeight), nil)
+}
+
+// approveMultiProposal adds the approval and executes the proposed transaction when approvals reach the required count
+func (p *Vault) approveMultiProposal(ctw *types.ContextWrapper, ID hash.Hash256, mp *MultiProposal, Approver common.PublicHash, index uint16) error {
+	acc, err := ctw.Account(mp.Address)
+	if err != nil {
+		return err
+	}
+	multiAcc, is := acc.(*MultiAccount)
+	if !is {
+		return ErrNotMultiAccount
+	}
+	if !isMultiAccountKeyHolder(multiAcc, Approver) {
+		return ErrNotMultiAccountKeyHolder
+	}
+	if mp.IsApproved(Approver) {
+		return ErrAlreadyApprovedMultiProposal
+	}
+	mp.Approvals = append(mp.Approvals, Approver)
+	if len(mp.Approvals) < int(multiAcc.Required) {
+		return p.setMultiProposal(ctw, ID, mp)
+	}
+
+	tx, err := mp.Transaction()
+	if err != nil {
+		return err
+	}
+	pid := uint8(mp.TxType >> 8)
+	tp, err := p.pm.Process(pid)
+	if err != nil {
+		return err
+	}
+	tctw := types.SwitchContextWrapper(pid, ctw)
+	if err := tx.Validate(tp, tctw, mp.Signers(multiAcc)); err != nil {
+		return err
+	}
+	if _, is := tx.(interface{ Seq() uint64 }); is {
+		tctw.AddSeq(mp.Address)
+	}
+	if err := tx.Execute(tp, tctw, index); err != nil {
+		return err
+	}
+	p.removeMultiProposal(ctw, ID)
+	return nil
+}
+
+// RecoveryKey returns the recovery key of the single account
+func (p *Vault) RecoveryKey(loader types.Loader, addr common.Address) (*RecoveryKey, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(addr, tagRecoveryKey); len(bs) > 0 {
+		rk := &RecoveryKey{}
+		if err := encoding.Unmarshal(bs, &rk); err != nil {
+			return nil, err
+		}
+		return rk, nil
+	} else {
+		return nil, ErrNotExistRecoveryKey
+	}
+}
+
+func (p *Vault) setRecoveryKey(ctw *types.ContextWrapper, addr common.Address, rk *RecoveryKey) error {
+	if rk == nil {
+		ctw.SetAccountData(addr, tagRecoveryKey, nil)
+		return nil
+	}
+	bs, err := encoding.Marshal(rk)
+	if err != nil {
+		return err
+	}
+	ctw.SetAccountData(addr, tagRecoveryKey, bs)
+	return nil
+}
+
+// RecoveryRequest returns the pending recovery request of the single account
+func (p *Vault) RecoveryRequest(loader types.Loader, addr common.Address) (*RecoveryRequest, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.AccountData(addr, tagRecoveryRequest); len(bs) > 0 {
+		rr := &RecoveryRequest{}
+		if err := encoding.Unmarshal(bs, &rr); err != nil {
+			return nil, err
+		}
+		return rr, nil
+	} else {
+		return nil, ErrNotExistRecoveryRequest
+	}
+}
+
+func (p *Vault) setRecoveryRequest(ctw *types.ContextWrapper, addr common.Address, rr *RecoveryRequest) error {
+	if rr == nil {
+		ctw.SetAccountData(addr, tagRecoveryRequest, nil)
+		return nil
+	}
+	bs, err := encoding.Marshal(rr)
+	if err != nil {
+		return err
+	}
+	ctw.SetAccountData(addr, tagRecoveryRequest, bs)
+	return nil
+}
+
+// rotateKey updates the key hash of the single account and removes the pending recovery request
+func (p *Vault) rotateKey(ctw *types.ContextWrapper, addr common.Address, KeyHash common.PublicHash, ByRecovery bool, index uint16) error {
+	acc, err := ctw.Account(addr)
+	if err != nil {
+		return err
+	}
+	singleAcc, is := acc.(*SingleAccount)
+	if !is {
+		return ErrNotSingleAccount
+	}
+	OldKeyHash := singleAcc.KeyHash
+	if OldKeyHash == KeyHash {
+		return ErrSameKeyHash
+	}
+	singleAcc.KeyHash = KeyHash
+	if err := p.setRecoveryRequest(ctw, addr, nil); err != nil {
+		return err
+	}
+
+	ev := &KeyRotatedEvent{
+		Height_:    ctw.TargetHeight(),
+		Index_:     index,
+		Address:    addr,
+		OldKeyHash: OldKeyHash,
+		NewKeyHash: KeyHash,
+		ByRecovery: ByRecovery,
+	}
+	if err := ctw.EmitEvent(ev); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AccountNames returns names that are registered or transferred to the address by the name service
+// The name of the account that is given at the creation is not included
+func (p *Vault) AccountNames(loader types.Loader, addr common.Address) ([]string, error) {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	names := []string{}
+	if bs := lw.AccountData(addr, tagAccountNames); len(bs) > 0 {
+		if err := encoding.Unmarshal(bs, &names); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+func (p *Vault) setAccountNames(ctw *types.ContextWrapper, addr common.Address, names []string) error {
+	if len(names) == 0 {
+		ctw.SetAccountData(addr, tagAccountNames, nil)
+		return nil
+	}
+	bs, err := encoding.Marshal(names)
+	if err != nil {
+		return err
+	}
+	ctw.SetAccountData(addr, tagAccountNames, bs)
+	return nil
+}
+
+// HasAccountNameOf returns the name is owned by the address or not
+// The name of the account that is given at the creation is owned while it is bound to the account
+func (p *Vault) HasAccountNameOf(loader types.Loader, addr common.Address, Name string) (bool, error) {
+	names, err := p.AccountNames(loader, addr)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range names {
+		if v == Name {
+			return true, nil
+		}
+	}
+	return p.hasCreationName(loader, addr, Name)
+}
+
+func (p *Vault) hasCreationName(loader types.Loader, addr common.Address, Name string) (bool, error) {
+	acc, err := loader.Account(addr)
+	if err != nil {
+		return false, err
+	}
+	if acc.Name() != Name {
+		return false, nil
+	}
+	if nameAddr, err := loader.AddressByName(Name); err != nil {
+		if err != types.ErrNotExistAccount && err != types.ErrDeletedAccount {
+			return false, err
+		}
+		return false, nil
+	} else {
+		return nameAddr == addr, nil
+	}
+}
+
+func (p *Vault) registerAccountName(ctw *types.ContextWrapper, addr common.Address, Name string) error {
+	names, err := p.AccountNames(ctw, addr)
+	if err != nil {
+		return err
+	}
+	if len(names) >= MaxAccountNames {
+		return ErrTooManyAccountNames
+	}
+	if err := ctw.SetAccountName(Name, addr); err != nil {
+		return err
+	}
+	return p.setAccountNames(ctw, addr, append(names, Name))
+}
+
+func (p *Vault) releaseAccountName(ctw *types.ContextWrapper, addr common.Address, Name string) error {
+	names, err := p.AccountNames(ctw, addr)
+	if err != nil {
+		return err
+	}
+	for i, v := range names {
+		if v == Name {
+			if err := ctw.DeleteAccountName(Name); err != nil {
+				return err
+			}
+			return p.setAccountNames(ctw, addr, append(names[:i], names[i+1:]...))
+		}
+	}
+	if has, err := p.hasCreationName(ctw, addr, Name); err != nil {
+		return err
+	} else if !has {
+		return ErrNotOwnedAccountName
+	}
+	return ctw.DeleteAccountName(Name)
+}
+
+// ReleaseAccountNames releases names of the account, it should be called before the account is deleted
+// The name of the account that is given at the creation is released after NameReleaseUpgrade
+func (p *Vault) ReleaseAccountNames(ctw *types.ContextWrapper, acc types.Account) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	names, err := p.AccountNames(ctw, acc.Address())
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		for _, Name := range names {
+			if err := ctw.DeleteAccountName(Name); err != nil {
+				return err
+			}
+		}
+		if err := p.setAccountNames(ctw, acc.Address(), nil); err != nil {
+			return err
+		}
+	}
+	if p.IsUpgraded(ctw, NameReleaseUpgrade) {
+		if has, err := p.hasCreationName(ctw, acc.Address(), acc.Name()); err != nil {
+			return err
+		} else if has {
+			if err := ctw.DeleteAccountName(acc.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Vault) total(loader types.Loader, tag []byte) *amount.Amount {
+	lw := types.NewLoaderWrapper(p.pid, loader)
+
+	if bs := lw.ProcessData(tag); len(bs) > 0 {
+		return amount.NewAmountFromBytes(bs)
+	} else {
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+func (p *Vault) setTotal(ctw *types.ContextWrapper, tag []byte, am *amount.Amount) {
+	if am.IsZero() {
+		ctw.SetProcessData(tag, nil)
+	} else {
+		ctw.SetProcessData(tag, am.Bytes())
+	}
+}
+
+// addTotal and subTotal do nothing before SupplyUpgrade, so blocks of the running chain keep their results
+func (p *Vault) addTotal(ctw *types.ContextWrapper, tag []byte, am *amount.Amount) {
+	if !p.IsUpgraded(ctw, SupplyUpgrade) {
+		return
+	}
+	p.setTotal(ctw, tag, p.total(ctw, tag).Add(am))
+}
+
+func (p *Vault) subTotal(ctw *types.ContextWrapper, tag []byte, am *amount.Amount) error {
+	if !p.IsUpgraded(ctw, SupplyUpgrade) {
+		return nil
+	}
+	total := p.total(ctw, tag)
+	if total.Less(am) {
+		return ErrMinusSupplyTotal
+	}
+	p.setTotal(ctw, tag, total.Sub(am))
+	return nil
+}
+
+// seedSupply sets the balance total and the locked balance total from the full account scan
+// Locked balances of deleted accounts are included because they are burned when they are unlocked
+// The formulator seeds its totals by SeedFormulatorSupply, and the burned amount is counted from the activation height
+func (p *Vault) seedSupply(ctw *types.ContextWrapper) error {
+	addrs, err := ctw.Addresses()
+	if err != nil {
+		return err
+	}
+	Balance := amount.NewCoinAmount(0, 0)
+	LockedBalance := amount.NewCoinAmount(0, 0)
+	for _, addr := range addrs {
+		for _, UnlockedHeight := range p.LockedBalanceHeights(ctw, addr) {
+			LockedBalance = LockedBalance.Add(p.LockedBalance(ctw, addr, UnlockedHeight))
+		}
+		if has, err := ctw.HasAccount(addr); err != nil {
+			if err != types.ErrDeletedAccount {
+				return err
+			}
+		} else if has {
+			Balance = Balance.Add(p.Balance(ctw, addr))
+		}
+	}
+	p.setTotal(ctw, tagTotalBalance, Balance)
+	p.setTotal(ctw, tagTotalLockedBalance, LockedBalance)
+	return nil
+}
+
+// SeedFormulatorSupply sets totals of formulator accounts, it is called by the formulator at the activation height of SupplyUpgrade
+func (p *Vault) SeedFormulatorSupply(ctw *types.ContextWrapper, FormulatorAmount *amount.Amount, StakingAmount *amount.Amount, UnstakingAmount *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	if !p.IsSeedHeight(ctw, SupplyUpgrade) {
+		return ErrInvalidSeedHeight
+	}
+	p.setTotal(ctw, tagTotalFormulator, FormulatorAmount)
+	p.setTotal(ctw, tagTotalStaking, StakingAmount)
+	p.setTotal(ctw, tagTotalUnstaking, UnstakingAmount)
+	return nil
+}
+
+// Supply returns running totals of the coin that are maintained by the vault
+func (p *Vault) Supply(loader types.Loader) (*Supply, error) {
+	if !p.IsUpgraded(loader, SupplyUpgrade) {
+		return nil, ErrNotUpgraded
+	}
+	s := &Supply{
+		Balance:          p.total(loader, tagTotalBalance),
+		LockedBalance:    p.total(loader, tagTotalLockedBalance),
+		FormulatorAmount: p.total(loader, tagTotalFormulator),
+		StakingAmount:    p.total(loader, tagTotalStaking),
+		UnstakingAmount:  p.total(loader, tagTotalUnstaking),
+		HeldBalance:      p.HeldBalance(loader),
+		CollectedFee:     p.total(loader, tagCollectedFee),
+		BurnedAmount:     p.total(loader, tagTotalBurned),
+	}
+	s.TotalSupply = s.Balance.Add(s.LockedBalance).Add(s.FormulatorAmount).Add(s.StakingAmount).Add(s.UnstakingAmount).Add(s.HeldBalance).Add(s.CollectedFee)
+	return s, nil
+}
+
+// AddBurnedAmount adds the amount that is removed from the supply
+func (p *Vault) AddBurnedAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	p.addTotal(ctw, tagTotalBurned, am)
+	return nil
+}
+
+// AddFormulatorAmount adds the amount that is locked in formulator accounts
+func (p *Vault) AddFormulatorAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	p.addTotal(ctw, tagTotalFormulator, am)
+	return nil
+}
+
+// SubFormulatorAmount subtracts the amount that is locked in formulator accounts
+func (p *Vault) SubFormulatorAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	return p.subTotal(ctw, tagTotalFormulator, am)
+}
+
+// AddStakingAmount adds the amount that is staked to hyper formulators
+func (p *Vault) AddStakingAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	p.addTotal(ctw, tagTotalStaking, am)
+	return nil
+}
+
+// SubStakingAmount subtracts the amount that is staked to hyper formulators
+func (p *Vault) SubStakingAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	return p.subTotal(ctw, tagTotalStaking, am)
+}
+
+// AddUnstakingAmount adds the amount that is waiting for the unstaking unlock
+func (p *Vault) AddUnstakingAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	zero := amount.NewCoinAmount(0, 0)
+	if am.Less(zero) {
+		return ErrMinusInput
+	}
+	p.addTotal(ctw, tagTotalUnstaking, am)
+	return nil
+}
+
+// SubUnstakingAmount subtracts the amount that is waiting for the unstaking unlock
+func (p *Vault) SubUnstakingAmount(ctw *types.ContextWrapper, am *amount.Amount) error {
+	ctw = types.SwitchContextWrapper(p.pid, ctw)
+
+	return p.subTotal(ctw, tagTotalUnstaking, am)
+}
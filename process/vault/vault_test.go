@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/amount"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/process/admin"
+	"github.com/fletaio/fleta_testnet/process/processtest"
+)
+
+type txFixture struct {
+	p     *Vault
+	ctx   *types.Context
+	Addrs []common.Address
+	Keys  []common.PublicHash
+}
+
+// newTxFixture creates single accounts of the names that have 100 coins each
+func newTxFixture(t *testing.T, Names ...string) *txFixture {
+	p := NewVault(2)
+	if _, err := processtest.NewProcessManager(admin.NewAdmin(1), p); err != nil {
+		t.Fatal(err)
+	}
+	fx := &txFixture{
+		p:   p,
+		ctx: types.NewEmptyContext(),
+	}
+	ctw := types.NewContextWrapper(p.ID(), fx.ctx)
+	if err := p.InitUpgrades(ctw); err != nil {
+		t.Fatal(err)
+	}
+	for i, Name := range Names {
+		acc := &SingleAccount{
+			Address_: common.NewAddress(0, uint16(i+1), 0),
+			Name_:    Name,
+			KeyHash:  common.PublicHash{byte(i + 1)},
+		}
+		if err := ctw.CreateAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.AddBalance(ctw, acc.Address(), amount.NewCoinAmount(100, 0)); err != nil {
+			t.Fatal(err)
+		}
+		fx.Addrs = append(fx.Addrs, acc.Address())
+		fx.Keys = append(fx.Keys, acc.KeyHash)
+	}
+	return fx
+}
+
+func (fx *txFixture) seq(addr common.Address) uint64 {
+	return processtest.NextSeq(fx.ctx, addr)
+}
+
+// execute validates the transaction with keys of signers and executes it as the chain does
+func (fx *txFixture) execute(tx types.Transaction, Signers ...common.PublicHash) error {
+	return processtest.Execute(fx.ctx, fx.p, tx, Signers...)
+}
+
+// advance closes blocks until the target height of the context becomes the height
+func (fx *txFixture) advance(t *testing.T, Height uint32) {
+	for fx.ctx.TargetHeight() < Height {
+		b := &types.Block{
+			Header: types.Header{
+				Height: fx.ctx.TargetHeight(),
+			},
+		}
+		if err := fx.p.AfterExecuteTransactions(b, types.NewContextWrapper(fx.p.ID(), fx.ctx)); err != nil {
+			t.Fatal(err)
+		}
+		fx.ctx = processtest.Advance(fx.ctx, fx.ctx.TargetHeight()+1)
+	}
+}
+
+func checkBalance(t *testing.T, p *Vault, loader types.Loader, addr common.Address, Expected *amount.Amount) {
+	t.Helper()
+	if am := p.Balance(loader, addr); !am.Equal(Expected) {
+		t.Fatalf("invalid balance %v, expected %v", am, Expected)
+	}
+}
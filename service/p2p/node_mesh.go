@@ -35,6 +35,9 @@ type NodeMesh struct {
 	clientPeerMap   map[string]peer.Peer
 	serverPeerMap   map[string]peer.Peer
 	nodePoolManager nodepoolmanage.Manager
+	transport       Transport
+	lstn            net.Listener
+	isClose         bool
 }
 
 // NewNodeMesh returns a NodeMesh
@@ -46,6 +49,7 @@ func NewNodeMesh(ChainID uint8, key key.Key, SeedNodeMap map[common.PublicHash]s
 		nodeSet:       map[common.PublicHash]string{},
 		clientPeerMap: map[string]peer.Peer{},
 		serverPeerMap: map[string]peer.Peer{},
+		transport:     NewTCPTransport(),
 	}
 	manager, err := nodepoolmanage.NewNodePoolManage(peerStorePath, ms)
 	if err != nil {
@@ -59,6 +63,36 @@ func NewNodeMesh(ChainID uint8, key key.Key, SeedNodeMap map[common.PublicHash]s
 	return ms
 }
 
+// SetTransport sets the transport of the node mesh (it should be called before running)
+func (ms *NodeMesh) SetTransport(tr Transport) {
+	ms.Lock()
+	defer ms.Unlock()
+
+	ms.transport = tr
+}
+
+// Close terminates the node mesh and disconnects all peers
+func (ms *NodeMesh) Close() {
+	ms.Lock()
+	ms.isClose = true
+	lstn := ms.lstn
+	peers := []peer.Peer{}
+	for _, p := range ms.clientPeerMap {
+		peers = append(peers, p)
+	}
+	for _, p := range ms.serverPeerMap {
+		peers = append(peers, p)
+	}
+	ms.Unlock()
+
+	if lstn != nil {
+		lstn.Close()
+	}
+	for _, p := range peers {
+		p.Close()
+	}
+}
+
 // Run starts the node mesh
 func (ms *NodeMesh) Run(BindAddress string) {
 	ms.BindAddress = BindAddress
@@ -70,11 +104,12 @@ func (ms *NodeMesh) Run(BindAddress string) {
 				for {
 					ID := string(pubhash[:])
 					ms.Lock()
+					isClose := ms.isClose
 					_, hasInSet := ms.nodeSet[pubhash]
 					_, hasC := ms.clientPeerMap[ID]
 					_, hasS := ms.serverPeerMap[ID]
 					ms.Unlock()
-					if !hasInSet {
+					if isClose || !hasInSet {
 						return
 					}
 					if !hasC && !hasS {
@@ -299,7 +334,7 @@ func (ms *NodeMesh) AddPeerList(ips []string, hashs []string) {
 }
 
 func (ms *NodeMesh) client(Address string, TargetPubHash common.PublicHash) error {
-	conn, err := net.DialTimeout("tcp", Address, 10*time.Second)
+	conn, err := ms.transport.DialTimeout(Address, 10*time.Second)
 	if err != nil {
 		return err
 	}
@@ -351,14 +386,28 @@ func (ms *NodeMesh) client(Address string, TargetPubHash common.PublicHash) erro
 }
 
 func (ms *NodeMesh) server(BindAddress string) error {
-	lstn, err := net.Listen("tcp", BindAddress)
+	lstn, err := ms.transport.Listen(BindAddress)
 	if err != nil {
 		return err
 	}
+	ms.Lock()
+	if ms.isClose {
+		ms.Unlock()
+		lstn.Close()
+		return nil
+	}
+	ms.lstn = lstn
+	ms.Unlock()
 	rlog.Println(common.NewPublicHash(ms.key.PublicKey()), "Start to Listen", BindAddress)
 	for {
 		conn, err := lstn.Accept()
 		if err != nil {
+			ms.Lock()
+			isClose := ms.isClose
+			ms.Unlock()
+			if isClose {
+				return nil
+			}
 			return err
 		}
 		go func() {
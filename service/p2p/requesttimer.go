@@ -10,12 +10,29 @@ type RequestExpireHandler interface {
 	OnTimerExpired(height uint32, value string)
 }
 
+// RequestClock provides the current time and the sleep to the RequestTimer
+type RequestClock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type systemClock struct{}
+
+func (c *systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
 // RequestTimer triggers a event when a request is expired
 type RequestTimer struct {
 	sync.Mutex
 	timerMap map[uint32]*requestTimerItem
 	valueMap map[string]map[uint32]bool
 	handler  RequestExpireHandler
+	clock    RequestClock
 }
 
 // NewRequestTimer returns a RequestTimer
@@ -24,10 +41,19 @@ func NewRequestTimer(handler RequestExpireHandler) *RequestTimer {
 		timerMap: map[uint32]*requestTimerItem{},
 		valueMap: map[string]map[uint32]bool{},
 		handler:  handler,
+		clock:    &systemClock{},
 	}
 	return rm
 }
 
+// SetClock sets the clock of the timer (it should be called before running)
+func (rm *RequestTimer) SetClock(clock RequestClock) {
+	rm.Lock()
+	defer rm.Unlock()
+
+	rm.clock = clock
+}
+
 // Exist returns the target height request exists or not
 func (rm *RequestTimer) Exist(height uint32) bool {
 	rm.Lock()
@@ -44,7 +70,7 @@ func (rm *RequestTimer) Add(height uint32, t time.Duration, value string) {
 
 	rm.timerMap[height] = &requestTimerItem{
 		Height:    height,
-		ExpiredAt: uint64(time.Now().UnixNano()) + uint64(t),
+		ExpiredAt: uint64(rm.clock.Now().UnixNano()) + uint64(t),
 		Value:     value,
 	}
 	heightMap, has := rm.valueMap[value]
@@ -71,39 +97,39 @@ func (rm *RequestTimer) RemovesByValue(value string) {
 
 // Run is the main loop of RequestTimer
 func (rm *RequestTimer) Run() {
-	timer := time.NewTimer(100 * time.Millisecond)
+	rm.Lock()
+	clock := rm.clock
+	rm.Unlock()
+
 	for {
-		select {
-		case <-timer.C:
-			expired := []*requestTimerItem{}
-			now := uint64(time.Now().UnixNano())
-			remainMap := map[uint32]*requestTimerItem{}
-			rm.Lock()
-			for h, v := range rm.timerMap {
-				if v.ExpiredAt <= now {
-					expired = append(expired, v)
-
-					heightMap, has := rm.valueMap[v.Value]
-					if has {
-						delete(heightMap, v.Height)
-					}
-					if len(heightMap) == 0 {
-						delete(rm.valueMap, v.Value)
-					}
-				} else {
-					remainMap[h] = v
-				}
-			}
-			rm.timerMap = remainMap
-			rm.Unlock()
+		clock.Sleep(100 * time.Millisecond)
+
+		expired := []*requestTimerItem{}
+		now := uint64(clock.Now().UnixNano())
+		remainMap := map[uint32]*requestTimerItem{}
+		rm.Lock()
+		for h, v := range rm.timerMap {
+			if v.ExpiredAt <= now {
+				expired = append(expired, v)
 
-			if rm.handler != nil {
-				for _, v := range expired {
-					rm.handler.OnTimerExpired(v.Height, v.Value)
+				heightMap, has := rm.valueMap[v.Value]
+				if has {
+					delete(heightMap, v.Height)
+				}
+				if len(heightMap) == 0 {
+					delete(rm.valueMap, v.Value)
 				}
+			} else {
+				remainMap[h] = v
 			}
+		}
+		rm.timerMap = remainMap
+		rm.Unlock()
 
-			timer.Reset(100 * time.Millisecond)
+		if rm.handler != nil {
+			for _, v := range expired {
+				rm.handler.OnTimerExpired(v.Height, v.Value)
+			}
 		}
 	}
 }
@@ -0,0 +1,30 @@
+package p2p
+
+import (
+	"net"
+	"time"
+)
+
+// Transport provides listeners and connections of the mesh
+type Transport interface {
+	Listen(BindAddress string) (net.Listener, error)
+	DialTimeout(Address string, timeout time.Duration) (net.Conn, error)
+}
+
+// TCPTransport provides tcp listeners and connections
+type TCPTransport struct{}
+
+// NewTCPTransport returns a TCPTransport
+func NewTCPTransport() *TCPTransport {
+	return &TCPTransport{}
+}
+
+// Listen announces on the tcp address
+func (tr *TCPTransport) Listen(BindAddress string) (net.Listener, error) {
+	return net.Listen("tcp", BindAddress)
+}
+
+// DialTimeout connects to the tcp address with a timeout
+func (tr *TCPTransport) DialTimeout(Address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", Address, timeout)
+}
@@ -0,0 +1,21 @@
+package signer
+
+import "errors"
+
+// signer errors
+var (
+	ErrInvalidChainID      = errors.New("invalid chain id")
+	ErrDoubleSign          = errors.New("double sign")
+	ErrPastHeight          = errors.New("past height")
+	ErrUnknownRequestKind  = errors.New("unknown request kind")
+	ErrExceedMessageSize   = errors.New("exceed message size")
+	ErrInvalidPublicKey    = errors.New("invalid public key")
+	ErrInvalidCertificate  = errors.New("invalid certificate")
+	ErrSignerClosed        = errors.New("signer closed")
+	ErrNotSupported        = errors.New("not supported")
+	ErrInvalidSignResponse = errors.New("invalid sign response")
+	ErrInvalidRequest      = errors.New("invalid request")
+	ErrInvalidHandshake    = errors.New("invalid handshake")
+	ErrInvalidTransaction  = errors.New("invalid transaction")
+	ErrInvalidSignRecord   = errors.New("invalid sign record")
+)
@@ -0,0 +1,99 @@
+// Package signer implements the remote signer that keeps keys of formulators and observers off internet-facing hosts
+// Nodes connect to the signer daemon by TLS with mutual authentication over the unix socket or TCP,
+// and the daemon only signs typed requests that it checks and records so that it never signs two conflicting messages in the same round
+package signer
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// MaxMessageSize is the max size of a request or a response
+const MaxMessageSize = 1 << 20
+
+// request kinds
+const (
+	PublicKeyRequestKind       = RequestKind(1)
+	HeaderRequestKind          = RequestKind(3)
+	BlockSignRequestKind       = RequestKind(4)
+	RoundVoteRequestKind       = RequestKind(5)
+	RoundVoteAckRequestKind    = RequestKind(6)
+	BlockVoteRequestKind       = RequestKind(7)
+	BlockGenRequestRequestKind = RequestKind(8)
+	HandshakeRequestKind       = RequestKind(9)
+	TransactionRequestKind     = RequestKind(10)
+	VRFRequestKind             = RequestKind(11)
+)
+
+// RequestKind is the kind of the sign request
+type RequestKind uint8
+
+// Request is the message that is sent to the signer daemon
+// The daemon computes the signed hash from the typed content, so only the fields of the kind are used
+// Header and GeneratorSignature are used by header and block sign requests, TxType and TxData are the encoded transaction
+// VRFAlpha is the seed that is proved by the VRF request
+type Request struct {
+	Kind               RequestKind
+	Header             types.Header
+	GeneratorSignature common.Signature
+	RoundVote          *pof.RoundVote
+	RoundVoteAck       *pof.RoundVoteAck
+	BlockVote          *pof.BlockVote
+	BlockGenRequest    *pof.BlockGenRequest
+	Handshake          []byte
+	TxType             uint16
+	TxData             []byte
+	VRFAlpha           []byte
+}
+
+// Response is the message that is replied by the signer daemon
+// VRFOutput and VRFProof are only used by the VRF request
+type Response struct {
+	PublicKey common.PublicKey
+	Signature common.Signature
+	VRFOutput hash.Hash256
+	VRFProof  []byte
+	Error     string
+}
+
+func writeMessage(w io.Writer, v interface{}) error {
+	data, err := encoding.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxMessageSize {
+		return ErrExceedMessageSize
+	}
+	bs := make([]byte, 4+len(data))
+	binary.LittleEndian.PutUint32(bs, uint32(len(data)))
+	copy(bs[4:], data)
+	if _, err := w.Write(bs); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readMessage(r io.Reader, v interface{}) error {
+	bs := make([]byte, 4)
+	if _, err := io.ReadFull(r, bs); err != nil {
+		return err
+	}
+	Len := binary.LittleEndian.Uint32(bs)
+	if Len > MaxMessageSize {
+		return ErrExceedMessageSize
+	}
+	data := make([]byte, Len)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	if err := encoding.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package signer
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/vrf"
+	"github.com/fletaio/fleta_testnet/core/chain"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// RemoteKey is the key.Key that requests signatures to the signer daemon
+// It reconnects when the connection is broken, so the node can keep running while the daemon restarts
+type RemoteKey struct {
+	sync.Mutex
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	conn      net.Conn
+	pubkey    common.PublicKey
+}
+
+// NewRemoteKey returns a RemoteKey that is connected to the daemon at the network address
+func NewRemoteKey(Network string, Address string, TLSConfig *tls.Config) (*RemoteKey, error) {
+	ac := &RemoteKey{
+		network:   Network,
+		address:   Address,
+		tlsConfig: TLSConfig,
+		timeout:   10 * time.Second,
+	}
+	res, err := ac.request(&Request{
+		Kind: PublicKeyRequestKind,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.PublicKey == (common.PublicKey{}) {
+		return nil, ErrInvalidPublicKey
+	}
+	ac.pubkey = res.PublicKey
+	return ac, nil
+}
+
+// PublicKey returns the public key of the remote key
+func (ac *RemoteKey) PublicKey() common.PublicKey {
+	return ac.pubkey
+}
+
+// Sign is not supported by the remote key because the daemon only signs typed requests
+func (ac *RemoteKey) Sign(h hash.Hash256) (common.Signature, error) {
+	return common.Signature{}, ErrNotSupported
+}
+
+// SignWithPassphrase is not supported by the remote key
+func (ac *RemoteKey) SignWithPassphrase(h hash.Hash256, passphrase []byte) (common.Signature, error) {
+	return common.Signature{}, ErrNotSupported
+}
+
+// SignHeader requests the generator signature of the header, the daemon checks double signing by the height
+func (ac *RemoteKey) SignHeader(bh *types.Header) (common.Signature, error) {
+	return ac.sign(encoding.Hash(bh), &Request{
+		Kind:   HeaderRequestKind,
+		Header: *bh,
+	})
+}
+
+// SignBlockSign requests the observer signature of the block sign of the header
+func (ac *RemoteKey) SignBlockSign(bh *types.Header, GeneratorSignature common.Signature) (common.Signature, error) {
+	bs := &types.BlockSign{
+		HeaderHash:         encoding.Hash(bh),
+		GeneratorSignature: GeneratorSignature,
+	}
+	return ac.sign(encoding.Hash(bs), &Request{
+		Kind:               BlockSignRequestKind,
+		Header:             *bh,
+		GeneratorSignature: GeneratorSignature,
+	})
+}
+
+// SignRoundVote requests the signature of the round vote
+func (ac *RemoteKey) SignRoundVote(vt *pof.RoundVote) (common.Signature, error) {
+	return ac.sign(encoding.Hash(vt), &Request{
+		Kind:      RoundVoteRequestKind,
+		RoundVote: vt,
+	})
+}
+
+// SignRoundVoteAck requests the signature of the round vote ack, the daemon checks double signing by the round
+func (ac *RemoteKey) SignRoundVoteAck(vt *pof.RoundVoteAck) (common.Signature, error) {
+	return ac.sign(encoding.Hash(vt), &Request{
+		Kind:         RoundVoteAckRequestKind,
+		RoundVoteAck: vt,
+	})
+}
+
+// SignBlockVote requests the signature of the block vote, the daemon checks double signing by the round of the header
+func (ac *RemoteKey) SignBlockVote(vt *pof.BlockVote) (common.Signature, error) {
+	return ac.sign(encoding.Hash(vt), &Request{
+		Kind:      BlockVoteRequestKind,
+		BlockVote: vt,
+	})
+}
+
+// SignBlockGenRequest requests the signature of the block gen request
+func (ac *RemoteKey) SignBlockGenRequest(req *pof.BlockGenRequest) (common.Signature, error) {
+	return ac.sign(encoding.Hash(req), &Request{
+		Kind:            BlockGenRequestRequestKind,
+		BlockGenRequest: req,
+	})
+}
+
+// SignHandshake requests the signature of the mesh handshake
+func (ac *RemoteKey) SignHandshake(req []byte) (common.Signature, error) {
+	return ac.sign(hash.Hash(req), &Request{
+		Kind:      HandshakeRequestKind,
+		Handshake: req,
+	})
+}
+
+// SignTransaction requests the signature of the transaction, it fails when the daemon is not for the chain
+func (ac *RemoteKey) SignTransaction(ChainID uint8, tx types.Transaction) (common.Signature, error) {
+	t, err := encoding.Factory("transaction").TypeOf(tx)
+	if err != nil {
+		return common.Signature{}, err
+	}
+	data, err := encoding.Marshal(tx)
+	if err != nil {
+		return common.Signature{}, err
+	}
+	return ac.sign(chain.HashTransactionByType(ChainID, t, tx), &Request{
+		Kind:   TransactionRequestKind,
+		TxType: t,
+		TxData: data,
+	})
+}
+
+// ProveVRF requests the VRF output and the proof of the seed, the proof is verified by the public key of the remote key
+func (ac *RemoteKey) ProveVRF(alpha []byte) (hash.Hash256, []byte, error) {
+	res, err := ac.request(&Request{
+		Kind:     VRFRequestKind,
+		VRFAlpha: alpha,
+	})
+	if err != nil {
+		return hash.Hash256{}, nil, err
+	}
+	if Output, err := vrf.Verify(ac.pubkey, alpha, res.VRFProof); err != nil || Output != res.VRFOutput {
+		return hash.Hash256{}, nil, ErrInvalidSignResponse
+	}
+	return res.VRFOutput, res.VRFProof, nil
+}
+
+// Verify checks that the signatures is generated by the hash and the key or not
+func (ac *RemoteKey) Verify(h hash.Hash256, sig common.Signature) bool {
+	pubkey, err := common.RecoverPubkey(h, sig)
+	if err != nil {
+		return false
+	}
+	return pubkey == ac.pubkey
+}
+
+// Close closes the connection to the daemon
+func (ac *RemoteKey) Close() {
+	ac.Lock()
+	defer ac.Unlock()
+
+	if ac.conn != nil {
+		ac.conn.Close()
+		ac.conn = nil
+	}
+}
+
+func (ac *RemoteKey) sign(h hash.Hash256, req *Request) (common.Signature, error) {
+	res, err := ac.request(req)
+	if err != nil {
+		return common.Signature{}, err
+	}
+	if !ac.Verify(h, res.Signature) {
+		return common.Signature{}, ErrInvalidSignResponse
+	}
+	return res.Signature, nil
+}
+
+func (ac *RemoteKey) request(req *Request) (*Response, error) {
+	ac.Lock()
+	defer ac.Unlock()
+
+	res, err := ac.send(req)
+	if err != nil {
+		// retry once by the new connection
+		if ac.conn != nil {
+			ac.conn.Close()
+			ac.conn = nil
+		}
+		res, err = ac.send(req)
+		if err != nil {
+			if ac.conn != nil {
+				ac.conn.Close()
+				ac.conn = nil
+			}
+			return nil, err
+		}
+	}
+	if len(res.Error) > 0 {
+		return nil, errors.New(res.Error)
+	}
+	return res, nil
+}
+
+func (ac *RemoteKey) send(req *Request) (*Response, error) {
+	if ac.conn == nil {
+		dialer := &net.Dialer{Timeout: ac.timeout}
+		conn, err := tls.DialWithDialer(dialer, ac.network, ac.address, ac.tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		ac.conn = conn
+	}
+	ac.conn.SetDeadline(time.Now().Add(ac.timeout))
+	if err := writeMessage(ac.conn, req); err != nil {
+		return nil, err
+	}
+	var res Response
+	if err := readMessage(ac.conn, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
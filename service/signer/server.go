@@ -0,0 +1,328 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+// Server is the signer daemon that holds the key
+// It never signs raw hashes, it computes the signed hash from the typed request after checking it
+// Headers, block signs (and block votes of the same header) and round vote acks are recorded by the round (height and timeout count)
+// in the backend before signing, so the conflicting message of the recorded round is not signed even after the restart
+// Handshakes are checked by the chain id and the timestamp, and transactions are hashed by the chain id of the daemon
+// VRF outputs of seeds are proved by the key of the daemon
+type Server struct {
+	sync.Mutex
+	key      key.Key
+	chainID  uint8
+	records  *signRecords
+	listener net.Listener
+	isClose  bool
+}
+
+// NewServer returns a Server that signs by the key for the chain and loads signed records of the backend
+func NewServer(Key key.Key, ChainID uint8, back backend.StoreBackend) (*Server, error) {
+	records, err := loadSignRecords(back)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		key:     Key,
+		chainID: ChainID,
+		records: records,
+	}
+	return s, nil
+}
+
+// Run accepts nodes on the network address by the TLS config until closed
+func (s *Server) Run(Network string, Address string, TLSConfig *tls.Config) error {
+	ln, err := tls.Listen(Network, Address, TLSConfig)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	if s.isClose {
+		s.Unlock()
+		ln.Close()
+		return ErrSignerClosed
+	}
+	s.listener = ln
+	s.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.Lock()
+			isClose := s.isClose
+			s.Unlock()
+			if isClose {
+				return nil
+			}
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// Close terminates the server
+func (s *Server) Close() {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.isClose {
+		s.isClose = true
+		if s.listener != nil {
+			s.listener.Close()
+		}
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var req Request
+		if err := readMessage(conn, &req); err != nil {
+			return
+		}
+		res := &Response{
+			PublicKey: s.key.PublicKey(),
+		}
+		var err error
+		if req.Kind == VRFRequestKind {
+			res.VRFOutput, res.VRFProof, err = s.proveVRF(req.VRFAlpha)
+		} else {
+			res.Signature, err = s.handle(&req)
+		}
+		if err != nil {
+			log.Println("Signer", "Request", req.Kind, err)
+			res.Error = err.Error()
+		}
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := writeMessage(conn, res); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req *Request) (common.Signature, error) {
+	switch req.Kind {
+	case PublicKeyRequestKind:
+		return common.Signature{}, nil
+	case HeaderRequestKind:
+		return s.signHeader(&req.Header)
+	case BlockSignRequestKind:
+		return s.signBlockSign(&req.Header, req.GeneratorSignature)
+	case RoundVoteRequestKind:
+		return s.signRoundVote(req.RoundVote)
+	case RoundVoteAckRequestKind:
+		return s.signRoundVoteAck(req.RoundVoteAck)
+	case BlockVoteRequestKind:
+		return s.signBlockVote(req.BlockVote)
+	case BlockGenRequestRequestKind:
+		return s.signBlockGenRequest(req.BlockGenRequest)
+	case HandshakeRequestKind:
+		return s.signHandshake(req.Handshake)
+	case TransactionRequestKind:
+		return s.signTransaction(req.TxType, req.TxData)
+	default:
+		return common.Signature{}, ErrUnknownRequestKind
+	}
+}
+
+func (s *Server) signHeader(bh *types.Header) (common.Signature, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if bh.ChainID != s.chainID {
+		return common.Signature{}, ErrInvalidChainID
+	}
+	if bh.Height < s.records.maxHeightMap[headerRecordKind] {
+		return common.Signature{}, ErrPastHeight
+	}
+	TimeoutCount, err := types.DecodeTimeoutCount(bh.ConsensusData)
+	if err != nil {
+		return common.Signature{}, err
+	}
+	HeaderHash := encoding.Hash(bh)
+	if err := s.records.record(recordKey{Kind: headerRecordKind, Height: bh.Height, TimeoutCount: TimeoutCount}, HeaderHash); err != nil {
+		return common.Signature{}, err
+	}
+	return s.key.Sign(HeaderHash)
+}
+
+func (s *Server) signBlockSign(bh *types.Header, GeneratorSignature common.Signature) (common.Signature, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	HeaderHash, err := s.recordVote(bh)
+	if err != nil {
+		return common.Signature{}, err
+	}
+	bs := &types.BlockSign{
+		HeaderHash:         HeaderHash,
+		GeneratorSignature: GeneratorSignature,
+	}
+	return s.key.Sign(encoding.Hash(bs))
+}
+
+func (s *Server) signBlockVote(vt *pof.BlockVote) (common.Signature, error) {
+	if vt == nil || vt.Header == nil || vt.TargetHeight != vt.Header.Height {
+		return common.Signature{}, ErrInvalidRequest
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, err := s.recordVote(vt.Header); err != nil {
+		return common.Signature{}, err
+	}
+	return s.key.Sign(encoding.Hash(vt))
+}
+
+// recordVote records the header of the block sign or the block vote, they are the same vote of the round
+func (s *Server) recordVote(bh *types.Header) (hash.Hash256, error) {
+	if bh.ChainID != s.chainID {
+		return hash.Hash256{}, ErrInvalidChainID
+	}
+	TimeoutCount, err := types.DecodeTimeoutCount(bh.ConsensusData)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	HeaderHash := encoding.Hash(bh)
+	if err := s.records.record(recordKey{Kind: voteRecordKind, Height: bh.Height, TimeoutCount: TimeoutCount}, HeaderHash); err != nil {
+		return hash.Hash256{}, err
+	}
+	return HeaderHash, nil
+}
+
+func (s *Server) signRoundVoteAck(vt *pof.RoundVoteAck) (common.Signature, error) {
+	if vt == nil {
+		return common.Signature{}, ErrInvalidRequest
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if vt.ChainID != s.chainID {
+		return common.Signature{}, ErrInvalidChainID
+	}
+	// the digest is the same as the evidence of observers, so timestamps and replies are not conflicts
+	Digest := encoding.Hash(&pof.RoundVoteAck{
+		ChainID:              vt.ChainID,
+		LastHash:             vt.LastHash,
+		TargetHeight:         vt.TargetHeight,
+		TimeoutCount:         vt.TimeoutCount,
+		Formulator:           vt.Formulator,
+		FormulatorPublicHash: vt.FormulatorPublicHash,
+	})
+	if err := s.records.record(recordKey{Kind: ackRecordKind, Height: vt.TargetHeight, TimeoutCount: vt.TimeoutCount}, Digest); err != nil {
+		return common.Signature{}, err
+	}
+	return s.key.Sign(encoding.Hash(vt))
+}
+
+// signRoundVote signs the round vote without recording because it is replaced by the timestamp and not binding
+func (s *Server) signRoundVote(vt *pof.RoundVote) (common.Signature, error) {
+	if vt == nil {
+		return common.Signature{}, ErrInvalidRequest
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if vt.ChainID != s.chainID {
+		return common.Signature{}, ErrInvalidChainID
+	}
+	if err := s.records.check(vt.TargetHeight); err != nil {
+		return common.Signature{}, err
+	}
+	return s.key.Sign(encoding.Hash(vt))
+}
+
+// signBlockGenRequest signs the block gen request without recording because it is not binding
+func (s *Server) signBlockGenRequest(req *pof.BlockGenRequest) (common.Signature, error) {
+	if req == nil {
+		return common.Signature{}, ErrInvalidRequest
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if req.ChainID != s.chainID {
+		return common.Signature{}, ErrInvalidChainID
+	}
+	if err := s.records.check(req.TargetHeight); err != nil {
+		return common.Signature{}, err
+	}
+	return s.key.Sign(encoding.Hash(req))
+}
+
+// signHandshake signs the handshake of meshes that starts with the chain id and has the timestamp after the random bytes
+func (s *Server) signHandshake(req []byte) (common.Signature, error) {
+	if len(req) != 40 && len(req) != 40+common.AddressSize {
+		return common.Signature{}, ErrInvalidHandshake
+	}
+	if req[0] != s.chainID {
+		return common.Signature{}, ErrInvalidChainID
+	}
+	timestamp := binary.LittleEndian.Uint64(req[32:])
+	diff := time.Duration(uint64(time.Now().UnixNano()) - timestamp)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > time.Second*30 {
+		return common.Signature{}, ErrInvalidHandshake
+	}
+	return s.key.Sign(hash.Hash(req))
+}
+
+// signTransaction signs the encoded transaction by the hash of the chain (same as chain.HashTransactionByType)
+func (s *Server) signTransaction(t uint16, data []byte) (common.Signature, error) {
+	if len(data) == 0 {
+		return common.Signature{}, ErrInvalidTransaction
+	}
+	r := bytes.NewReader(data)
+	if err := encoding.NewDecoder(r).Skip(); err != nil {
+		return common.Signature{}, ErrInvalidTransaction
+	}
+	if r.Len() != 0 {
+		return common.Signature{}, ErrInvalidTransaction
+	}
+
+	var buffer bytes.Buffer
+	enc := encoding.NewEncoder(&buffer)
+	if err := enc.EncodeUint8(s.chainID); err != nil {
+		return common.Signature{}, err
+	}
+	if err := enc.EncodeUint16(t); err != nil {
+		return common.Signature{}, err
+	}
+	buffer.Write(data)
+	return s.key.Sign(hash.Hash(buffer.Bytes()))
+}
+
+// proveVRF proves the VRF output of the seed, it is not recorded because the output of the seed is unique
+func (s *Server) proveVRF(alpha []byte) (hash.Hash256, []byte, error) {
+	if len(alpha) != hash.Hash256Size {
+		return hash.Hash256{}, nil, ErrInvalidRequest
+	}
+	vp, is := s.key.(pof.VRFProver)
+	if !is {
+		return hash.Hash256{}, nil, ErrNotSupported
+	}
+	return vp.ProveVRF(alpha)
+}
@@ -0,0 +1,185 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fletaio/fleta_testnet/common"
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/common/key"
+	"github.com/fletaio/fleta_testnet/common/vrf"
+	"github.com/fletaio/fleta_testnet/core/backend"
+	_ "github.com/fletaio/fleta_testnet/core/backend/buntdb_driver"
+	"github.com/fletaio/fleta_testnet/core/types"
+	"github.com/fletaio/fleta_testnet/encoding"
+	"github.com/fletaio/fleta_testnet/pof"
+)
+
+func newTestServer(t *testing.T, Key key.Key, path string) (*Server, func()) {
+	back, err := backend.Create("buntdb", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(Key, 1, back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, back.Close
+}
+
+func testHeader(t *testing.T, Height uint32, TimeoutCount uint32, Timestamp uint64) *types.Header {
+	var buffer bytes.Buffer
+	if err := encoding.NewEncoder(&buffer).EncodeUint32(TimeoutCount); err != nil {
+		t.Fatal(err)
+	}
+	return &types.Header{
+		ChainID:       1,
+		Height:        Height,
+		Timestamp:     Timestamp,
+		ConsensusData: buffer.Bytes(),
+	}
+}
+
+func TestServer_RecordsSurviveRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "record")
+
+	Key, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, closeBack := newTestServer(t, Key, path)
+	ack := &pof.RoundVoteAck{ChainID: 1, TargetHeight: 20, TimeoutCount: 0, Formulator: common.NewAddress(0, 1, 0)}
+	if _, err := s.handle(&Request{Kind: RoundVoteAckRequestKind, RoundVoteAck: ack}); err != nil {
+		t.Fatal(err)
+	}
+	closeBack()
+
+	s, closeBack = newTestServer(t, Key, path)
+	defer closeBack()
+
+	tests := []struct {
+		name string
+		req  *Request
+		err  error
+	}{
+		{"same ack by the other timestamp", &Request{Kind: RoundVoteAckRequestKind, RoundVoteAck: &pof.RoundVoteAck{ChainID: 1, TargetHeight: 20, Formulator: common.NewAddress(0, 1, 0), Timestamp: 1}}, nil},
+		{"conflicting ack", &Request{Kind: RoundVoteAckRequestKind, RoundVoteAck: &pof.RoundVoteAck{ChainID: 1, TargetHeight: 20, Formulator: common.NewAddress(0, 2, 0)}}, ErrDoubleSign},
+		{"ack of the next timeout", &Request{Kind: RoundVoteAckRequestKind, RoundVoteAck: &pof.RoundVoteAck{ChainID: 1, TargetHeight: 20, TimeoutCount: 1, Formulator: common.NewAddress(0, 2, 0)}}, nil},
+		{"ack of the other chain", &Request{Kind: RoundVoteAckRequestKind, RoundVoteAck: &pof.RoundVoteAck{ChainID: 2, TargetHeight: 21}}, ErrInvalidChainID},
+		{"round vote of the pruned height", &Request{Kind: RoundVoteRequestKind, RoundVote: &pof.RoundVote{ChainID: 1, TargetHeight: 9}}, ErrPastHeight},
+		{"round vote", &Request{Kind: RoundVoteRequestKind, RoundVote: &pof.RoundVote{ChainID: 1, TargetHeight: 21}}, nil},
+		{"empty block vote", &Request{Kind: BlockVoteRequestKind, BlockVote: &pof.BlockVote{TargetHeight: 20}}, ErrInvalidRequest},
+		{"unknown kind", &Request{Kind: RequestKind(2)}, ErrUnknownRequestKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.handle(tt.req); err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestServer_BlockVoteAndBlockSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	Key, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, closeBack := newTestServer(t, Key, filepath.Join(dir, "record"))
+	defer closeBack()
+
+	bh := testHeader(t, 5, 0, 1)
+	if _, err := s.handle(&Request{Kind: BlockVoteRequestKind, BlockVote: &pof.BlockVote{TargetHeight: 5, Header: bh}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.handle(&Request{Kind: BlockSignRequestKind, Header: *bh}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.handle(&Request{Kind: BlockSignRequestKind, Header: *testHeader(t, 5, 0, 2)}); err != ErrDoubleSign {
+		t.Fatalf("expected %v but %v", ErrDoubleSign, err)
+	}
+	if _, err := s.handle(&Request{Kind: HeaderRequestKind, Header: *testHeader(t, 5, 0, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.handle(&Request{Kind: HeaderRequestKind, Header: *testHeader(t, 5, 0, 3)}); err != ErrDoubleSign {
+		t.Fatalf("expected %v but %v", ErrDoubleSign, err)
+	}
+	if _, err := s.handle(&Request{Kind: HeaderRequestKind, Header: *testHeader(t, 4, 1, 3)}); err != ErrPastHeight {
+		t.Fatalf("expected %v but %v", ErrPastHeight, err)
+	}
+}
+
+func TestServer_Handshake(t *testing.T) {
+	Key, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{key: Key, chainID: 1}
+
+	newHandshake := func(ChainID uint8, Size int, Timestamp time.Time) []byte {
+		req := make([]byte, Size)
+		req[0] = ChainID
+		binary.LittleEndian.PutUint64(req[32:], uint64(Timestamp.UnixNano()))
+		return req
+	}
+	tests := []struct {
+		name string
+		req  []byte
+		err  error
+	}{
+		{"observer handshake", newHandshake(1, 40, time.Now()), nil},
+		{"formulator handshake", newHandshake(1, 40+common.AddressSize, time.Now()), nil},
+		{"other chain", newHandshake(2, 40, time.Now()), ErrInvalidChainID},
+		{"old timestamp", newHandshake(1, 40, time.Now().Add(-time.Minute)), ErrInvalidHandshake},
+		{"invalid size", newHandshake(1, 48, time.Now()), ErrInvalidHandshake},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := s.handle(&Request{Kind: HandshakeRequestKind, Handshake: tt.req})
+			if err != tt.err {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			if err == nil {
+				if pubkey, err := common.RecoverPubkey(hash.Hash(tt.req), sig); err != nil || pubkey != Key.PublicKey() {
+					t.Fatalf("invalid signature")
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ProveVRF(t *testing.T) {
+	Key, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{key: Key, chainID: 1}
+
+	Seed := hash.Hash([]byte("seed"))
+	Output, Proof, err := s.proveVRF(Seed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := vrf.Verify(Key.PublicKey(), Seed[:], Proof); err != nil || v != Output {
+		t.Fatalf("invalid proof %v", err)
+	}
+	if _, _, err := s.proveVRF([]byte("seed")); err != ErrInvalidRequest {
+		t.Fatalf("expected %v but %v", ErrInvalidRequest, err)
+	}
+}
@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"encoding/binary"
+
+	"github.com/fletaio/fleta_testnet/common/hash"
+	"github.com/fletaio/fleta_testnet/core/backend"
+)
+
+var tagSignRecord = []byte{1, 0}
+
+// record kinds
+const (
+	headerRecordKind = uint8(1)
+	voteRecordKind   = uint8(2) // block signs and block votes of the same header share the record
+	ackRecordKind    = uint8(3)
+)
+
+type recordKey struct {
+	Kind         uint8
+	Height       uint32
+	TimeoutCount uint32
+}
+
+// signRecords is the durable record of messages that are signed by the daemon
+// It is checked before every signature, so the daemon keeps refusing conflicting messages after the restart
+type signRecords struct {
+	back         backend.StoreBackend
+	recordMap    map[recordKey]hash.Hash256
+	maxHeightMap map[uint8]uint32
+	keepHeights  uint32
+}
+
+func loadSignRecords(back backend.StoreBackend) (*signRecords, error) {
+	sr := &signRecords{
+		back:         back,
+		recordMap:    map[recordKey]hash.Hash256{},
+		maxHeightMap: map[uint8]uint32{},
+		keepHeights:  10,
+	}
+	if err := back.View(func(txn backend.StoreReader) error {
+		return txn.Iterate(tagSignRecord, func(key []byte, value []byte) error {
+			rk, err := fromSignRecordKey(key)
+			if err != nil {
+				return err
+			}
+			var Digest hash.Hash256
+			copy(Digest[:], value)
+			sr.recordMap[rk] = Digest
+			if rk.Height > sr.maxHeightMap[rk.Kind] {
+				sr.maxHeightMap[rk.Kind] = rk.Height
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return sr, nil
+}
+
+// lowestHeight returns the lowest height that is checkable by the kept records
+func (sr *signRecords) lowestHeight() uint32 {
+	var MaxHeight uint32
+	for _, v := range sr.maxHeightMap {
+		if v > MaxHeight {
+			MaxHeight = v
+		}
+	}
+	if MaxHeight <= sr.keepHeights {
+		return 0
+	}
+	return MaxHeight - sr.keepHeights
+}
+
+// check returns an error when the height is already pruned
+func (sr *signRecords) check(Height uint32) error {
+	if Height < sr.lowestHeight() {
+		return ErrPastHeight
+	}
+	return nil
+}
+
+// record stores the digest of the message before it is signed
+// It fails when the different digest is recorded in the same round
+func (sr *signRecords) record(rk recordKey, Digest hash.Hash256) error {
+	if err := sr.check(rk.Height); err != nil {
+		return err
+	}
+	if old, has := sr.recordMap[rk]; has {
+		if old != Digest {
+			return ErrDoubleSign
+		}
+		return nil
+	}
+	if err := sr.back.Update(func(txn backend.StoreWriter) error {
+		return txn.Set(toSignRecordKey(rk), Digest[:])
+	}); err != nil {
+		return err
+	}
+	sr.recordMap[rk] = Digest
+	if rk.Height > sr.maxHeightMap[rk.Kind] {
+		sr.maxHeightMap[rk.Kind] = rk.Height
+		return sr.prune()
+	}
+	return nil
+}
+
+func (sr *signRecords) prune() error {
+	LowestHeight := sr.lowestHeight()
+	keys := [][]byte{}
+	for rk := range sr.recordMap {
+		if rk.Height < LowestHeight {
+			keys = append(keys, toSignRecordKey(rk))
+			delete(sr.recordMap, rk)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return sr.back.Update(func(txn backend.StoreWriter) error {
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func toSignRecordKey(rk recordKey) []byte {
+	bs := make([]byte, len(tagSignRecord)+9)
+	copy(bs, tagSignRecord)
+	idx := len(tagSignRecord)
+	bs[idx] = rk.Kind
+	binary.BigEndian.PutUint32(bs[idx+1:], rk.Height)
+	binary.BigEndian.PutUint32(bs[idx+5:], rk.TimeoutCount)
+	return bs
+}
+
+func fromSignRecordKey(key []byte) (recordKey, error) {
+	if len(key) != len(tagSignRecord)+9 {
+		return recordKey{}, ErrInvalidSignRecord
+	}
+	idx := len(tagSignRecord)
+	return recordKey{
+		Kind:         key[idx],
+		Height:       binary.BigEndian.Uint32(key[idx+1:]),
+		TimeoutCount: binary.BigEndian.Uint32(key[idx+5:]),
+	}, nil
+}
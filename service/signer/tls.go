@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+)
+
+// NewServerTLSConfig returns the TLS config of the daemon that requires client certificates signed by the CA
+func NewServerTLSConfig(CertFile string, KeyFile string, CAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(CertFile, KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := loadCertPool(CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// NewClientTLSConfig returns the TLS config of the node that verifies the daemon by the CA and the server name
+func NewClientTLSConfig(CertFile string, KeyFile string, CAFile string, ServerName string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(CertFile, KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := loadCertPool(CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   ServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func loadCertPool(CAFile string) (*x509.CertPool, error) {
+	bs, err := ioutil.ReadFile(CAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bs) {
+		return nil, ErrInvalidCertificate
+	}
+	return pool, nil
+}
+
+// Config defines the connection to the signer daemon in configurations of nodes
+type Config struct {
+	Network    string // tcp or unix
+	Address    string
+	ServerName string
+	CertFile   string
+	KeyFile    string
+	CAFile     string
+}
+
+// NewRemoteKeyFromConfig returns a RemoteKey that is connected by the config
+func NewRemoteKeyFromConfig(cfg *Config) (*RemoteKey, error) {
+	TLSConfig, err := NewClientTLSConfig(cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.ServerName)
+	if err != nil {
+		return nil, err
+	}
+	Network := cfg.Network
+	if len(Network) == 0 {
+		Network = "tcp"
+	}
+	return NewRemoteKey(Network, cfg.Address, TLSConfig)
+}